@@ -0,0 +1,218 @@
+package application
+
+import (
+	"errors"
+	"fmt"
+	"io/ioutil"
+	"os"
+	"path/filepath"
+
+	"github.com/AOEpeople/vistecture/v2/model/compose"
+	"github.com/AOEpeople/vistecture/v2/model/core"
+	"gopkg.in/yaml.v2"
+)
+
+type (
+	//ApplicationWriter - writes application definitions in the single application format (one file per application)
+	ApplicationWriter struct {
+		//Folder - where the definition files are written to
+		Folder string
+		//Merge - merge into existing definitions (found by application name in all definition files of the folder) instead of overwriting them (handwritten values are kept)
+		Merge bool
+	}
+)
+
+//WriteApplications - writes every application to <Folder>/<name>.yml and returns the written files.
+// With Merge an application that is already defined in the folder is merged into its definition file - the file is rewritten, so comments and the order of keys are lost.
+// Applications defined in files with other applications (or in docker-compose files) cannot be rewritten without touching the others - they are returned as skipped
+func (w *ApplicationWriter) WriteApplications(applications []*core.Application) ([]string, []string, error) {
+	if err := os.MkdirAll(w.Folder, 0755); err != nil {
+		return nil, nil, err
+	}
+	var definitions map[string]*core.Application
+	applicationsPerFile := make(map[string]int)
+	if w.Merge {
+		var err error
+		if definitions, err = w.loadDefinitions(); err != nil {
+			return nil, nil, err
+		}
+		for _, definition := range definitions {
+			applicationsPerFile[definition.Source.File]++
+		}
+	}
+	var written, skipped []string
+	for _, application := range applications {
+		fileName := filepath.Join(w.Folder, application.Name+".yml")
+		if existing, found := definitions[application.Name]; found {
+			fileName = existing.Source.File
+			if fileName == "" || applicationsPerFile[fileName] > 1 || compose.IsComposeFile(fileName) {
+				skipped = append(skipped, fmt.Sprintf("%v (defined in %v together with other applications - merge it by hand)", application.Name, existing.Source))
+				continue
+			}
+			application = MergeApplication(existing, application)
+		}
+		content, err := MarshalApplication(application)
+		if err != nil {
+			return written, skipped, err
+		}
+		if err := ioutil.WriteFile(fileName, content, 0644); err != nil {
+			return written, skipped, errors.New(fmt.Sprintf("Cannot write application definition %v: %v", fileName, err))
+		}
+		written = append(written, fileName)
+	}
+	return written, skipped, nil
+}
+
+// loadDefinitions - the applications defined in the folder by name. Definitions that cannot be loaded are an error - they could be duplicated otherwise
+func (w *ApplicationWriter) loadDefinitions() (map[string]*core.Application, error) {
+	definitions := make(map[string]*core.Application)
+	if files, _ := ioutil.ReadDir(w.Folder); len(files) == 0 {
+		return definitions, nil
+	}
+	loader := ProjectLoader{}
+	applications, err := loader.LoadApplications(w.Folder)
+	if errs := AllErrors(err); len(errs) > 0 {
+		return nil, errors.New(fmt.Sprintf("Cannot merge into the definitions in %v: %v", w.Folder, errs))
+	}
+	for _, application := range applications {
+		definitions[application.Name] = application
+	}
+	return definitions, nil
+}
+
+//MergeApplication - returns a copy of the existing application completed by the imported one. Values of the existing application are kept,
+//missing services, dependencies, infrastructure dependencies and properties are added
+func MergeApplication(existing *core.Application, imported *core.Application) *core.Application {
+	merged := existing.Clone()
+	fillString(&merged.Title, imported.Title)
+	fillString(&merged.Summary, imported.Summary)
+	fillString(&merged.Description, imported.Description)
+	fillString(&merged.Team, imported.Team)
+	fillString(&merged.Group, imported.Group)
+	fillString(&merged.Technology, imported.Technology)
+	fillString(&merged.Category, imported.Category)
+	if merged.Status == "" {
+		merged.Status = imported.Status
+	}
+
+	for _, importedService := range imported.ProvidedServices {
+		found := false
+		for i := range merged.ProvidedServices {
+			service := &merged.ProvidedServices[i]
+			if service.Name != importedService.Name {
+				continue
+			}
+			found = true
+			if service.Type == "" {
+				service.Type = importedService.Type
+			}
+			fillString(&service.Summary, importedService.Summary)
+			service.IsPublic = service.IsPublic || importedService.IsPublic
+		}
+		if !found {
+			merged.ProvidedServices = append(merged.ProvidedServices, importedService.Clone())
+		}
+	}
+
+	for _, importedDependency := range imported.Dependencies {
+		found := false
+		for _, dependency := range merged.Dependencies {
+			if dependency.Reference == importedDependency.Reference {
+				found = true
+			}
+		}
+		if !found {
+			merged.Dependencies = append(merged.Dependencies, importedDependency.Clone())
+		}
+	}
+
+	for _, importedInfrastructure := range imported.InfrastructureDependencies {
+		found := false
+		for _, infrastructure := range merged.InfrastructureDependencies {
+			if infrastructure.Type == importedInfrastructure.Type {
+				found = true
+			}
+		}
+		if !found {
+			merged.InfrastructureDependencies = append(merged.InfrastructureDependencies, importedInfrastructure)
+		}
+	}
+
+	for key, value := range imported.Properties {
+		if _, found := merged.Properties[key]; found {
+			continue
+		}
+		if merged.Properties == nil {
+			merged.Properties = make(map[string]string)
+		}
+		merged.Properties[key] = value
+	}
+	return merged
+}
+
+// fillString - sets the value if the target is empty
+func fillString(target *string, value string) {
+	if *target == "" {
+		*target = value
+	}
+}
+
+//MarshalApplication - returns the definition of the application as YAML. Empty values and the generated id are omitted so the result reads like a handwritten definition
+func MarshalApplication(application *core.Application) ([]byte, error) {
+	marshalled, err := yaml.Marshal(application)
+	if err != nil {
+		return nil, err
+	}
+	var definition yaml.MapSlice
+	if err := yaml.Unmarshal(marshalled, &definition); err != nil {
+		return nil, err
+	}
+	var withoutId yaml.MapSlice
+	for _, item := range definition {
+		if item.Key != "id" {
+			withoutId = append(withoutId, item)
+		}
+	}
+	return yaml.Marshal(withoutEmptyValues(withoutId))
+}
+
+// withoutEmptyValues - recursively removes empty strings, false, zero numbers, empty lists and empty maps
+func withoutEmptyValues(value interface{}) interface{} {
+	switch typedValue := value.(type) {
+	case yaml.MapSlice:
+		var result yaml.MapSlice
+		for _, item := range typedValue {
+			if cleaned := withoutEmptyValues(item.Value); cleaned != nil {
+				result = append(result, yaml.MapItem{Key: item.Key, Value: cleaned})
+			}
+		}
+		if len(result) == 0 {
+			return nil
+		}
+		return result
+	case []interface{}:
+		var result []interface{}
+		for _, item := range typedValue {
+			if cleaned := withoutEmptyValues(item); cleaned != nil {
+				result = append(result, cleaned)
+			}
+		}
+		if len(result) == 0 {
+			return nil
+		}
+		return result
+	case string:
+		if typedValue == "" {
+			return nil
+		}
+	case bool:
+		if !typedValue {
+			return nil
+		}
+	case int:
+		if typedValue == 0 {
+			return nil
+		}
+	}
+	return value
+}
@@ -0,0 +1,94 @@
+package application_test
+
+import (
+	"io/ioutil"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+
+	"github.com/AOEpeople/vistecture/v2/application"
+	"github.com/AOEpeople/vistecture/v2/model/core"
+)
+
+func TestApplicationWriter_WriteApplications(t *testing.T) {
+	applications := []*core.Application{
+		{
+			Id:               3,
+			Name:             "checkout",
+			Team:             "team1",
+			ProvidedServices: []core.Service{{Name: "api", Type: core.SERVICE_TYPE_API}},
+			Dependencies:     []core.Dependency{{Reference: "payment.auth", Relationship: core.RELATIONSHIP_ACL}},
+		},
+	}
+	writer := application.ApplicationWriter{Folder: t.TempDir()}
+	written, _, err := writer.WriteApplications(applications)
+	if err != nil || len(written) != 1 {
+		t.Fatalf("Expected one written file, got %v %v", written, err)
+	}
+
+	content, _ := application.MarshalApplication(applications[0])
+	expected := "name: checkout\nteam: team1\nprovided-services:\n- name: api\n  type: api\ndependencies:\n- reference: payment.auth\n  relationship: acl\n"
+	if string(content) != expected {
+		t.Errorf("Expected definition without empty values:\n%v\ngot:\n%v", expected, string(content))
+	}
+
+	loader := application.ProjectLoader{StrictMode: true}
+	loaded, err := loader.LoadApplications(written[0])
+	if errs := application.AllErrors(err); len(errs) > 0 {
+		t.Fatal(errs)
+	}
+	if len(loaded) != 1 || loaded[0].Name != "checkout" || loaded[0].Dependencies[0].Relationship != core.RELATIONSHIP_ACL {
+		t.Errorf("Expected written definition to be loadable, got %#v", loaded)
+	}
+}
+
+func TestApplicationWriter_Merge(t *testing.T) {
+	folder := t.TempDir()
+	writer := application.ApplicationWriter{Folder: folder, Merge: true}
+	handwritten := "name: orders\nsummary: Handwritten\nprovided-services:\n- name: api\n  description: Handwritten service\nproperties:\n  owner: alice\n  app: old\n"
+	if err := ioutil.WriteFile(filepath.Join(folder, "orders.yaml"), []byte(handwritten), 0644); err != nil {
+		t.Fatal(err)
+	}
+	if err := ioutil.WriteFile(filepath.Join(folder, "shop.yml"), []byte("applications:\n- name: shop\n- name: cart\n"), 0644); err != nil {
+		t.Fatal(err)
+	}
+
+	imported := &core.Application{
+		Name:                       "orders",
+		Summary:                    "Imported",
+		Technology:                 "go",
+		ProvidedServices:           []core.Service{{Name: "api", Type: core.SERVICE_TYPE_API}, {Name: "ui", Type: core.SERVICE_TYPE_GUI}},
+		InfrastructureDependencies: []core.InfrastructureDependency{{Type: "postgres"}},
+		Properties:                 map[string]string{"app": "orders", "image": "orders:1.0"},
+	}
+	written, skipped, err := writer.WriteApplications([]*core.Application{imported, {Name: "cart", Technology: "php"}})
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(written) != 1 || written[0] != filepath.Join(folder, "orders.yaml") {
+		t.Fatalf("Expected the handwritten orders.yaml to be merged, got %v", written)
+	}
+	if len(skipped) != 1 || !strings.HasPrefix(skipped[0], "cart ") {
+		t.Errorf("Expected cart of the multi application file to be skipped, got %v", skipped)
+	}
+	if _, err := os.Stat(filepath.Join(folder, "cart.yml")); err == nil {
+		t.Error("Expected no duplicated definition of cart")
+	}
+
+	loader := application.ProjectLoader{StrictMode: true}
+	loaded, err := loader.LoadApplications(written[0])
+	if errs := application.AllErrors(err); len(errs) > 0 {
+		t.Fatal(errs)
+	}
+	merged := loaded[0]
+	if merged.Summary != "Handwritten" || merged.Technology != "go" {
+		t.Errorf("Expected handwritten summary and imported technology, got %#v", merged)
+	}
+	if len(merged.ProvidedServices) != 2 || merged.ProvidedServices[0].Description != "Handwritten service" || merged.ProvidedServices[0].Type != core.SERVICE_TYPE_API {
+		t.Errorf("Expected completed handwritten service and added ui, got %#v", merged.ProvidedServices)
+	}
+	if len(merged.InfrastructureDependencies) != 1 || merged.Properties["owner"] != "alice" || merged.Properties["app"] != "old" || merged.Properties["image"] != "orders:1.0" {
+		t.Errorf("Expected added infrastructure and the handwritten properties completed, got %#v", merged)
+	}
+}
@@ -2,8 +2,11 @@ package application
 
 import (
 	"errors"
+	"fmt"
+	"path"
 	"strings"
 
+	"github.com/AOEpeople/vistecture/v2/model/analyze"
 	"github.com/AOEpeople/vistecture/v2/model/core"
 )
 
@@ -13,26 +16,67 @@ type (
 		AppDefinitionsPaths []string                `json:"appDefinitionsPaths" yaml:"appDefinitionsPaths"`
 		ProjectName         string                  `json:"projectName" yaml:"projectName"`
 		AppOverrides        []*ApplicationOverrides `json:"appOverrides" yaml:"appOverrides"`
+		//Rules - architecture rules that are checked by the analyze command
+		Rules []*analyze.Rule `json:"rules" yaml:"rules"`
+		//Vocabulary - project specific relationships, statuses, service types and categories (in addition to the default ones)
+		Vocabulary *core.Vocabulary `json:"vocabulary,omitempty" yaml:"vocabulary,omitempty"`
 	}
 	SubViewConfig struct {
 		Name                string   `json:"name" yaml:"name" `
 		IncludedApplication []string `json:"included-applications" yaml:"included-applications"`
+		//Include - applications matching one of the selectors are part of the subview (in addition to the included-applications)
+		Include []*SubViewSelector `json:"include,omitempty" yaml:"include,omitempty"`
+		//Exclude - applications matching one of the selectors are removed from the subview. If nothing is included, all applications except the excluded are part of the subview
+		Exclude []*SubViewSelector `json:"exclude,omitempty" yaml:"exclude,omitempty"`
 	}
+	//SubViewSelector - selects applications by their attributes and optionally the applications around them
+	SubViewSelector struct {
+		core.ApplicationSelector `yaml:",inline"`
+		//Dependencies - also select the dependencies of the matched applications up to this number of hops (-1 for all)
+		Dependencies int `json:"dependencies,omitempty" yaml:"dependencies,omitempty"`
+		//Dependents - also select the applications depending on the matched applications up to this number of hops (-1 for all)
+		Dependents int `json:"dependents,omitempty" yaml:"dependents,omitempty"`
+	}
+	//ApplicationOverrides - patch for a loaded application. Removals are applied to the loaded application before the additions
 	ApplicationOverrides struct {
 		//Name - is used to reference
 		Name string `json:"name" yaml:"name"`
 		//Title and all other attributes are supposed to override or extend the properties of the referenced application
-		Title                  string            `json:"title,omitempty" yaml:"title,omitempty"`
-		Summary                string            `json:"summary,omitempty" yaml:"summary,omitempty"`
-		Description            string            `json:"description,omitempty" yaml:"description,omitempty"`
-		Group                  string            `json:"group,omitempty" yaml:"group,omitempty"`
-		Technology             string            `json:"technology,omitempty" yaml:"technology,omitempty"`
-		Category               string            `json:"category,omitempty" yaml:"category,omitempty"`
-		AddProvidedServices    []core.Service    `json:"add-provided-services" yaml:"add-provided-services"`
-		RemoveProvidedServices []string          `json:"remove-provided-services" yaml:"remove-provided-services"`
-		AddDependencies        []core.Dependency `json:"add-dependencies" yaml:"add-dependencies"`
-		RemoveDependencies     []string          `json:"remove-dependencies" yaml:"remove-dependencies"`
-		Properties             map[string]string `json:"properties" yaml:"properties"`
+		Title                            string                           `json:"title,omitempty" yaml:"title,omitempty"`
+		Summary                          string                           `json:"summary,omitempty" yaml:"summary,omitempty"`
+		Description                      string                           `json:"description,omitempty" yaml:"description,omitempty"`
+		Team                             string                           `json:"team,omitempty" yaml:"team,omitempty"`
+		Group                            string                           `json:"group,omitempty" yaml:"group,omitempty"`
+		Technology                       string                           `json:"technology,omitempty" yaml:"technology,omitempty"`
+		Category                         string                           `json:"category,omitempty" yaml:"category,omitempty"`
+		Status                           core.Status                      `json:"status,omitempty" yaml:"status,omitempty"`
+		Display                          *core.ApplicationDisplaySettings `json:"display,omitempty" yaml:"display,omitempty"`
+		AddProvidedServices              []core.Service                   `json:"add-provided-services" yaml:"add-provided-services"`
+		RemoveProvidedServices           []string                         `json:"remove-provided-services" yaml:"remove-provided-services"`
+		OverrideProvidedServices         []*ServiceOverrides              `json:"override-provided-services" yaml:"override-provided-services"`
+		AddDependencies                  []core.Dependency                `json:"add-dependencies" yaml:"add-dependencies"`
+		RemoveDependencies               []string                         `json:"remove-dependencies" yaml:"remove-dependencies"`
+		AddInfrastructureDependencies    []core.InfrastructureDependency  `json:"add-infrastructure-dependencies" yaml:"add-infrastructure-dependencies"`
+		RemoveInfrastructureDependencies []string                         `json:"remove-infrastructure-dependencies" yaml:"remove-infrastructure-dependencies"`
+		Properties                       map[string]string                `json:"properties" yaml:"properties"`
+		RemoveProperties                 []string                         `json:"remove-properties" yaml:"remove-properties"`
+	}
+	//ServiceOverrides - patch for one provided service of the overridden application
+	ServiceOverrides struct {
+		//Name - the service to change
+		Name               string            `json:"name" yaml:"name"`
+		Title              string            `json:"title,omitempty" yaml:"title,omitempty"`
+		Summary            string            `json:"summary,omitempty" yaml:"summary,omitempty"`
+		Description        string            `json:"description,omitempty" yaml:"description,omitempty"`
+		Type               core.ServiceType  `json:"type,omitempty" yaml:"type,omitempty"`
+		Status             core.Status       `json:"status,omitempty" yaml:"status,omitempty"`
+		SecurityLevel      string            `json:"securityLevel,omitempty" yaml:"securityLevel,omitempty"`
+		IsPublic           *bool             `json:"isPublic,omitempty" yaml:"isPublic,omitempty"`
+		IsOpenHost         *bool             `json:"isOpenHost,omitempty" yaml:"isOpenHost,omitempty"`
+		AddDependencies    []core.Dependency `json:"add-dependencies" yaml:"add-dependencies"`
+		RemoveDependencies []string          `json:"remove-dependencies" yaml:"remove-dependencies"`
+		Properties         map[string]string `json:"properties" yaml:"properties"`
+		RemoveProperties   []string          `json:"remove-properties" yaml:"remove-properties"`
 	}
 )
 
@@ -46,6 +90,11 @@ func (p *SubViewConfig) validate() []error {
 	if strings.Contains(p.Name, ".") {
 		foundErrors = append(foundErrors, errors.New("project config name contains '.'"))
 	}
+	for _, selector := range append(append([]*SubViewSelector{}, p.Include...), p.Exclude...) {
+		if _, err := path.Match(selector.Name, ""); err != nil {
+			foundErrors = append(foundErrors, errors.New(fmt.Sprintf("subview '%v' has an invalid name pattern '%v': %v", p.Name, selector.Name, err)))
+		}
+	}
 	return foundErrors
 }
 
@@ -56,10 +105,20 @@ func (p *ProjectConfig) Validate() []error {
 	for _, subView := range p.SubViewConfig {
 		foundErrors = append(foundErrors, subView.validate()...)
 	}
+	for _, rule := range p.Rules {
+		if err := rule.Validate(); err != nil {
+			foundErrors = append(foundErrors, err)
+		}
+	}
 
 	return foundErrors
 }
 
+//GetVocabulary - the default vocabulary extended with the project specific values
+func (p *ProjectConfig) GetVocabulary() *core.Vocabulary {
+	return core.DefaultVocabulary().Extend(p.Vocabulary)
+}
+
 //FindSubViewConfigByName - Find project info by Name
 func (p *ProjectConfig) FindSubViewConfigByName(nameToMatch string) (*SubViewConfig, error) {
 	for _, subView := range p.SubViewConfig {
@@ -70,70 +129,189 @@ func (p *ProjectConfig) FindSubViewConfigByName(nameToMatch string) (*SubViewCon
 	return nil, errors.New("project info with name '" + nameToMatch + "' not found")
 }
 
-//GetAdjustedApplication - Merges the given application with another. The current application is the one who will be modified.
+//GetAdjustedApplication - returns a copy of the given application with the overrides applied. The passed application is not modified
 func (a *ApplicationOverrides) GetAdjustedApplication(application *core.Application) (*core.Application, error) {
-	//clone the passed application
-	newApplication := *application
+	newApplication := application.Clone()
 
-	//override properties if set in the ApplicationReference
 	if a.Name != "" {
 		newApplication.Name = a.Name
 	}
-	if a.Category != "" {
-		newApplication.Category = a.Category
+	overrideString(&newApplication.Title, a.Title)
+	overrideString(&newApplication.Summary, a.Summary)
+	overrideString(&newApplication.Description, a.Description)
+	overrideString(&newApplication.Team, a.Team)
+	overrideString(&newApplication.Group, a.Group)
+	overrideString(&newApplication.Technology, a.Technology)
+	overrideString(&newApplication.Category, a.Category)
+	if a.Status != "" {
+		newApplication.Status = a.Status
 	}
-	if a.Description != "" {
-		newApplication.Description = a.Description
+	if a.Display != nil {
+		newApplication.Display = *a.Display
 	}
-	if a.AddDependencies != nil {
-		newApplication.Dependencies = append(application.Dependencies, a.AddDependencies...)
+
+	newApplication.Dependencies = append(removeDependencies(newApplication.Dependencies, a.RemoveDependencies), cloneDependencies(a.AddDependencies)...)
+
+	var infrastructureDependencies []core.InfrastructureDependency
+	for _, infrastructureDependency := range newApplication.InfrastructureDependencies {
+		if !inSlice(infrastructureDependency.Type, a.RemoveInfrastructureDependencies) {
+			infrastructureDependencies = append(infrastructureDependencies, infrastructureDependency)
+		}
 	}
-	if a.RemoveDependencies != nil {
-		for currentKey, currentDep := range newApplication.Dependencies {
-			for _, remove := range a.RemoveDependencies {
-				if remove == currentDep.GetApplicationName() {
-					newApplication.Dependencies = append(newApplication.Dependencies[:currentKey], newApplication.Dependencies[currentKey+1:]...)
-				}
-			}
+	newApplication.InfrastructureDependencies = append(infrastructureDependencies, a.AddInfrastructureDependencies...)
+
+	var services []core.Service
+	for _, service := range newApplication.ProvidedServices {
+		if !inSlice(service.Name, a.RemoveProvidedServices) {
+			services = append(services, service)
 		}
 	}
-	if a.AddProvidedServices != nil {
-		newApplication.ProvidedServices = append(application.ProvidedServices, a.AddProvidedServices...)
+	for _, service := range a.AddProvidedServices {
+		services = append(services, service.Clone())
 	}
-	if a.RemoveProvidedServices != nil {
-		for currentKey, currentService := range newApplication.ProvidedServices {
-			for _, remove := range a.RemoveProvidedServices {
-				if remove == currentService.Name {
-					newApplication.ProvidedServices = append(newApplication.ProvidedServices[:currentKey], newApplication.ProvidedServices[currentKey+1:]...)
-				}
+	newApplication.ProvidedServices = services
+
+	for _, serviceOverride := range a.OverrideProvidedServices {
+		found := false
+		for i := range newApplication.ProvidedServices {
+			if newApplication.ProvidedServices[i].Name == serviceOverride.Name {
+				serviceOverride.apply(&newApplication.ProvidedServices[i])
+				found = true
 			}
 		}
+		if !found {
+			return nil, errors.New(fmt.Sprintf("Override for application %v: service %v not found", application.Name, serviceOverride.Name))
+		}
 	}
-	if a.Category != "" {
-		newApplication.Category = a.Category
+
+	newApplication.Properties = overrideProperties(newApplication.Properties, a.Properties, a.RemoveProperties)
+	return newApplication, nil
+}
+
+// apply - changes the service (that is already a copy owned by the adjusted application)
+func (s *ServiceOverrides) apply(service *core.Service) {
+	overrideString(&service.Title, s.Title)
+	overrideString(&service.Summary, s.Summary)
+	overrideString(&service.Description, s.Description)
+	overrideString(&service.SecurityLevel, s.SecurityLevel)
+	if s.Type != "" {
+		service.Type = s.Type
 	}
-	if a.Group != "" {
-		newApplication.Group = a.Group
+	if s.Status != "" {
+		service.Status = s.Status
 	}
-	if a.Properties != nil {
-		if newApplication.Properties == nil {
-			newApplication.Properties = make(map[string]string)
+	if s.IsPublic != nil {
+		service.IsPublic = *s.IsPublic
+	}
+	if s.IsOpenHost != nil {
+		service.IsOpenHost = *s.IsOpenHost
+	}
+	service.Dependencies = append(removeDependencies(service.Dependencies, s.RemoveDependencies), cloneDependencies(s.AddDependencies)...)
+	service.Properties = overrideProperties(service.Properties, s.Properties, s.RemoveProperties)
+}
+
+func overrideString(target *string, value string) {
+	if value != "" {
+		*target = value
+	}
+}
+
+// removeDependencies - returns the dependencies that reference none of the given applications or services ("app" removes all dependencies to app, "app.service" only the one to the service)
+func removeDependencies(dependencies []core.Dependency, references []string) []core.Dependency {
+	if len(references) == 0 {
+		return dependencies
+	}
+	var result []core.Dependency
+	for _, dependency := range dependencies {
+		if !inSlice(dependency.GetApplicationName(), references) && !inSlice(dependency.Reference, references) {
+			result = append(result, dependency)
 		}
-		for k, v := range a.Properties {
-			newApplication.Properties[k] = v
+	}
+	return result
+}
+
+func cloneDependencies(dependencies []core.Dependency) []core.Dependency {
+	var result []core.Dependency
+	for _, dependency := range dependencies {
+		result = append(result, dependency.Clone())
+	}
+	return result
+}
+
+// overrideProperties - removes and then sets the given properties (properties is already a copy owned by the adjusted application)
+func overrideProperties(properties map[string]string, set map[string]string, remove []string) map[string]string {
+	for _, key := range remove {
+		delete(properties, key)
+	}
+	if len(set) == 0 {
+		return properties
+	}
+	if properties == nil {
+		properties = make(map[string]string)
+	}
+	for k, v := range set {
+		properties[k] = v
+	}
+	return properties
+}
+
+func inSlice(value string, values []string) bool {
+	for _, v := range values {
+		if v == value {
+			return true
 		}
 	}
-	return &newApplication, nil
+	return false
 }
 
+//GetMatchedApps - returns the applications of the subview (in the order of the given applications): the included applications and the applications matching the include selectors without the ones matching the exclude selectors
 func (s *SubViewConfig) GetMatchedApps(apps []*core.Application) []*core.Application {
+	graph := core.CreateGraph(&core.Project{Applications: apps})
+
+	included := make(map[string]bool)
+	for _, includedAppName := range s.IncludedApplication {
+		included[includedAppName] = true
+	}
+	for _, selector := range s.Include {
+		selector.selectApplications(graph, included)
+	}
+	includeAll := len(s.IncludedApplication) == 0 && len(s.Include) == 0 && len(s.Exclude) > 0
+
+	excluded := make(map[string]bool)
+	for _, selector := range s.Exclude {
+		selector.selectApplications(graph, excluded)
+	}
+
 	var matchingApps []*core.Application
 	for _, app := range apps {
-		for _, includedAppName := range s.IncludedApplication {
-			if includedAppName == app.Name {
-				matchingApps = append(matchingApps, app)
-			}
+		if (includeAll || included[app.Name]) && !excluded[app.Name] {
+			matchingApps = append(matchingApps, app)
 		}
 	}
 	return matchingApps
 }
+
+// selectApplications - adds the names of the matching applications and their dependencies / dependents to selected
+func (s *SubViewSelector) selectApplications(graph *core.Graph, selected map[string]bool) {
+	for _, app := range graph.Applications() {
+		if !s.Matches(app) {
+			continue
+		}
+		selected[app.Name] = true
+		for _, neighbourhood := range []struct {
+			hops      int
+			direction core.GraphDirection
+		}{{s.Dependencies, core.DOWNSTREAM}, {s.Dependents, core.UPSTREAM}} {
+			if neighbourhood.hops == 0 {
+				continue
+			}
+			maxDepth := neighbourhood.hops
+			if maxDepth < 0 {
+				maxDepth = 0
+			}
+			for _, reached := range graph.Walk(app.Name, neighbourhood.direction, maxDepth, nil) {
+				selected[reached.Application.Name] = true
+			}
+		}
+	}
+}
@@ -0,0 +1,123 @@
+package application_test
+
+import (
+	"strings"
+	"testing"
+
+	"github.com/AOEpeople/vistecture/v2/application"
+	"github.com/AOEpeople/vistecture/v2/model/core"
+)
+
+func TestApplicationOverrides_GetAdjustedApplication(t *testing.T) {
+
+	original := &core.Application{
+		Name:       "app1",
+		Team:       "team1",
+		Properties: map[string]string{"key": "value", "old": "value"},
+		Dependencies: []core.Dependency{
+			{Reference: "app2"},
+			{Reference: "app2.api"},
+			{Reference: "app3"},
+		},
+		ProvidedServices: []core.Service{
+			{Name: "api", Dependencies: []core.Dependency{{Reference: "app3"}, {Reference: "app4"}}},
+			{Name: "old"},
+			{Name: "older"},
+		},
+		InfrastructureDependencies: []core.InfrastructureDependency{{Type: "mysql"}, {Type: "redis"}},
+	}
+	isPublic := true
+	override := application.ApplicationOverrides{
+		Name:                             "app1",
+		Title:                            "Application 1",
+		Technology:                       "go",
+		Team:                             "team2",
+		Status:                           core.STATUS_PLANNED,
+		RemoveDependencies:               []string{"app2"},
+		AddDependencies:                  []core.Dependency{{Reference: "app5"}},
+		RemoveProvidedServices:           []string{"old", "older"},
+		RemoveInfrastructureDependencies: []string{"mysql"},
+		OverrideProvidedServices: []*application.ServiceOverrides{
+			{Name: "api", IsPublic: &isPublic, RemoveDependencies: []string{"app3"}, AddDependencies: []core.Dependency{{Reference: "app6"}}},
+		},
+		Properties:       map[string]string{"key": "new"},
+		RemoveProperties: []string{"old"},
+	}
+
+	adjusted, err := override.GetAdjustedApplication(original)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if adjusted.Title != "Application 1" || adjusted.Technology != "go" || adjusted.Team != "team2" || adjusted.Status != core.STATUS_PLANNED {
+		t.Error("Expected title, technology, team and status to be overridden", adjusted)
+	}
+	if len(adjusted.Dependencies) != 2 || adjusted.Dependencies[0].Reference != "app3" || adjusted.Dependencies[1].Reference != "app5" {
+		t.Error("Expected both dependencies to app2 to be removed and app5 to be added", adjusted.Dependencies)
+	}
+	if len(adjusted.ProvidedServices) != 1 || !adjusted.ProvidedServices[0].IsPublic {
+		t.Error("Expected consecutive services to be removed and api to be public", adjusted.ProvidedServices)
+	}
+	if deps := adjusted.ProvidedServices[0].Dependencies; len(deps) != 2 || deps[0].Reference != "app4" || deps[1].Reference != "app6" {
+		t.Error("Expected the service dependencies to be changed", deps)
+	}
+	if len(adjusted.InfrastructureDependencies) != 1 || adjusted.InfrastructureDependencies[0].Type != "redis" {
+		t.Error("Expected mysql to be removed", adjusted.InfrastructureDependencies)
+	}
+	if adjusted.Properties["key"] != "new" || len(adjusted.Properties) != 1 {
+		t.Error("Expected properties to be merged", adjusted.Properties)
+	}
+
+	//the original application is unchanged
+	if len(original.Dependencies) != 3 || len(original.ProvidedServices) != 3 || len(original.ProvidedServices[0].Dependencies) != 2 || original.ProvidedServices[0].IsPublic || original.Properties["key"] != "value" || len(original.Properties) != 2 {
+		t.Error("Expected the original application not to be modified", original)
+	}
+
+	if _, err := (&application.ApplicationOverrides{OverrideProvidedServices: []*application.ServiceOverrides{{Name: "unknown"}}}).GetAdjustedApplication(original); err == nil {
+		t.Error("Expected error for an override of an unknown service")
+	}
+}
+
+func TestSubViewConfig_GetMatchedApps(t *testing.T) {
+
+	apps := []*core.Application{
+		{Name: "shop", Team: "team1", Group: "frontend", Dependencies: []core.Dependency{{Reference: "checkout"}}},
+		{Name: "checkout", Team: "team1", Group: "backend/order", Dependencies: []core.Dependency{{Reference: "payment"}}},
+		{Name: "payment", Team: "team2", Group: "backend/payment", Category: core.CATEGORY_EXTERNAL, Dependencies: []core.Dependency{{Reference: "bank"}}},
+		{Name: "bank", Team: "team3", Category: core.CATEGORY_EXTERNAL},
+		{Name: "search", Team: "team2", Group: "backend", Status: core.STATUS_PLANNED},
+	}
+	names := func(apps []*core.Application) string {
+		var result []string
+		for _, app := range apps {
+			result = append(result, app.Name)
+		}
+		return strings.Join(result, ",")
+	}
+
+	for _, testCase := range []struct {
+		subView  application.SubViewConfig
+		expected string
+	}{
+		{application.SubViewConfig{IncludedApplication: []string{"bank", "shop"}}, "shop,bank"},
+		{application.SubViewConfig{Include: []*application.SubViewSelector{{ApplicationSelector: core.ApplicationSelector{Group: "backend"}}}}, "checkout,payment,search"},
+		{application.SubViewConfig{Include: []*application.SubViewSelector{{ApplicationSelector: core.ApplicationSelector{Team: "team2"}}}, Exclude: []*application.SubViewSelector{{ApplicationSelector: core.ApplicationSelector{Status: core.STATUS_PLANNED}}}}, "payment"},
+		{application.SubViewConfig{Include: []*application.SubViewSelector{{ApplicationSelector: core.ApplicationSelector{Name: "checkout"}, Dependencies: 1, Dependents: -1}}}, "shop,checkout,payment"},
+		{application.SubViewConfig{Exclude: []*application.SubViewSelector{{ApplicationSelector: core.ApplicationSelector{Category: core.CATEGORY_EXTERNAL}}}}, "shop,checkout,search"},
+		{application.SubViewConfig{IncludedApplication: []string{"search"}, Include: []*application.SubViewSelector{{ApplicationSelector: core.ApplicationSelector{Name: "s*"}}}}, "shop,search"},
+	} {
+		if matched := names(testCase.subView.GetMatchedApps(apps)); matched != testCase.expected {
+			t.Errorf("expected %v got %v", testCase.expected, matched)
+		}
+	}
+}
+
+func TestProjectConfig_ValidateSubViewPatterns(t *testing.T) {
+	config := application.ProjectConfig{SubViewConfig: []*application.SubViewConfig{
+		{Name: "valid", Include: []*application.SubViewSelector{{ApplicationSelector: core.ApplicationSelector{Name: "order-*"}}}},
+		{Name: "broken", Exclude: []*application.SubViewSelector{{ApplicationSelector: core.ApplicationSelector{Name: "order-[*"}}}},
+	}}
+	errs := config.Validate()
+	if len(errs) != 1 || !strings.Contains(errs[0].Error(), "subview 'broken' has an invalid name pattern 'order-[*'") {
+		t.Errorf("Expected an error for the pattern of the broken subview, got %v", errs)
+	}
+}
@@ -10,13 +10,18 @@ import (
 	"path/filepath"
 	"strings"
 
+	"github.com/AOEpeople/vistecture/v2/model/compose"
 	"github.com/AOEpeople/vistecture/v2/model/core"
+	"github.com/AOEpeople/vistecture/v2/model/jsonschema"
 	yaml "gopkg.in/yaml.v2"
 )
 
 type (
 	ProjectLoader struct {
 		StrictMode bool
+		//SchemaValidation - validates every definition file against the JSON schema (see ApplicationSchema and ProjectConfigSchema)
+		SchemaValidation  bool
+		applicationSchema *jsonschema.Schema
 	}
 	oldApplicationFormat struct {
 		Applications []*core.Application `json:"applications" yaml:"applications"`
@@ -49,6 +54,21 @@ func (e *ErrorCollection) ErrorsOrNil() error {
 	return nil
 }
 
+//AllErrors - returns the single errors of an ErrorCollection (or the error itself)
+func AllErrors(err error) []error {
+	if err == nil {
+		return nil
+	}
+	if errMany, ok := err.(*ErrorCollection); ok {
+		var result []error
+		for _, e := range errMany.Errors {
+			result = append(result, AllErrors(e)...)
+		}
+		return result
+	}
+	return []error{err}
+}
+
 func (p *ProjectLoader) LoadProjectConfig(filePath string) (*ProjectConfig, error) {
 	if !strings.Contains(filePath, ".yml") && !!strings.Contains(filePath, ".yaml") {
 		return nil, errors.New("wrong fileextension")
@@ -68,22 +88,45 @@ func (p *ProjectLoader) LoadProjectConfig(filePath string) (*ProjectConfig, erro
 	if err != nil {
 		return nil, err
 	}
+	if projectConfig == nil {
+		return nil, nil
+	}
+	annotateProjectConfig(parseYamlNode(file), projectConfig, filePath)
+	if p.SchemaValidation {
+		collectedErrors := &ErrorCollection{}
+		for _, schemaErr := range validateSchema(ProjectConfigSchema(projectConfig.GetVocabulary()), file, filePath) {
+			collectedErrors.Add(schemaErr)
+		}
+		return projectConfig, collectedErrors.ErrorsOrNil()
+	}
 	return projectConfig, nil
 }
 
 func (p *ProjectLoader) LoadProjectFromConfigFile(filePath string, limitToSubView string) (*core.Project, error) {
 	projectConfig, err := p.LoadProjectConfig(filePath)
-	if err != nil {
+	if projectConfig == nil {
+		if err == nil {
+			err = errors.New(fmt.Sprintf("Project config %v is empty", filePath))
+		}
 		return nil, err
 	}
+	//schema violations in the project config are reported together with the problems of the application definitions
+	collectedErrors := &ErrorCollection{}
+	collectedErrors.Add(err)
 	baseFolder := path.Dir(filePath)
-	return p.LoadProject(projectConfig, baseFolder, limitToSubView)
+	project, err := p.LoadProject(projectConfig, baseFolder, limitToSubView)
+	collectedErrors.Add(err)
+	return project, collectedErrors.ErrorsOrNil()
 }
 
 func (p *ProjectLoader) LoadProject(projectConfig *ProjectConfig, baseFolder string, limitToSubView string) (*core.Project, error) {
 	collectedErrors := &ErrorCollection{}
 	var newProject core.Project
 	newProject.Name = projectConfig.ProjectName
+	newProject.Vocabulary = projectConfig.GetVocabulary()
+	if p.SchemaValidation {
+		p.applicationSchema = ApplicationSchema(newProject.Vocabulary)
+	}
 
 	var applications []*core.Application
 	for _, pathsWithAppDefinitions := range projectConfig.AppDefinitionsPaths {
@@ -111,6 +154,11 @@ func (p *ProjectLoader) LoadProject(projectConfig *ProjectConfig, baseFolder str
 		}
 		applications = replaceApplication(adjustedApplication, applications)
 	}
+	for _, subViewConfig := range projectConfig.SubViewConfig {
+		for _, err := range subViewConfig.validate() {
+			collectedErrors.Add(err)
+		}
+	}
 
 	if limitToSubView == "" {
 		newProject.Applications = applications
@@ -167,7 +215,12 @@ func (p *ProjectLoader) LoadApplications(filePath string) ([]*core.Application,
 		for ck, capp := range applications {
 			if sk > ck && sapp.Name == capp.Name {
 				i++
-				collectedErrors.Add(fmt.Errorf("Application with name %v is duplicated - exists in %d and %d", sapp.Name, sk, ck))
+				collectedErrors.Add(&core.ValidationError{
+					CheckId:     core.CHECK_DUPLICATE_APPLICATION,
+					Application: sapp.Name,
+					Source:      sapp.Source,
+					Message:     fmt.Sprintf("Application with name %v is duplicated - exists in %v and %v", sapp.Name, sapp.Source, capp.Source),
+				})
 				applications[ck].Name = fmt.Sprintf("%v-Duplicate-%v", applications[ck].Name, i)
 			}
 		}
@@ -230,6 +283,10 @@ func (p *ProjectLoader) createFromFile(fileName string) ([]*core.Application, er
 	if !strings.Contains(fileName, ".yml") && !strings.Contains(fileName, ".yaml") {
 		return nil, errors.New("Unknown file type")
 	}
+	if compose.IsComposeFile(fileName) {
+		//docker-compose files are imported - they are neither annotated nor validated against the application schema
+		return compose.ReadApplications(fileName)
+	}
 	//first load yml format where we expect only one application:
 	var loadedApplication core.Application
 	errNewFormat := p.unmarshalYaml(file, &loadedApplication)
@@ -237,19 +294,41 @@ func (p *ProjectLoader) createFromFile(fileName string) ([]*core.Application, er
 	errOldFormat := p.unmarshalYaml(file, &oldFormat)
 
 	//Decide automatically which format should be used to be added to the result:
+	isOldFormat := false
 	if errNewFormat == nil && errOldFormat == nil {
 		//both parsing succeeds - use the one with content
 		if loadedApplication.Name != "" {
 			applications = append(applications, &loadedApplication)
 		} else {
 			applications = append(applications, oldFormat.Applications...)
+			isOldFormat = true
 		}
 	} else if errNewFormat != nil && errOldFormat == nil {
 		applications = append(applications, oldFormat.Applications...)
+		isOldFormat = true
 	} else if errNewFormat == nil {
 		applications = append(applications, &loadedApplication)
 	} else {
-		return nil, errors.New(fmt.Sprintf("Cannot parse application definition file %v: \n \t Errors interpreted in 'Single App Format': %v \n \t Errors interpreted in 'Multiple App Format': %v", fileName, errNewFormat, errOldFormat))
+		line := lineOfYamlError(errNewFormat)
+		if line == 0 {
+			line = lineOfYamlError(errOldFormat)
+		}
+		return nil, &core.ValidationError{
+			CheckId: core.CHECK_DEFINITION_FILE,
+			Source:  core.SourcePosition{File: fileName, Line: line},
+			Message: fmt.Sprintf("Cannot parse application definition file: \n \t Errors interpreted in 'Single App Format': %v \n \t Errors interpreted in 'Multiple App Format': %v", errNewFormat, errOldFormat),
+		}
+	}
+	annotateApplications(parseYamlNode(file), applications, fileName, isOldFormat)
+	if p.SchemaValidation {
+		if p.applicationSchema == nil {
+			p.applicationSchema = ApplicationSchema(nil)
+		}
+		collectedErrors := &ErrorCollection{}
+		for _, schemaErr := range validateSchema(p.applicationSchema, file, fileName) {
+			collectedErrors.Add(schemaErr)
+		}
+		return applications, collectedErrors.ErrorsOrNil()
 	}
 	return applications, nil
 }
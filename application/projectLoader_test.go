@@ -19,9 +19,42 @@ func TestProjectLoader_LoadProjectFromConfigFile(t *testing.T) {
 		t.Error("expected no error for getting app1 got " + err.Error())
 	}
 
-	_, err = project.FindApplication("app2")
+	app2, err := project.FindApplication("app2")
 	if err != nil {
 		t.Error("expected no error for getting app2 got " + err.Error())
 	}
 
+	if app2.Source.String() != "fixtures/new_format/app2.yml:1" {
+		t.Error("expected app2 to be defined at fixtures/new_format/app2.yml:1 got " + app2.Source.String())
+	}
+	if app2.ProvidedServices[0].Source.Line != 7 {
+		t.Errorf("expected service gui of app2 to be defined at line 7 got %v", app2.ProvidedServices[0].Source.Line)
+	}
+
+}
+
+func TestProjectLoader_SchemaValidation(t *testing.T) {
+
+	loader := application.ProjectLoader{StrictMode: true, SchemaValidation: true}
+	_, err := loader.LoadProjectFromConfigFile("fixtures/project.yml", "")
+	if err != nil {
+		t.Error("expected the fixtures to match the schema got " + err.Error())
+	}
+
+	_, err = loader.LoadApplications("fixtures/invalid_schema/app3.yml")
+	errs := application.AllErrors(err)
+	if len(errs) != 1 || errs[0].Error() != "fixtures/invalid_schema/app3.yml:5: provided-services[0].type: value 'rest' is not allowed - use one of: api, gui, exchange, topic" {
+		t.Errorf("expected schema violation for the service type got %v", errs)
+	}
+}
+
+func TestProjectLoader_LoadComposeFile(t *testing.T) {
+	loader := application.ProjectLoader{StrictMode: true, SchemaValidation: true}
+	loaded, err := loader.LoadApplications("../model/compose/fixtures/docker-compose.yml")
+	if errs := application.AllErrors(err); len(errs) > 0 {
+		t.Fatal(errs)
+	}
+	if len(loaded) != 2 || loaded[0].Name != "shop" || loaded[1].Name != "orders" {
+		t.Errorf("Expected the applications of the compose services, got %v", loaded)
+	}
 }
@@ -0,0 +1,282 @@
+package application
+
+import (
+	"errors"
+	"fmt"
+	"log"
+	"os"
+	"path"
+	"path/filepath"
+	"sort"
+	"strings"
+	"sync"
+	"time"
+
+	"github.com/AOEpeople/vistecture/v2/model/core"
+)
+
+type (
+	//ProjectModel - keeps the project of a project config file in memory and rebuilds it when the definitions change (see Watch).
+	// It is safe for concurrent use: readers get an immutable ProjectSnapshot, a rebuild replaces the snapshot atomically
+	ProjectModel struct {
+		loader     *ProjectLoader
+		configFile string
+		//reloadMutex - serializes the rebuilds (the loader is not safe for concurrent use)
+		reloadMutex  sync.Mutex
+		mutex        sync.RWMutex
+		snapshot     *ProjectSnapshot
+		reloadErrors []error
+		//watchedPaths - the project config and the definition paths of the last loaded project config
+		watchedPaths []string
+		//loadedFingerprint - the fingerprint of the watched files before the last (successful or failed) rebuild
+		loadedFingerprint string
+		listeners         []chan int
+	}
+
+	//ProjectSnapshot - one build of the model. It is shared by all readers and must not be modified
+	ProjectSnapshot struct {
+		//Version - incremented with every successful rebuild, starting with 1
+		Version  int
+		LoadedAt time.Time
+		Config   *ProjectConfig
+		//Project - the complete project with all applications
+		Project *core.Project
+		//Errors - the (validation) errors that occurred while loading this build
+		Errors   []error
+		subViews map[string]*core.Project
+	}
+)
+
+const (
+	//DEFAULT_WATCH_INTERVAL - how often the definition files are checked for changes
+	DEFAULT_WATCH_INTERVAL = time.Second
+	//DEFAULT_WATCH_DEBOUNCE - the definition files need to be unchanged for this duration before the model is rebuilt
+	DEFAULT_WATCH_DEBOUNCE = 500 * time.Millisecond
+)
+
+//CreateProjectModel - Factory that loads the project of the config file. Fails if the project cannot be loaded at all
+func CreateProjectModel(loader *ProjectLoader, configFile string) (*ProjectModel, error) {
+	model := &ProjectModel{loader: loader, configFile: configFile, watchedPaths: []string{configFile}}
+	if err := model.Reload(); err != nil {
+		return nil, err
+	}
+	return model, nil
+}
+
+//Snapshot - returns the current (last successfully loaded) build
+func (m *ProjectModel) Snapshot() *ProjectSnapshot {
+	m.mutex.RLock()
+	defer m.mutex.RUnlock()
+	return m.snapshot
+}
+
+//ReloadErrors - returns the errors of the last rebuild if it failed (the previous build is still served then) - nil after a successful rebuild
+func (m *ProjectModel) ReloadErrors() []error {
+	m.mutex.RLock()
+	defer m.mutex.RUnlock()
+	return m.reloadErrors
+}
+
+//Reload - loads the project again and replaces the current snapshot. If the project config or a definition file cannot be read or parsed
+// the current snapshot is kept and the errors are returned (and available via ReloadErrors)
+func (m *ProjectModel) Reload() error {
+	m.reloadMutex.Lock()
+	defer m.reloadMutex.Unlock()
+
+	watchedPaths := m.watched()
+	fingerprint := fingerprintOf(watchedPaths)
+	config, snapshot, err := m.build()
+	if config != nil && !equalStrings(watchedPaths, m.pathsOf(config)) {
+		//the definitions of other paths are loaded - they are watched from now on
+		watchedPaths = m.pathsOf(config)
+		fingerprint = fingerprintOf(watchedPaths)
+	}
+	m.mutex.Lock()
+	defer m.mutex.Unlock()
+	m.watchedPaths = watchedPaths
+	m.loadedFingerprint = fingerprint
+	if err != nil && (m.snapshot != nil || snapshot == nil) {
+		m.reloadErrors = AllErrors(err)
+		return err
+	}
+	if m.snapshot != nil {
+		snapshot.Version = m.snapshot.Version + 1
+	}
+	m.snapshot = snapshot
+	m.reloadErrors = nil
+	for _, listener := range m.listeners {
+		//a listener that did not receive the previous version yet is notified about the reload anyway
+		select {
+		case listener <- snapshot.Version:
+		default:
+		}
+	}
+	return nil
+}
+
+//Subscribe - returns a channel that receives the version after every successful rebuild. Call Unsubscribe when done
+func (m *ProjectModel) Subscribe() <-chan int {
+	m.mutex.Lock()
+	defer m.mutex.Unlock()
+	listener := make(chan int, 1)
+	m.listeners = append(m.listeners, listener)
+	return listener
+}
+
+//Unsubscribe - stops the notifications for a channel returned by Subscribe
+func (m *ProjectModel) Unsubscribe(subscription <-chan int) {
+	m.mutex.Lock()
+	defer m.mutex.Unlock()
+	for i, listener := range m.listeners {
+		if listener == subscription {
+			m.listeners = append(m.listeners[:i], m.listeners[i+1:]...)
+			return
+		}
+	}
+}
+
+// build - returns the loaded config and the new snapshot. The error is set if the build is broken (the snapshot is still returned if there is a project at all)
+func (m *ProjectModel) build() (*ProjectConfig, *ProjectSnapshot, error) {
+	config, err := m.loader.LoadProjectConfig(m.configFile)
+	if config == nil {
+		if err == nil {
+			err = errors.New(fmt.Sprintf("Project config %v is empty", m.configFile))
+		}
+		return nil, nil, err
+	}
+	collectedErrors := &ErrorCollection{}
+	collectedErrors.Add(err)
+	project, err := m.loader.LoadProject(config, path.Dir(m.configFile), "")
+	collectedErrors.Add(err)
+	if project == nil {
+		return config, nil, collectedErrors
+	}
+	snapshot := &ProjectSnapshot{
+		Version:  1,
+		LoadedAt: time.Now(),
+		Config:   config,
+		Project:  project,
+		Errors:   AllErrors(collectedErrors.ErrorsOrNil()),
+		subViews: make(map[string]*core.Project),
+	}
+	for _, subViewConfig := range config.SubViewConfig {
+		subView := &core.Project{
+			Name:         project.Name,
+			Vocabulary:   project.Vocabulary,
+			Applications: subViewConfig.GetMatchedApps(project.Applications),
+		}
+		subView.GenerateApplicationIds()
+		snapshot.subViews[subViewConfig.Name] = subView
+	}
+	brokenDefinitions := &ErrorCollection{}
+	for _, err := range snapshot.Errors {
+		if isBrokenDefinition(err) {
+			brokenDefinitions.Add(err)
+		}
+	}
+	return config, snapshot, brokenDefinitions.ErrorsOrNil()
+}
+
+// isBrokenDefinition - true for errors of files that could not be read or parsed (the applications defined there are missing in the project)
+func isBrokenDefinition(err error) bool {
+	validationError, ok := err.(*core.ValidationError)
+	return !ok || validationError.CheckId == core.CHECK_DEFINITION_FILE
+}
+
+//SubView - returns the project limited to the applications of the subview - or the complete project if the name is empty
+func (s *ProjectSnapshot) SubView(name string) (*core.Project, error) {
+	if name == "" {
+		return s.Project, nil
+	}
+	if subView, found := s.subViews[name]; found {
+		return subView, nil
+	}
+	return nil, errors.New(fmt.Sprintf("Subview with name %v not defined", name))
+}
+
+//Watch - polls the project config and the application definitions every interval and rebuilds the model once the files did not change for the debounce duration.
+// Blocks until stop is closed
+func (m *ProjectModel) Watch(interval time.Duration, debounce time.Duration, stop <-chan struct{}) {
+	ticker := time.NewTicker(interval)
+	defer ticker.Stop()
+	var changed string
+	var changedAt time.Time
+	for {
+		select {
+		case <-stop:
+			return
+		case now := <-ticker.C:
+			current := fingerprintOf(m.watched())
+			m.mutex.RLock()
+			loaded := m.loadedFingerprint
+			m.mutex.RUnlock()
+			if current == loaded {
+				continue
+			}
+			if current != changed {
+				changed = current
+				changedAt = now
+				continue
+			}
+			if now.Sub(changedAt) < debounce {
+				continue
+			}
+			if err := m.Reload(); err != nil {
+				log.Printf("Reloading %v failed - keeping version %v: %v", m.configFile, m.Snapshot().Version, err)
+				continue
+			}
+			log.Printf("Reloaded %v - version %v", m.configFile, m.Snapshot().Version)
+		}
+	}
+}
+
+func (m *ProjectModel) watched() []string {
+	m.mutex.RLock()
+	defer m.mutex.RUnlock()
+	return m.watchedPaths
+}
+
+// pathsOf - the project config file and the definition paths of the config
+func (m *ProjectModel) pathsOf(config *ProjectConfig) []string {
+	paths := []string{m.configFile}
+	for _, definitionsPath := range config.AppDefinitionsPaths {
+		paths = append(paths, path.Join(path.Dir(m.configFile), definitionsPath))
+	}
+	return paths
+}
+
+// fingerprintOf - size and modification time of all files in the paths (folders are walked recursively)
+func fingerprintOf(paths []string) string {
+	var lines []string
+	for _, watchedPath := range paths {
+		err := filepath.Walk(watchedPath, func(file string, info os.FileInfo, err error) error {
+			if err != nil {
+				return err
+			}
+			if info.IsDir() && file != watchedPath && strings.Contains(info.Name(), ".git") {
+				return filepath.SkipDir
+			}
+			if !info.IsDir() {
+				lines = append(lines, fmt.Sprintf("%v %v %v", file, info.Size(), info.ModTime().UnixNano()))
+			}
+			return nil
+		})
+		if err != nil {
+			lines = append(lines, fmt.Sprintf("%v %v", watchedPath, err))
+		}
+	}
+	sort.Strings(lines)
+	return strings.Join(lines, "\n")
+}
+
+func equalStrings(a []string, b []string) bool {
+	if len(a) != len(b) {
+		return false
+	}
+	for i := range a {
+		if a[i] != b[i] {
+			return false
+		}
+	}
+	return true
+}
@@ -0,0 +1,75 @@
+package application_test
+
+import (
+	"io/ioutil"
+	"os"
+	"path/filepath"
+	"testing"
+	"time"
+
+	"github.com/AOEpeople/vistecture/v2/application"
+)
+
+func TestProjectModel_Watch(t *testing.T) {
+	folder := t.TempDir()
+	writeFile := func(name string, content string) {
+		if err := ioutil.WriteFile(filepath.Join(folder, name), []byte(content), 0644); err != nil {
+			t.Fatal(err)
+		}
+	}
+	writeFile("project.yml", "projectName: watched\nappDefinitionsPaths:\n- apps\nsubViews:\n- name: checkout\n  included-applications:\n  - checkout\n")
+	if err := os.Mkdir(filepath.Join(folder, "apps"), 0755); err != nil {
+		t.Fatal(err)
+	}
+	writeFile("apps/checkout.yml", "name: checkout\n")
+
+	model, err := application.CreateProjectModel(&application.ProjectLoader{}, filepath.Join(folder, "project.yml"))
+	if err != nil {
+		t.Fatal(err)
+	}
+	if snapshot := model.Snapshot(); snapshot.Version != 1 || len(snapshot.Project.Applications) != 1 {
+		t.Fatalf("Expected version 1 with one application, got %#v", snapshot)
+	}
+	reloads := model.Subscribe()
+	defer model.Unsubscribe(reloads)
+	stop := make(chan struct{})
+	defer close(stop)
+	go model.Watch(10*time.Millisecond, 30*time.Millisecond, stop)
+	waitFor := func(condition func() bool) {
+		for i := 0; i < 200 && !condition(); i++ {
+			time.Sleep(10 * time.Millisecond)
+		}
+		if !condition() {
+			t.Fatalf("Model not reloaded - version %v, reload errors %v", model.Snapshot().Version, model.ReloadErrors())
+		}
+	}
+
+	writeFile("apps/checkout.yml", "name: checkout\ndependencies: [\n")
+	waitFor(func() bool { return len(model.ReloadErrors()) == 1 })
+	if snapshot := model.Snapshot(); snapshot.Version != 1 || len(snapshot.Project.Applications) != 1 {
+		t.Errorf("Expected the last good version to be kept, got version %v", snapshot.Version)
+	}
+
+	writeFile("apps/payment.yml", "name: payment\n")
+	writeFile("apps/checkout.yml", "name: checkout\ndependencies:\n- reference: payment\n")
+	waitFor(func() bool { return model.Snapshot().Version > 1 })
+	snapshot := model.Snapshot()
+	if len(model.ReloadErrors()) != 0 || len(snapshot.Project.Applications) != 2 {
+		t.Errorf("Expected both applications after the reload, got %v %v", snapshot.Project.Applications, model.ReloadErrors())
+	}
+	subView, err := snapshot.SubView("checkout")
+	if err != nil || len(subView.Applications) != 1 || subView.Applications[0].Name != "checkout" {
+		t.Errorf("Expected subview with checkout, got %v %v", subView, err)
+	}
+	if _, err := snapshot.SubView("unknown"); err == nil {
+		t.Error("Expected an error for an unknown subview")
+	}
+	select {
+	case version := <-reloads:
+		if version != snapshot.Version {
+			t.Errorf("Expected notification about version %v, got %v", snapshot.Version, version)
+		}
+	default:
+		t.Error("Expected a notification about the reload")
+	}
+}
@@ -0,0 +1,108 @@
+package application
+
+import (
+	"github.com/AOEpeople/vistecture/v2/model/analyze"
+	"github.com/AOEpeople/vistecture/v2/model/core"
+	"github.com/AOEpeople/vistecture/v2/model/jsonschema"
+)
+
+const (
+	SCHEMA_APPLICATION = "application"
+	SCHEMA_PROJECT     = "project"
+
+	//CHECK_SCHEMA - check id of problems found by the schema validation
+	CHECK_SCHEMA = "schema"
+)
+
+var (
+	schemaRequired = map[string][]string{
+		"Application":          {"name"},
+		"Service":              {"name"},
+		"Dependency":           {"reference"},
+		"SubViewConfig":        {"name"},
+		"ApplicationOverrides": {"name"},
+		"ServiceOverrides":     {"name"},
+		"Rule":                 {"id", "type"},
+	}
+)
+
+// schemaEnums - allowed values of the vocabulary fields in application and project definitions
+func schemaEnums(vocabulary *core.Vocabulary) map[string][]string {
+	return map[string][]string{
+		"Application.status":              vocabulary.Statuses,
+		"Application.category":            vocabulary.Categories,
+		"Service.status":                  vocabulary.Statuses,
+		"Service.type":                    vocabulary.ServiceTypes,
+		"Service.securityLevel":           vocabulary.SecurityLevels,
+		"Dependency.status":               vocabulary.Statuses,
+		"Dependency.relationship":         vocabulary.Relationships,
+		"ApplicationOverrides.category":   vocabulary.Categories,
+		"ApplicationOverrides.status":     vocabulary.Statuses,
+		"ServiceOverrides.type":           vocabulary.ServiceTypes,
+		"ServiceOverrides.status":         vocabulary.Statuses,
+		"ServiceOverrides.securityLevel":  vocabulary.SecurityLevels,
+		"ApplicationSelector.status":      vocabulary.Statuses,
+		"ApplicationSelector.category":    vocabulary.Categories,
+		"ServiceSelector.status":          vocabulary.Statuses,
+		"ServiceSelector.type":            vocabulary.ServiceTypes,
+		"ServiceSelector.securityLevel":   vocabulary.SecurityLevels,
+		"DependencySelector.status":       vocabulary.Statuses,
+		"DependencySelector.relationship": vocabulary.Relationships,
+		"Rule.type":                       {analyze.RULE_FORBIDDEN_DEPENDENCY, analyze.RULE_DEPENDENCY_REQUIREMENT, analyze.RULE_MAX_TEAM_DEPENDENCIES},
+		"Rule.severity":                   {analyze.SEVERITY_ERROR, analyze.SEVERITY_WARNING, analyze.SEVERITY_INFO},
+	}
+}
+
+//ApplicationSchema - JSON schema of application definition files: a single application or a list of applications below "applications". The vocabulary defines the allowed values (DefaultVocabulary if nil)
+func ApplicationSchema(vocabulary *core.Vocabulary) *jsonschema.Schema {
+	generator := jsonschema.CreateGenerator(schemaEnums(vocabularyOrDefault(vocabulary)), schemaRequired)
+	application := generator.Reference(core.Application{})
+	return generator.Document("Vistecture application definition", &jsonschema.Schema{
+		AnyOf: []*jsonschema.Schema{
+			application,
+			{
+				Type:                 jsonschema.TYPE_OBJECT,
+				Properties:           map[string]*jsonschema.Schema{"applications": {Type: jsonschema.TYPE_ARRAY, Items: application}},
+				AdditionalProperties: false,
+				Required:             []string{"applications"},
+			},
+		},
+	})
+}
+
+//ProjectConfigSchema - JSON schema of the project config file (project.yml). The vocabulary defines the allowed values (DefaultVocabulary if nil)
+func ProjectConfigSchema(vocabulary *core.Vocabulary) *jsonschema.Schema {
+	generator := jsonschema.CreateGenerator(schemaEnums(vocabularyOrDefault(vocabulary)), schemaRequired)
+	return generator.Document("Vistecture project configuration", generator.Reference(ProjectConfig{}))
+}
+
+//SchemaByName - returns SCHEMA_APPLICATION or SCHEMA_PROJECT (nil if the name is unknown)
+func SchemaByName(name string, vocabulary *core.Vocabulary) *jsonschema.Schema {
+	switch name {
+	case SCHEMA_APPLICATION:
+		return ApplicationSchema(vocabulary)
+	case SCHEMA_PROJECT:
+		return ProjectConfigSchema(vocabulary)
+	}
+	return nil
+}
+
+func vocabularyOrDefault(vocabulary *core.Vocabulary) *core.Vocabulary {
+	if vocabulary == nil {
+		return core.DefaultVocabulary()
+	}
+	return vocabulary
+}
+
+// validateSchema - validates the content of a definition file against the schema and returns the violations as ValidationErrors
+func validateSchema(schema *jsonschema.Schema, file []byte, fileName string) []error {
+	var result []error
+	for _, violation := range schema.Validate(parseYamlNode(file)) {
+		result = append(result, &core.ValidationError{
+			CheckId: CHECK_SCHEMA,
+			Source:  core.SourcePosition{File: fileName, Line: violation.Line},
+			Message: violation.Error(),
+		})
+	}
+	return result
+}
@@ -0,0 +1,126 @@
+package application
+
+import (
+	"regexp"
+	"strconv"
+
+	"github.com/AOEpeople/vistecture/v2/model/core"
+	yamlv3 "gopkg.in/yaml.v3"
+)
+
+var yamlErrorLine = regexp.MustCompile(`line (\d+)`)
+
+// parseYamlNode - returns the root mapping node of the yaml document (nil if the file cannot be parsed)
+func parseYamlNode(file []byte) *yamlv3.Node {
+	var document yamlv3.Node
+	if err := yamlv3.Unmarshal(file, &document); err != nil {
+		return nil
+	}
+	if document.Kind != yamlv3.DocumentNode || len(document.Content) == 0 {
+		return nil
+	}
+	return document.Content[0]
+}
+
+// mappingValue - returns the value node for the key of a mapping node
+func mappingValue(node *yamlv3.Node, key string) *yamlv3.Node {
+	if node == nil || node.Kind != yamlv3.MappingNode {
+		return nil
+	}
+	for i := 0; i+1 < len(node.Content); i += 2 {
+		if node.Content[i].Value == key {
+			return node.Content[i+1]
+		}
+	}
+	return nil
+}
+
+// sequenceItems - returns the items of a sequence node
+func sequenceItems(node *yamlv3.Node) []*yamlv3.Node {
+	if node == nil || node.Kind != yamlv3.SequenceNode {
+		return nil
+	}
+	return node.Content
+}
+
+// annotateApplications - sets the source positions of the applications that were unmarshalled from the given node (the single app mapping or the "applications" list of the old format)
+func annotateApplications(root *yamlv3.Node, applications []*core.Application, fileName string, isOldFormat bool) {
+	nodes := []*yamlv3.Node{root}
+	if isOldFormat {
+		nodes = sequenceItems(mappingValue(root, "applications"))
+	}
+	for i, application := range applications {
+		var node *yamlv3.Node
+		if i < len(nodes) {
+			node = nodes[i]
+		}
+		annotateApplication(node, application, fileName)
+	}
+}
+
+func annotateApplication(node *yamlv3.Node, application *core.Application, fileName string) {
+	application.Source = sourcePosition(node, fileName)
+	annotateServices(sequenceItems(mappingValue(node, "provided-services")), application.ProvidedServices, fileName)
+	annotateDependencies(sequenceItems(mappingValue(node, "dependencies")), application.Dependencies, fileName)
+}
+
+func annotateServices(nodes []*yamlv3.Node, services []core.Service, fileName string) {
+	for i := range services {
+		var node *yamlv3.Node
+		if i < len(nodes) {
+			node = nodes[i]
+		}
+		services[i].Source = sourcePosition(node, fileName)
+		annotateDependencies(sequenceItems(mappingValue(node, "dependencies")), services[i].Dependencies, fileName)
+	}
+}
+
+func annotateDependencies(nodes []*yamlv3.Node, dependencies []core.Dependency, fileName string) {
+	for i := range dependencies {
+		var node *yamlv3.Node
+		if i < len(nodes) {
+			node = nodes[i]
+		}
+		dependencies[i].Source = sourcePosition(node, fileName)
+	}
+}
+
+// annotateProjectConfig - sets the source positions of the services and dependencies added by application overrides
+func annotateProjectConfig(root *yamlv3.Node, projectConfig *ProjectConfig, fileName string) {
+	nodes := sequenceItems(mappingValue(root, "appOverrides"))
+	for i, override := range projectConfig.AppOverrides {
+		if i >= len(nodes) || override == nil {
+			continue
+		}
+		annotateServices(sequenceItems(mappingValue(nodes[i], "add-provided-services")), override.AddProvidedServices, fileName)
+		annotateDependencies(sequenceItems(mappingValue(nodes[i], "add-dependencies")), override.AddDependencies, fileName)
+		serviceNodes := sequenceItems(mappingValue(nodes[i], "override-provided-services"))
+		for j, serviceOverride := range override.OverrideProvidedServices {
+			if j >= len(serviceNodes) || serviceOverride == nil {
+				continue
+			}
+			annotateDependencies(sequenceItems(mappingValue(serviceNodes[j], "add-dependencies")), serviceOverride.AddDependencies, fileName)
+		}
+	}
+}
+
+func sourcePosition(node *yamlv3.Node, fileName string) core.SourcePosition {
+	position := core.SourcePosition{File: fileName}
+	if node != nil {
+		position.Line = node.Line
+	}
+	return position
+}
+
+// lineOfYamlError - returns the first line number mentioned in a yaml error (0 if none)
+func lineOfYamlError(err error) int {
+	if err == nil {
+		return 0
+	}
+	match := yamlErrorLine.FindStringSubmatch(err.Error())
+	if match == nil {
+		return 0
+	}
+	line, _ := strconv.Atoi(match[1])
+	return line
+}
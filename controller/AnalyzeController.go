@@ -1,39 +1,242 @@
 package controller
 
 import (
+	"encoding/csv"
+	"encoding/json"
 	"fmt"
+	"io"
 	"log"
+	"os"
+	"strconv"
+	"strings"
+	"text/tabwriter"
 
 	"github.com/AOEpeople/vistecture/v2/model/analyze"
 	"github.com/AOEpeople/vistecture/v2/model/core"
+	"github.com/AOEpeople/vistecture/v2/model/graphviz"
+	"github.com/AOEpeople/vistecture/v2/model/report"
 )
 
 type AnalyzeController struct {
 	project *core.Project
+	rules   []*analyze.Rule
+	//loadErrors - problems found while loading the project, they are reported together with the findings of the analysis
+	loadErrors []error
 }
 
+type analyzeResult struct {
+	*report.Report
+	Impact *analyze.ImpactReport `json:"impact"`
+}
+
+const (
+	OUTPUT_TABLE = "table"
+	OUTPUT_JSON  = report.FORMAT_JSON
+	OUTPUT_CSV   = "csv"
+	OUTPUT_JUNIT = report.FORMAT_JUNIT
+	OUTPUT_SARIF = report.FORMAT_SARIF
+	OUTPUT_TEXT  = report.FORMAT_TEXT
+	OUTPUT_DOT   = "dot"
+)
+
 func (a *AnalyzeController) Inject(project *core.Project) {
 	a.project = project
 }
 
-func (a *AnalyzeController) AnalyzeAction() {
-	var ProjectAnalyzer analyze.ProjectAnalyzer
-	errors := ProjectAnalyzer.AnalyzeCyclicDependencies(a.project)
-	if errors != nil {
-		for _, error := range errors {
-			log.Println(error)
+//InjectRules - sets the architecture rules that are checked by the AnalyzeAction
+func (a *AnalyzeController) InjectRules(rules []*analyze.Rule) {
+	a.rules = rules
+}
+
+//InjectLoadErrors - sets the errors of the project loading that are reported by the AnalyzeAction
+func (a *AnalyzeController) InjectLoadErrors(loadErrors []error) {
+	a.loadErrors = loadErrors
+}
+
+//AnalyzeAction - checks for cyclic dependencies and rule violations and prints the impact analysis.
+// With the junit and sarif output only the findings are written, with json the findings and the impact analysis. The impact analysis is also written if errors are found - then it exits with a non-zero code
+func (a *AnalyzeController) AnalyzeAction(applicationName string, output string, skipOptional bool, skipPlanned bool) {
+	if output == "" {
+		output = OUTPUT_TABLE
+	}
+	switch output {
+	case OUTPUT_TABLE, OUTPUT_JSON, OUTPUT_CSV, OUTPUT_JUNIT, OUTPUT_SARIF:
+	default:
+		log.Fatalf("Unknown output format '%v' - use %v, %v, %v, %v or %v", output, OUTPUT_TABLE, OUTPUT_JSON, OUTPUT_CSV, OUTPUT_JUNIT, OUTPUT_SARIF)
+	}
+	if a.project == nil {
+		a.writeLoadErrors(output)
+		return
+	}
+	ProjectAnalyzer := analyze.ProjectAnalyzer{
+		SkipOptionalDependencies: skipOptional,
+		SkipPlannedDependencies:  skipPlanned,
+	}
+	errors := append([]error{}, a.loadErrors...)
+	errors = append(errors, ProjectAnalyzer.AnalyzeCyclicDependencies(a.project)...)
+	violations, err := ProjectAnalyzer.CheckRules(a.project, a.rules)
+	if err != nil {
+		errors = append(errors, err)
+	}
+	for _, violation := range violations {
+		errors = append(errors, violation)
+	}
+	findings := report.CreateReport("vistecture analyze", errors, a.project)
+
+	if output == OUTPUT_JUNIT || output == OUTPUT_SARIF {
+		if err := findings.Write(os.Stdout, output); err != nil {
+			log.Fatal(err)
+		}
+		exitOnErrors(findings)
+		return
+	}
+	if output != OUTPUT_JSON {
+		for _, finding := range findings.Findings {
+			log.Println(finding)
+		}
+	}
+	impactReport, err := ProjectAnalyzer.ImpactAnalyze(a.project, applicationName)
+	if err != nil {
+		log.Fatal(err)
+	}
+
+	switch output {
+	case OUTPUT_JSON:
+		err = writeJson(os.Stdout, analyzeResult{Report: findings, Impact: impactReport})
+	case OUTPUT_CSV:
+		err = writeImpactCsv(os.Stdout, impactReport)
+	default:
+		if findings.HasErrors() {
+			fmt.Println("\nSolve Errors please!")
+		} else {
+			fmt.Println("\nGreat - no errors or cyclic dependencies found in your definitions!")
 		}
-		log.Fatal("Solve Errors please!")
+		fmt.Println()
+		fmt.Println("Impact Analysis: \n(How many other components may be influenced if a component fails)")
+		err = writeImpactTable(os.Stdout, impactReport, applicationName != "")
 	}
-	fmt.Println("\nGreat - no errors or cyclic dependencies found in your definitions!")
+	if err != nil {
+		log.Fatal(err)
+	}
+	exitOnErrors(findings)
+}
 
-	fmt.Println()
-	fmt.Println("Impact Analysis: \n(How many other components may be influenced if a component fails)")
-	impacts := ProjectAnalyzer.ImpactAnalyze(a.project)
-	fmt.Println("Direct\t\tIndirect\tComponent")
-	fmt.Println("------\t\t--------\t--------")
-	for _, impact := range impacts {
-		fmt.Println(impact)
+// writeLoadErrors - reports the errors if the project could not be loaded at all
+func (a *AnalyzeController) writeLoadErrors(output string) {
+	findings := report.CreateReport("vistecture analyze", a.loadErrors, nil)
+	switch output {
+	case OUTPUT_JUNIT, OUTPUT_SARIF:
+		if err := findings.Write(os.Stdout, output); err != nil {
+			log.Fatal(err)
+		}
+	case OUTPUT_JSON:
+		if err := writeJson(os.Stdout, analyzeResult{Report: findings}); err != nil {
+			log.Fatal(err)
+		}
+	default:
+		for _, finding := range findings.Findings {
+			log.Println(finding)
+		}
 	}
+	log.Fatal("project loading aborted.")
+}
 
+//PathAction - prints the shortest path and all paths with at most maxLength hops from one application to another as text, json or dot (the shortest path highlighted)
+func (a *AnalyzeController) PathAction(from string, to string, maxLength int, output string, skipOptional bool, skipPlanned bool) {
+	projectAnalyzer := analyze.ProjectAnalyzer{
+		SkipOptionalDependencies: skipOptional,
+		SkipPlannedDependencies:  skipPlanned,
+	}
+	pathReport, err := projectAnalyzer.FindPaths(a.project, from, to, maxLength)
+	if err != nil {
+		log.Fatal(err)
+	}
+	switch output {
+	case OUTPUT_TEXT, "":
+		err = writePathText(os.Stdout, pathReport)
+	case OUTPUT_JSON:
+		err = writeJson(os.Stdout, pathReport)
+	case OUTPUT_DOT:
+		_, err = fmt.Fprint(os.Stdout, graphviz.CreatePathDrawer(pathReport).Draw())
+	default:
+		log.Fatalf("Unknown output format '%v' - use %v, %v or %v", output, OUTPUT_TEXT, OUTPUT_JSON, OUTPUT_DOT)
+	}
+	if err != nil {
+		log.Fatal(err)
+	}
+}
+
+func exitOnErrors(findings *report.Report) {
+	if findings.HasErrors() {
+		os.Exit(1)
+	}
+}
+
+func writeImpactTable(w io.Writer, impactReport *analyze.ImpactReport, withDependents bool) error {
+	tw := tabwriter.NewWriter(w, 0, 8, 2, ' ', 0)
+	fmt.Fprintln(tw, "Direct\tTransitive\tDepth\tTeams\tGroups\tComponent")
+	fmt.Fprintln(tw, "------\t----------\t-----\t-----\t------\t---------")
+	for _, impact := range impactReport.Applications {
+		fmt.Fprintf(tw, "%d\t%d\t%d\t%v\t%v\t%v\n", impact.DirectCount, impact.TransitiveCount, impact.MaxDepth, summariesToString(impact.ImpactedTeams), summariesToString(impact.ImpactedGroups), impact.Application)
+	}
+	if withDependents {
+		for _, impact := range impactReport.Applications {
+			fmt.Fprintf(tw, "\nDependents of %v:\n", impact.Application)
+			fmt.Fprintln(tw, "Depth\tTeam\tGroup\tComponent")
+			fmt.Fprintln(tw, "-----\t----\t-----\t---------")
+			for _, dependent := range impact.Dependents {
+				fmt.Fprintf(tw, "%d\t%v\t%v\t%v\n", dependent.Depth, dependent.Team, dependent.Group, dependent.Name)
+			}
+		}
+	}
+	return tw.Flush()
+}
+
+func writePathText(w io.Writer, pathReport *analyze.PathReport) error {
+	if pathReport.Shortest == nil {
+		_, err := fmt.Fprintf(w, "No path from %v to %v\n", pathReport.From, pathReport.To)
+		return err
+	}
+	fmt.Fprintf(w, "Shortest path (length %d):\n  %v\n", pathReport.Shortest.Length, pathReport.Shortest.Description)
+	fmt.Fprintf(w, "\nAll paths up to length %d (%d):\n", pathReport.MaxLength, len(pathReport.Paths))
+	for i, path := range pathReport.Paths {
+		if _, err := fmt.Fprintf(w, "%3d. %v\n", i+1, path.Description); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+func writeJson(w io.Writer, data interface{}) error {
+	encoder := json.NewEncoder(w)
+	encoder.SetIndent("", "  ")
+	return encoder.Encode(data)
+}
+
+//writeImpactCsv - writes one row per application and dependent. Applications without dependents get one row with empty dependent columns
+func writeImpactCsv(w io.Writer, impactReport *analyze.ImpactReport) error {
+	csvWriter := csv.NewWriter(w)
+	_ = csvWriter.Write([]string{"application", "team", "group", "direct", "transitive", "dependent", "dependentTeam", "dependentGroup", "depth"})
+	for _, impact := range impactReport.Applications {
+		row := []string{impact.Application, impact.Team, impact.Group, strconv.Itoa(impact.DirectCount), strconv.Itoa(impact.TransitiveCount)}
+		if len(impact.Dependents) == 0 {
+			_ = csvWriter.Write(append(row, "", "", "", ""))
+		}
+		for _, dependent := range impact.Dependents {
+			_ = csvWriter.Write(append(row, dependent.Name, dependent.Team, dependent.Group, strconv.Itoa(dependent.Depth)))
+		}
+	}
+	csvWriter.Flush()
+	return csvWriter.Error()
+}
+
+func summariesToString(summaries []*analyze.ImpactSummary) string {
+	var parts []string
+	for _, summary := range summaries {
+		parts = append(parts, fmt.Sprintf("%v:%d", summary.Name, summary.Count))
+	}
+	if len(parts) == 0 {
+		return "-"
+	}
+	return strings.Join(parts, ",")
 }
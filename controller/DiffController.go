@@ -0,0 +1,21 @@
+package controller
+
+import (
+	"log"
+	"os"
+
+	"github.com/AOEpeople/vistecture/v2/model/core"
+	"github.com/AOEpeople/vistecture/v2/model/diff"
+)
+
+type (
+	//DiffController - shows the semantic changes between two versions of a project
+	DiffController struct{}
+)
+
+//DiffAction - prints the changes from the base to the head project as text, markdown, json or dot
+func (d *DiffController) DiffAction(base *core.Project, head *core.Project, output string) {
+	if err := diff.CreateProjectDiff(base, head).Write(os.Stdout, output); err != nil {
+		log.Fatal(err)
+	}
+}
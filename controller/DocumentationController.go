@@ -11,8 +11,12 @@ import (
 
 	"log"
 
+	"github.com/AOEpeople/vistecture/v2/application"
 	"github.com/AOEpeople/vistecture/v2/model/core"
 	"github.com/AOEpeople/vistecture/v2/model/graphviz"
+	"github.com/AOEpeople/vistecture/v2/model/mermaid"
+	"github.com/AOEpeople/vistecture/v2/model/plantuml"
+	"github.com/AOEpeople/vistecture/v2/model/structurizr"
 )
 
 type (
@@ -29,7 +33,8 @@ func (d *DocumentationController) Inject(project *core.Project) {
 	d.project = project
 }
 
-func (d *DocumentationController) GraphvizAction(componentName string, iconPath string, hidePlanned string, skipValidation bool) {
+//GraphvizAction - prints the complete graph or the graph of one application. With a depth (upstream/downstream < 0 means "use depth") the neighbourhood of the application is drawn
+func (d *DocumentationController) GraphvizAction(componentName string, iconPath string, hidePlanned string, skipValidation bool, depth int, upstreamDepth int, downstreamDepth int) {
 	projectDrawer := graphviz.CreateProjectDrawer(d.project, iconPath)
 	if componentName != "" {
 		Component, e := d.project.FindApplication(componentName)
@@ -42,7 +47,17 @@ func (d *DocumentationController) GraphvizAction(componentName string, iconPath
 		if Component == nil {
 			os.Exit(-1)
 		}
-		fmt.Print(projectDrawer.DrawComponent(Component))
+		if upstreamDepth < 0 {
+			upstreamDepth = depth
+		}
+		if downstreamDepth < 0 {
+			downstreamDepth = depth
+		}
+		if upstreamDepth == 0 && downstreamDepth == 0 {
+			fmt.Print(projectDrawer.DrawComponent(Component))
+		} else {
+			fmt.Print(projectDrawer.DrawNeighbourhood(Component, upstreamDepth, downstreamDepth, hidePlanned == "1"))
+		}
 	} else {
 		fmt.Print(projectDrawer.DrawComplete(hidePlanned == "1"))
 	}
@@ -59,6 +74,66 @@ func (d *DocumentationController) TeamGraphvizAction(summaryRelation string) {
 	fmt.Print(drawer.DrawComplete())
 }
 
+//PlantUMLAction - prints the PlantUML diagram of the view (complete, application, group or team)
+func (d *DocumentationController) PlantUMLAction(view string, componentName string, hidePlanned string, summaryRelation string) {
+	switch view {
+	case "", "complete":
+		fmt.Print(plantuml.CreateProjectDrawer(d.project).DrawComplete(hidePlanned == "1"))
+	case "application":
+		component, e := d.project.FindApplication(componentName)
+		if e != nil {
+			log.Fatal(e)
+		}
+		fmt.Print(plantuml.CreateProjectDrawer(d.project).DrawComponent(component, hidePlanned == "1"))
+	case "group":
+		fmt.Print(plantuml.CreateGroupDrawer(d.project, summaryRelation != "", hidePlanned == "1").DrawComplete())
+	case "team":
+		fmt.Print(plantuml.CreateTeamDependencyDrawer(d.project, summaryRelation != "", hidePlanned == "1").DrawComplete())
+	default:
+		log.Fatalf("Unknown view '%v' - use complete, application, group or team", view)
+	}
+}
+
+//MermaidAction - prints the Mermaid flowchart of the view (complete, application, group or team) - optional as fenced markdown code block
+func (d *DocumentationController) MermaidAction(view string, componentName string, hidePlanned string, summaryRelation string, markdown string) {
+	var chart string
+	switch view {
+	case "", "complete":
+		chart = mermaid.CreateProjectDrawer(d.project).DrawComplete(hidePlanned == "1")
+	case "application":
+		component, e := d.project.FindApplication(componentName)
+		if e != nil {
+			log.Fatal(e)
+		}
+		chart = mermaid.CreateProjectDrawer(d.project).DrawComponent(component, hidePlanned == "1")
+	case "group":
+		chart = mermaid.CreateGroupDrawer(d.project, summaryRelation != "", hidePlanned == "1").DrawComplete()
+	case "team":
+		chart = mermaid.CreateTeamDependencyDrawer(d.project, summaryRelation != "", hidePlanned == "1").DrawComplete()
+	default:
+		log.Fatalf("Unknown view '%v' - use complete, application, group or team", view)
+	}
+	if markdown != "" {
+		chart = "```mermaid\n" + chart + "```\n"
+	}
+	fmt.Print(chart)
+}
+
+//StructurizrAction - prints the project as Structurizr DSL workspace with an additional container view per subview
+func (d *DocumentationController) StructurizrAction(subViews []*application.SubViewConfig) {
+	var views []*structurizr.View
+	for _, subView := range subViews {
+		views = append(views, &structurizr.View{
+			Key:          subView.Name,
+			Title:        subView.Name,
+			Applications: subView.GetMatchedApps(d.project.Applications),
+		})
+	}
+	if err := structurizr.CreateWorkspaceWriter(d.project, views).Write(os.Stdout); err != nil {
+		log.Fatal(err)
+	}
+}
+
 func (d *DocumentationController) HTMLDocumentAction(templatePath string, iconPath string) {
 	tpl := template.New(filepath.Base(templatePath))
 
@@ -0,0 +1,109 @@
+package controller
+
+import (
+	"fmt"
+	"io"
+	"log"
+	"os"
+	"strings"
+	"text/tabwriter"
+
+	"github.com/AOEpeople/vistecture/v2/model/core"
+	"github.com/AOEpeople/vistecture/v2/model/drift"
+)
+
+type (
+	//DriftController - compares the declared dependencies with the calls observed in traces
+	DriftController struct {
+		project *core.Project
+	}
+)
+
+func (d *DriftController) Inject(project *core.Project) {
+	d.project = project
+}
+
+//DriftAction - reads the calls of the trace exports or CSV files and prints the drift report.
+// Exits with a non-zero code if undeclared calls (with failOnUnobserved also unobserved dependencies) are found
+func (d *DriftController) DriftAction(paths []string, mappingProperty string, output string, failOnUnobserved bool) {
+	if output == "" {
+		output = OUTPUT_TABLE
+	}
+	if output != OUTPUT_TABLE && output != OUTPUT_JSON {
+		log.Fatalf("Unknown output format '%v' - use %v or %v", output, OUTPUT_TABLE, OUTPUT_JSON)
+	}
+	if len(paths) == 0 {
+		log.Fatal("Path of the trace exports or call files missing")
+	}
+	var calls []*drift.Call
+	for _, path := range paths {
+		read, readErrors := drift.ReadCallsFromPath(path)
+		for _, err := range readErrors {
+			log.Println(err)
+		}
+		calls = append(calls, read...)
+	}
+	if len(calls) == 0 {
+		log.Fatal("No calls found")
+	}
+	report := drift.CreateDetector(d.project, mappingProperty).Detect(drift.SumCalls(calls))
+
+	var err error
+	if output == OUTPUT_JSON {
+		err = writeJson(os.Stdout, report)
+	} else {
+		err = writeDriftTable(os.Stdout, report)
+	}
+	if err != nil {
+		log.Fatal(err)
+	}
+	if report.HasDrift(failOnUnobserved) {
+		os.Exit(1)
+	}
+}
+
+func writeDriftTable(w io.Writer, report *drift.Report) error {
+	tw := tabwriter.NewWriter(w, 0, 8, 2, ' ', 0)
+	fmt.Fprintf(tw, "Observed but undeclared (%d):\n", len(report.Undeclared))
+	fmt.Fprintln(tw, "Calls\tFrom\tTo")
+	fmt.Fprintln(tw, "-----\t----\t--")
+	for _, relation := range report.Undeclared {
+		fmt.Fprintf(tw, "%d\t%v\t%v\n", relation.Count, relation.From, relation.To)
+	}
+	fmt.Fprintf(tw, "\nDeclared but not observed (%d):\n", len(report.Unobserved))
+	writeDependencyTraffic(tw, report.Unobserved)
+	fmt.Fprintln(tw, "\nTraffic per pair of applications with declared dependencies (traces do not name the provided services - the calls are not split by dependency):")
+	writeDependencyTraffic(tw, report.Relations)
+	if len(report.UnknownServices) > 0 {
+		fmt.Fprintln(tw, "\nServices not mapped to an application:")
+		fmt.Fprintln(tw, "Calls\tService")
+		fmt.Fprintln(tw, "-----\t-------")
+		for _, service := range report.UnknownServices {
+			fmt.Fprintf(tw, "%d\t%v\n", service.Count, service.Name)
+		}
+	}
+	if len(report.Unobservable) > 0 {
+		fmt.Fprintf(tw, "\nApplications without observed outgoing calls (not checked): %v\n", report.Unobservable)
+	}
+	return tw.Flush()
+}
+
+// writeDependencyTraffic - one row per pair of applications with the declared dependencies (declaring service in brackets)
+func writeDependencyTraffic(tw *tabwriter.Writer, relations []*drift.RelationTraffic) {
+	fmt.Fprintln(tw, "Calls\tFrom\tTo\tDeclared dependencies\tDefined in")
+	fmt.Fprintln(tw, "-----\t----\t--\t---------------------\t----------")
+	for _, traffic := range relations {
+		var dependencies []string
+		for _, dependency := range traffic.Dependencies {
+			description := dependency.Reference
+			if dependency.Service != "" {
+				description = "[" + dependency.Service + "] " + description
+			}
+			if dependency.Status != "" {
+				description += " (" + string(dependency.Status) + ")"
+			}
+			dependencies = append(dependencies, description)
+		}
+		fmt.Fprintf(tw, "%d\t%v\t%v\t%v\t%v\n", traffic.Count, traffic.From, traffic.To, strings.Join(dependencies, ", "), traffic.Dependencies[0].Source)
+	}
+}
@@ -0,0 +1,41 @@
+package controller
+
+import (
+	"io"
+	"log"
+	"os"
+
+	"github.com/AOEpeople/vistecture/v2/model/backstage"
+	"github.com/AOEpeople/vistecture/v2/model/core"
+)
+
+type (
+	//ExportController - exports the project to the formats of other tools
+	ExportController struct {
+		project *core.Project
+	}
+)
+
+func (e *ExportController) Inject(project *core.Project) {
+	e.project = project
+}
+
+//BackstageAction - writes the Backstage entities (catalog-info.yaml) to the output file or stdout
+func (e *ExportController) BackstageAction(outputFile string) {
+	var out io.Writer = os.Stdout
+	if outputFile != "" {
+		file, err := os.Create(outputFile)
+		if err != nil {
+			log.Fatal(err)
+		}
+		defer file.Close()
+		out = file
+	}
+	entities := backstage.CreateCatalogExporter(e.project).Entities()
+	if err := backstage.WriteEntities(out, entities); err != nil {
+		log.Fatal(err)
+	}
+	if outputFile != "" {
+		log.Printf("%v entities written to %v", len(entities), outputFile)
+	}
+}
@@ -0,0 +1,66 @@
+package controller
+
+import (
+	"log"
+
+	"github.com/AOEpeople/vistecture/v2/application"
+	"github.com/AOEpeople/vistecture/v2/model/backstage"
+	"github.com/AOEpeople/vistecture/v2/model/compose"
+	"github.com/AOEpeople/vistecture/v2/model/core"
+	"github.com/AOEpeople/vistecture/v2/model/kubernetes"
+)
+
+type (
+	//ImportController - creates application definitions from the inventories of other tools
+	ImportController struct {
+		writer *application.ApplicationWriter
+	}
+)
+
+//CreateImportController - Factory - the application definitions are written to the output folder (merged into existing definitions)
+func CreateImportController(outputFolder string) *ImportController {
+	return &ImportController{writer: &application.ApplicationWriter{Folder: outputFolder, Merge: true}}
+}
+
+//BackstageAction - imports the Components of a catalog file or a folder of catalog files
+func (i *ImportController) BackstageAction(path string) {
+	entities, err := backstage.ReadEntitiesFromPath(path)
+	if err != nil {
+		log.Fatal(err)
+	}
+	i.write(backstage.CreateCatalogImporter(entities).Applications())
+}
+
+//KubernetesAction - imports the workloads of the manifests (e.g. rendered by helm template) of a file or folder
+func (i *ImportController) KubernetesAction(path string) {
+	objects, readErrors := kubernetes.ReadObjectsFromPath(path)
+	for _, err := range readErrors {
+		log.Println(err)
+	}
+	i.write(kubernetes.CreateImporter(objects).Applications())
+}
+
+//ComposeAction - imports the services of a docker-compose file
+func (i *ImportController) ComposeAction(fileName string) {
+	applications, err := compose.ReadApplications(fileName)
+	if err != nil {
+		log.Fatal(err)
+	}
+	i.write(applications)
+}
+
+func (i *ImportController) write(applications []*core.Application) {
+	if len(applications) == 0 {
+		log.Fatal("No applications found to import")
+	}
+	written, skipped, err := i.writer.WriteApplications(applications)
+	for _, fileName := range written {
+		log.Printf("Written %v", fileName)
+	}
+	for _, application := range skipped {
+		log.Printf("Skipped %v", application)
+	}
+	if err != nil {
+		log.Fatal(err)
+	}
+}
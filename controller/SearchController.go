@@ -0,0 +1,80 @@
+package controller
+
+import (
+	"fmt"
+	"io"
+	"log"
+	"os"
+	"strings"
+
+	"github.com/AOEpeople/vistecture/v2/model/core"
+	"github.com/AOEpeople/vistecture/v2/model/search"
+)
+
+type (
+	//SearchController - full-text search over the applications, services and dependencies
+	SearchController struct {
+		project *core.Project
+	}
+)
+
+//MAX_PRINTED_MATCHES - number of matches printed per application in the text output
+const MAX_PRINTED_MATCHES = 3
+
+func (s *SearchController) Inject(project *core.Project) {
+	s.project = project
+}
+
+//SearchAction - prints the applications matching the query, the best first, with highlighted snippets of the matching fields
+func (s *SearchController) SearchAction(query string, limit int, output string) {
+	if strings.TrimSpace(query) == "" {
+		log.Fatal("Search query missing")
+	}
+	results := search.CreateIndex(s.project).Search(query, limit)
+	var err error
+	switch output {
+	case OUTPUT_TEXT, "":
+		err = writeSearchText(os.Stdout, results)
+	case OUTPUT_JSON:
+		if results == nil {
+			results = []*search.Result{}
+		}
+		err = writeJson(os.Stdout, results)
+	default:
+		log.Fatalf("Unknown output format '%v' - use %v or %v", output, OUTPUT_TEXT, OUTPUT_JSON)
+	}
+	if err != nil {
+		log.Fatal(err)
+	}
+}
+
+func writeSearchText(w io.Writer, results []*search.Result) error {
+	if len(results) == 0 {
+		_, err := fmt.Fprintln(w, "No applications found")
+		return err
+	}
+	var b strings.Builder
+	for i, result := range results {
+		b.WriteString(fmt.Sprintf("%d. %v", i+1, result.Application))
+		if result.Title != "" {
+			b.WriteString(" (" + result.Title + ")")
+		}
+		if result.Team != "" {
+			b.WriteString(" - team " + result.Team)
+		}
+		b.WriteString(fmt.Sprintf(" [score %v]\n", result.Score))
+		for j, match := range result.Matches {
+			if j == MAX_PRINTED_MATCHES {
+				b.WriteString(fmt.Sprintf("   ... %d more matches\n", len(result.Matches)-MAX_PRINTED_MATCHES))
+				break
+			}
+			field := match.Field
+			if match.Kind != search.KIND_APPLICATION {
+				field = match.Kind + " " + match.Name + " " + match.Field
+			}
+			b.WriteString(fmt.Sprintf("   %v: %v\n", field, match.Highlighted("**", "**")))
+		}
+	}
+	_, err := io.WriteString(w, b.String())
+	return err
+}
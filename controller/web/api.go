@@ -0,0 +1,340 @@
+package web
+
+import (
+	"encoding/json"
+	"errors"
+	"fmt"
+	"net/http"
+	"net/url"
+	"sort"
+	"strings"
+
+	"github.com/AOEpeople/vistecture/v2/application"
+	"github.com/AOEpeople/vistecture/v2/model/core"
+	"github.com/gorilla/mux"
+)
+
+type (
+	//ApiController - the REST API (/api/v1). All endpoints take the query parameters subview, name, team, group, category, technology, status and property (key=value or key, repeatable).
+	// The filters select the returned applications, dependencies and dependents are always taken from the complete project (or subview)
+	ApiController struct {
+		projectModel *application.ProjectModel
+	}
+
+	//ApiApplication - an application with its direct neighbours
+	ApiApplication struct {
+		*core.Application
+		//DependencyApplications - names of the applications the application depends on (the declared dependencies are in Dependencies)
+		DependencyApplications []string `json:"dependencyApplications"`
+		//DependentApplications - names of the applications that depend on the application
+		DependentApplications []string                    `json:"dependentApplications"`
+		DependenciesGrouped   []*core.DependenciesGrouped `json:"dependenciesGrouped"`
+	}
+
+	//ApiTeam - the applications of a team
+	ApiTeam struct {
+		Name         string   `json:"name"`
+		Applications []string `json:"applications"`
+	}
+
+	//ApiTeamDetails - the applications of a team and the couplings to other teams
+	ApiTeamDetails struct {
+		Name         string              `json:"name"`
+		Applications []*core.Application `json:"applications"`
+		//Dependencies - the teams the applications of the team depend on
+		Dependencies []*ApiTeamRelation `json:"dependencies"`
+		//Dependents - the teams with applications depending on the team
+		Dependents []*ApiTeamRelation `json:"dependents"`
+	}
+
+	//ApiTeamRelation - the dependencies between the applications of two teams
+	ApiTeamRelation struct {
+		Team string `json:"team"`
+		//Relationship - the strongest relationship of the dependencies
+		Relationship core.Relationship `json:"relationship,omitempty"`
+		//Dependencies - the dependencies like "app1 -> app2.api"
+		Dependencies []string `json:"dependencies"`
+	}
+
+	//ApiMissingDependency - a dependency to an application that is not defined
+	ApiMissingDependency struct {
+		Application   string `json:"application"`
+		SourceService string `json:"sourceService,omitempty"`
+		core.Dependency
+	}
+
+	//ApiError - body of failed requests
+	ApiError struct {
+		Error string `json:"error"`
+	}
+)
+
+func (a *ApiController) Inject(projectModel *application.ProjectModel) {
+	a.projectModel = projectModel
+}
+
+//ApplicationsAction - lists the matching applications
+func (a *ApiController) ApplicationsAction(w http.ResponseWriter, r *http.Request) {
+	project, selector, ok := a.project(w, r)
+	if !ok {
+		return
+	}
+	writeApiJson(w, http.StatusOK, filterApplications(project.Applications, selector))
+}
+
+//ApplicationAction - returns the application with its dependencies, dependents and grouped dependencies
+func (a *ApiController) ApplicationAction(w http.ResponseWriter, r *http.Request) {
+	project, selector, ok := a.project(w, r)
+	if !ok {
+		return
+	}
+	app, ok := findApiApplication(w, r, project, selector)
+	if !ok {
+		return
+	}
+	graph := project.Graph()
+	result := &ApiApplication{
+		Application:            app,
+		DependencyApplications: []string{},
+		DependentApplications:  []string{},
+		DependenciesGrouped:    graph.DependenciesGrouped(app),
+	}
+	for _, dependency := range graph.Dependencies(app.Name) {
+		result.DependencyApplications = append(result.DependencyApplications, dependency.Name)
+	}
+	for _, dependent := range graph.Dependents(app.Name) {
+		result.DependentApplications = append(result.DependentApplications, dependent.Name)
+	}
+	writeApiJson(w, http.StatusOK, result)
+}
+
+//ServicesAction - lists the services provided by the application
+func (a *ApiController) ServicesAction(w http.ResponseWriter, r *http.Request) {
+	project, selector, ok := a.project(w, r)
+	if !ok {
+		return
+	}
+	app, ok := findApiApplication(w, r, project, selector)
+	if !ok {
+		return
+	}
+	services := app.ProvidedServices
+	if services == nil {
+		services = []core.Service{}
+	}
+	writeApiJson(w, http.StatusOK, services)
+}
+
+//TeamsAction - lists the teams of the matching applications (applications without team belong to "noteam")
+func (a *ApiController) TeamsAction(w http.ResponseWriter, r *http.Request) {
+	project, selector, ok := a.project(w, r)
+	if !ok {
+		return
+	}
+	filtered := &core.Project{Applications: filterApplications(project.Applications, selector)}
+	teams := []*ApiTeam{}
+	for team, apps := range filtered.GetApplicationByTeam() {
+		apiTeam := &ApiTeam{Name: team}
+		for _, app := range apps {
+			apiTeam.Applications = append(apiTeam.Applications, app.Name)
+		}
+		teams = append(teams, apiTeam)
+	}
+	sort.Slice(teams, func(i, j int) bool {
+		return teams[i].Name < teams[j].Name
+	})
+	writeApiJson(w, http.StatusOK, teams)
+}
+
+//TeamAction - returns the matching applications of the team and the dependencies from and to other teams
+func (a *ApiController) TeamAction(w http.ResponseWriter, r *http.Request) {
+	project, selector, ok := a.project(w, r)
+	if !ok {
+		return
+	}
+	team := mux.Vars(r)["team"]
+	apps := (&core.Project{Applications: filterApplications(project.Applications, selector)}).GetApplicationByTeam()[team]
+	if len(apps) == 0 {
+		writeApiError(w, http.StatusNotFound, errors.New(fmt.Sprintf("Team with name '%v' not found", team)))
+		return
+	}
+	result := &ApiTeamDetails{Name: team, Applications: apps, Dependencies: []*ApiTeamRelation{}, Dependents: []*ApiTeamRelation{}}
+	teamOf := func(app *core.Application) string {
+		if app.Team == "" {
+			return core.NOTEAM
+		}
+		return app.Team
+	}
+	for _, relation := range project.Graph().ClusterRelations(teamOf, nil) {
+		if relation.FromCluster == team {
+			result.Dependencies = append(result.Dependencies, newApiTeamRelation(relation.ToCluster, relation))
+		} else if relation.ToCluster == team {
+			result.Dependents = append(result.Dependents, newApiTeamRelation(relation.FromCluster, relation))
+		}
+	}
+	writeApiJson(w, http.StatusOK, result)
+}
+
+//GroupAction - returns the group with its subgroups and the matching applications. Without qualifiedName the root group is returned
+func (a *ApiController) GroupAction(w http.ResponseWriter, r *http.Request) {
+	project, selector, ok := a.project(w, r)
+	if !ok {
+		return
+	}
+	filtered := &core.Project{Applications: filterApplications(project.Applications, selector)}
+	group := filtered.GetApplicationsRootGroup()
+	if qualifiedName := strings.Trim(mux.Vars(r)["qualifiedName"], "/"); qualifiedName != "" {
+		group = findGroup(group, qualifiedName)
+		if group == nil {
+			writeApiError(w, http.StatusNotFound, errors.New(fmt.Sprintf("Group with name '%v' not found", qualifiedName)))
+			return
+		}
+	}
+	writeApiJson(w, http.StatusOK, group)
+}
+
+//SubViewsAction - lists the names of the configured subviews
+func (a *ApiController) SubViewsAction(w http.ResponseWriter, r *http.Request) {
+	names := []string{}
+	for _, subViewConfig := range a.projectModel.Snapshot().Config.SubViewConfig {
+		names = append(names, subViewConfig.Name)
+	}
+	writeApiJson(w, http.StatusOK, names)
+}
+
+//SubViewAction - returns the project limited to the subview with the matching applications
+func (a *ApiController) SubViewAction(w http.ResponseWriter, r *http.Request) {
+	selector, err := selectorOf(r.URL.Query())
+	if err != nil {
+		writeApiError(w, http.StatusBadRequest, err)
+		return
+	}
+	name := mux.Vars(r)["name"]
+	project, err := a.projectModel.Snapshot().SubView(name)
+	if err != nil || name == "" {
+		writeApiError(w, http.StatusNotFound, errors.New(fmt.Sprintf("Subview with name '%v' not found", name)))
+		return
+	}
+	writeApiJson(w, http.StatusOK, &core.Project{
+		Name:         project.Name,
+		Applications: filterApplications(project.Applications, selector),
+		Vocabulary:   project.Vocabulary,
+	})
+}
+
+//MissingAction - lists the dependencies of the matching applications to applications that are not defined
+func (a *ApiController) MissingAction(w http.ResponseWriter, r *http.Request) {
+	project, selector, ok := a.project(w, r)
+	if !ok {
+		return
+	}
+	missing := []*ApiMissingDependency{}
+	for _, edge := range project.Graph().MissingEdges() {
+		if !selector.Matches(edge.From) {
+			continue
+		}
+		missing = append(missing, &ApiMissingDependency{Application: edge.From.Name, SourceService: edge.SourceService, Dependency: edge.Dependency})
+	}
+	writeApiJson(w, http.StatusOK, missing)
+}
+
+// project - the project of the requested subview and the selector of the filters. Writes the error response if not ok
+func (a *ApiController) project(w http.ResponseWriter, r *http.Request) (*core.Project, *core.ApplicationSelector, bool) {
+	selector, err := selectorOf(r.URL.Query())
+	if err != nil {
+		writeApiError(w, http.StatusBadRequest, err)
+		return nil, nil, false
+	}
+	project, err := a.projectModel.Snapshot().SubView(r.URL.Query().Get("subview"))
+	if err != nil {
+		writeApiError(w, http.StatusNotFound, err)
+		return nil, nil, false
+	}
+	return project, selector, true
+}
+
+// selectorOf - the application selector of the filter query parameters
+func selectorOf(query url.Values) (*core.ApplicationSelector, error) {
+	selector := &core.ApplicationSelector{
+		Name:       query.Get("name"),
+		Team:       query.Get("team"),
+		Group:      query.Get("group"),
+		Category:   query.Get("category"),
+		Technology: query.Get("technology"),
+		Status:     core.Status(query.Get("status")),
+	}
+	for _, property := range query["property"] {
+		key, value := property, "*"
+		if i := strings.Index(property, "="); i >= 0 {
+			key, value = property[:i], property[i+1:]
+		}
+		if key == "" {
+			return nil, errors.New(fmt.Sprintf("Invalid property filter '%v' - use key=value or key", property))
+		}
+		if selector.Properties == nil {
+			selector.Properties = make(map[string]string)
+		}
+		selector.Properties[key] = value
+	}
+	return selector, nil
+}
+
+func filterApplications(applications []*core.Application, selector *core.ApplicationSelector) []*core.Application {
+	filtered := []*core.Application{}
+	for _, app := range applications {
+		if selector.Matches(app) {
+			filtered = append(filtered, app)
+		}
+	}
+	return filtered
+}
+
+// findApiApplication - the matching application of the name in the path. Writes the error response if not found
+func findApiApplication(w http.ResponseWriter, r *http.Request, project *core.Project, selector *core.ApplicationSelector) (*core.Application, bool) {
+	name := mux.Vars(r)["name"]
+	for _, app := range project.Applications {
+		if app.Name == name && selector.Matches(app) {
+			return app, true
+		}
+	}
+	writeApiError(w, http.StatusNotFound, errors.New(fmt.Sprintf("Application with name '%v' not found", name)))
+	return nil, false
+}
+
+func findGroup(group *core.ApplicationsByGroup, qualifiedName string) *core.ApplicationsByGroup {
+	if !group.IsRoot && group.QualifiedGroupName == qualifiedName {
+		return group
+	}
+	for _, subGroup := range group.SubGroups {
+		if found := findGroup(subGroup, qualifiedName); found != nil {
+			return found
+		}
+	}
+	return nil
+}
+
+func newApiTeamRelation(team string, relation *core.ClusterRelation) *ApiTeamRelation {
+	apiRelation := &ApiTeamRelation{Team: team, Relationship: relation.StrongestRelationship()}
+	for _, edge := range relation.Edges {
+		apiRelation.Dependencies = append(apiRelation.Dependencies, edge.From.Name+" -> "+edge.Reference())
+	}
+	return apiRelation
+}
+
+func writeApiJson(w http.ResponseWriter, status int, result interface{}) {
+	b, err := json.Marshal(result)
+	if err != nil {
+		writeApiError(w, http.StatusInternalServerError, err)
+		return
+	}
+	w.Header().Set("Content-Type", "application/json")
+	w.WriteHeader(status)
+	_, _ = w.Write(b)
+}
+
+func writeApiError(w http.ResponseWriter, status int, err error) {
+	b, _ := json.Marshal(ApiError{Error: err.Error()})
+	w.Header().Set("Content-Type", "application/json")
+	w.WriteHeader(status)
+	_, _ = w.Write(b)
+}
@@ -0,0 +1,173 @@
+package web
+
+import (
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"reflect"
+	"testing"
+
+	"github.com/AOEpeople/vistecture/v2/model/core"
+	"github.com/gorilla/mux"
+)
+
+func createTestApiController(t *testing.T) *ApiController {
+	definitions := map[string]string{"apps/crm.yml": "name: crm\ngroup: backend/customers\ndependencies:\n- reference: erp\n"}
+	for name, content := range testDefinitions {
+		definitions[name] = content
+	}
+	projectModel, _ := createTestModel(t, definitions)
+	controller := &ApiController{}
+	controller.Inject(projectModel)
+	return controller
+}
+
+// apiGet - calls the handler with the path variables and decodes the json response into result. Returns the status code
+func apiGet(t *testing.T, handler http.HandlerFunc, url string, vars map[string]string, result interface{}) int {
+	response := httptest.NewRecorder()
+	handler(response, mux.SetURLVars(httptest.NewRequest(http.MethodGet, url, nil), vars))
+	if contentType := response.Header().Get("Content-Type"); contentType != "application/json" {
+		t.Errorf("Expected json for %v, got %v", url, contentType)
+	}
+	if err := json.Unmarshal(response.Body.Bytes(), result); err != nil {
+		t.Fatalf("Cannot decode response of %v: %v %v", url, err, response.Body.String())
+	}
+	return response.Code
+}
+
+func applicationNames(applications []*core.Application) []string {
+	names := []string{}
+	for _, app := range applications {
+		names = append(names, app.Name)
+	}
+	return names
+}
+
+func TestApiController_Applications(t *testing.T) {
+	controller := createTestApiController(t)
+
+	for url, expected := range map[string][]string{
+		"/api/v1/applications":                          {"checkout", "crm", "payment", "shop"},
+		"/api/v1/applications?team=team1":               {"checkout", "shop"},
+		"/api/v1/applications?group=backend":            {"checkout", "crm", "payment"},
+		"/api/v1/applications?subview=checkout&name=p*": {"payment"},
+		"/api/v1/applications?status=planned":           {"shop"},
+	} {
+		var applications []*core.Application
+		if status := apiGet(t, controller.ApplicationsAction, url, nil, &applications); status != http.StatusOK {
+			t.Errorf("Expected status 200 for %v, got %v", url, status)
+		}
+		if names := applicationNames(applications); !reflect.DeepEqual(names, expected) {
+			t.Errorf("Expected %v for %v, got %v", expected, url, names)
+		}
+	}
+
+	var apiError ApiError
+	if status := apiGet(t, controller.ApplicationsAction, "/api/v1/applications?property==value", nil, &apiError); status != http.StatusBadRequest || apiError.Error == "" {
+		t.Errorf("Expected 400 for an invalid property filter, got %v %v", status, apiError)
+	}
+	if status := apiGet(t, controller.ApplicationsAction, "/api/v1/applications?subview=unknown", nil, &apiError); status != http.StatusNotFound {
+		t.Errorf("Expected 404 for an unknown subview, got %v", status)
+	}
+}
+
+func TestApiController_Application(t *testing.T) {
+	controller := createTestApiController(t)
+
+	var application ApiApplication
+	if status := apiGet(t, controller.ApplicationAction, "/api/v1/applications/checkout", map[string]string{"name": "checkout"}, &application); status != http.StatusOK {
+		t.Fatalf("Expected status 200, got %v", status)
+	}
+	if application.Name != "checkout" || !reflect.DeepEqual(application.DependencyApplications, []string{"payment"}) || !reflect.DeepEqual(application.DependentApplications, []string{"shop"}) {
+		t.Errorf("Expected checkout depending on payment and used by shop, got %v %v %v", application.Name, application.DependencyApplications, application.DependentApplications)
+	}
+
+	var apiError ApiError
+	if status := apiGet(t, controller.ApplicationAction, "/api/v1/applications/checkout?team=team2", map[string]string{"name": "checkout"}, &apiError); status != http.StatusNotFound || apiError.Error != "Application with name 'checkout' not found" {
+		t.Errorf("Expected 404 for an application not matching the filter, got %v %v", status, apiError)
+	}
+
+	var services []core.Service
+	if status := apiGet(t, controller.ServicesAction, "/api/v1/applications/payment/services", map[string]string{"name": "payment"}, &services); status != http.StatusOK || len(services) != 1 || services[0].Name != "api" {
+		t.Errorf("Expected the api of payment, got %v %v", status, services)
+	}
+	response := httptest.NewRecorder()
+	controller.ServicesAction(response, mux.SetURLVars(httptest.NewRequest(http.MethodGet, "/api/v1/applications/shop/services", nil), map[string]string{"name": "shop"}))
+	if response.Body.String() != "[]" {
+		t.Errorf("Expected an empty list for an application without services, got %v", response.Body.String())
+	}
+}
+
+func TestApiController_Teams(t *testing.T) {
+	controller := createTestApiController(t)
+
+	var teams []*ApiTeam
+	apiGet(t, controller.TeamsAction, "/api/v1/teams", nil, &teams)
+	expected := []*ApiTeam{
+		{Name: core.NOTEAM, Applications: []string{"crm"}},
+		{Name: "team1", Applications: []string{"checkout", "shop"}},
+		{Name: "team2", Applications: []string{"payment"}},
+	}
+	if !reflect.DeepEqual(teams, expected) {
+		t.Errorf("Expected teams %v, got %v", expected, teams)
+	}
+
+	var team ApiTeamDetails
+	if status := apiGet(t, controller.TeamAction, "/api/v1/teams/team2", map[string]string{"team": "team2"}, &team); status != http.StatusOK {
+		t.Fatalf("Expected status 200, got %v", status)
+	}
+	expectedDependents := []*ApiTeamRelation{{Team: "team1", Dependencies: []string{"checkout -> payment.api"}}}
+	if len(team.Dependencies) != 0 || !reflect.DeepEqual(team.Dependents, expectedDependents) {
+		t.Errorf("Expected team2 used by team1, got %v %v", team.Dependencies, team.Dependents)
+	}
+
+	var apiError ApiError
+	if status := apiGet(t, controller.TeamAction, "/api/v1/teams/unknown", map[string]string{"team": "unknown"}, &apiError); status != http.StatusNotFound {
+		t.Errorf("Expected 404 for an unknown team, got %v", status)
+	}
+}
+
+func TestApiController_GroupsAndSubViews(t *testing.T) {
+	controller := createTestApiController(t)
+
+	var group core.ApplicationsByGroup
+	if status := apiGet(t, controller.GroupAction, "/api/v1/groups/backend", map[string]string{"qualifiedName": "backend"}, &group); status != http.StatusOK {
+		t.Fatalf("Expected status 200, got %v", status)
+	}
+	if group.QualifiedGroupName != "backend" || len(group.Applications) != 2 || len(group.SubGroups) != 1 || group.SubGroups[0].QualifiedGroupName != "backend/customers" {
+		t.Errorf("Expected backend with 2 applications and the subgroup customers, got %#v", group)
+	}
+	var apiError ApiError
+	if status := apiGet(t, controller.GroupAction, "/api/v1/groups/unknown", map[string]string{"qualifiedName": "unknown"}, &apiError); status != http.StatusNotFound {
+		t.Errorf("Expected 404 for an unknown group, got %v", status)
+	}
+
+	var subViews []string
+	if apiGet(t, controller.SubViewsAction, "/api/v1/subviews", nil, &subViews); !reflect.DeepEqual(subViews, []string{"checkout"}) {
+		t.Errorf("Expected the subview checkout, got %v", subViews)
+	}
+	var subView core.Project
+	if status := apiGet(t, controller.SubViewAction, "/api/v1/subviews/checkout?team=team2", map[string]string{"name": "checkout"}, &subView); status != http.StatusOK {
+		t.Fatalf("Expected status 200, got %v", status)
+	}
+	if names := applicationNames(subView.Applications); !reflect.DeepEqual(names, []string{"payment"}) {
+		t.Errorf("Expected payment of the subview, got %v", names)
+	}
+	if status := apiGet(t, controller.SubViewAction, "/api/v1/subviews/unknown", map[string]string{"name": "unknown"}, &apiError); status != http.StatusNotFound {
+		t.Errorf("Expected 404 for an unknown subview, got %v", status)
+	}
+}
+
+func TestApiController_Missing(t *testing.T) {
+	controller := createTestApiController(t)
+
+	var missing []*ApiMissingDependency
+	apiGet(t, controller.MissingAction, "/api/v1/missing", nil, &missing)
+	if len(missing) != 1 || missing[0].Application != "crm" || missing[0].Reference != "erp" {
+		t.Errorf("Expected the missing erp of crm, got %v", missing)
+	}
+	missing = nil
+	if apiGet(t, controller.MissingAction, "/api/v1/missing?team=team1", nil, &missing); len(missing) != 0 {
+		t.Errorf("Expected no missing dependencies of team1, got %v", missing)
+	}
+}
@@ -0,0 +1,67 @@
+package web
+
+import (
+	"encoding/json"
+	"fmt"
+	"net/http"
+	"time"
+)
+
+type (
+	//ReloadEvent - data of the Server-Sent Events about the project model
+	ReloadEvent struct {
+		Version int `json:"version"`
+	}
+)
+
+const (
+	//EVENT_VERSION - sent when the stream is opened with the current version of the project model
+	EVENT_VERSION = "version"
+	//EVENT_RELOAD - sent after the project model was reloaded successfully
+	EVENT_RELOAD = "reload"
+	//EVENT_KEEPALIVE - a comment is sent in this interval, so that proxies keep the stream open and closed connections are noticed
+	EVENT_KEEPALIVE = 30 * time.Second
+	//EVENT_RETRY - tells the browser how long to wait before reconnecting
+	EVENT_RETRY = time.Second
+)
+
+//EventsAction - streams Server-Sent Events: "version" with the current version of the project model when connected and "reload" after every successful reload.
+// The stream stays open until the client goes away - it must not be served with a write timeout. The browser compares the versions to detect reloads that happened while it was reconnecting
+func (p *ProjectController) EventsAction(w http.ResponseWriter, r *http.Request) {
+	flusher, ok := w.(http.Flusher)
+	if !ok {
+		http.Error(w, "Streaming not supported", http.StatusInternalServerError)
+		return
+	}
+	reloads := p.projectModel.Subscribe()
+	defer p.projectModel.Unsubscribe(reloads)
+
+	w.Header().Set("Content-Type", "text/event-stream")
+	w.Header().Set("Cache-Control", "no-cache")
+	w.Header().Set("Connection", "keep-alive")
+	_, _ = fmt.Fprintf(w, "retry: %v\n\n", EVENT_RETRY.Milliseconds())
+	writeEvent(w, EVENT_VERSION, p.projectModel.Snapshot().Version)
+	flusher.Flush()
+
+	keepalive := time.NewTicker(EVENT_KEEPALIVE)
+	defer keepalive.Stop()
+	for {
+		select {
+		case <-r.Context().Done():
+			return
+		case <-keepalive.C:
+			if _, err := fmt.Fprint(w, ": keepalive\n\n"); err != nil {
+				return
+			}
+			flusher.Flush()
+		case version := <-reloads:
+			writeEvent(w, EVENT_RELOAD, version)
+			flusher.Flush()
+		}
+	}
+}
+
+func writeEvent(w http.ResponseWriter, event string, version int) {
+	b, _ := json.Marshal(ReloadEvent{Version: version})
+	_, _ = fmt.Fprintf(w, "event: %v\ndata: %v\n\n", event, string(b))
+}
@@ -0,0 +1,66 @@
+package web
+
+import (
+	"bufio"
+	"net/http"
+	"net/http/httptest"
+	"path/filepath"
+	"strings"
+	"testing"
+	"time"
+)
+
+func TestProjectController_EventsAction(t *testing.T) {
+	projectModel, folder := createTestModel(t, testDefinitions)
+	controller := ProjectController{}
+	controller.Inject(projectModel, false)
+	server := httptest.NewServer(http.HandlerFunc(controller.EventsAction))
+	defer server.Close()
+
+	response, err := http.Get(server.URL)
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer response.Body.Close()
+	if contentType := response.Header.Get("Content-Type"); contentType != "text/event-stream" {
+		t.Fatalf("Expected an event stream, got %v", contentType)
+	}
+	events := make(chan string)
+	go func() {
+		scanner := bufio.NewScanner(response.Body)
+		var event []string
+		for scanner.Scan() {
+			if scanner.Text() != "" {
+				event = append(event, scanner.Text())
+				continue
+			}
+			events <- strings.Join(event, "|")
+			event = nil
+		}
+		close(events)
+	}()
+	nextEvent := func() string {
+		select {
+		case event := <-events:
+			return event
+		case <-time.After(5 * time.Second):
+			t.Fatal("Expected an event")
+			return ""
+		}
+	}
+
+	if retry := nextEvent(); retry != "retry: 1000" {
+		t.Errorf("Expected the retry interval, got %q", retry)
+	}
+	if version := nextEvent(); version != `event: version|data: {"version":1}` {
+		t.Errorf("Expected the current version, got %q", version)
+	}
+
+	writeTestFile(t, filepath.Join(folder, "apps/search.yml"), "name: search\n")
+	if err := projectModel.Reload(); err != nil {
+		t.Fatal(err)
+	}
+	if reload := nextEvent(); reload != `event: reload|data: {"version":2}` {
+		t.Errorf("Expected the reload of version 2, got %q", reload)
+	}
+}
@@ -0,0 +1,220 @@
+package web
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"hash/fnv"
+	"net/http"
+	"strings"
+	"sync"
+	"time"
+
+	"github.com/AOEpeople/vistecture/v2/application"
+	"github.com/AOEpeople/vistecture/v2/model/graphviz"
+	"github.com/gorilla/mux"
+)
+
+type (
+	//GraphController - renders the graphviz graphs of the served project as DOT, SVG or PNG. Rendered graphs are cached until the project model is reloaded
+	GraphController struct {
+		projectModel *application.ProjectModel
+		iconPath     string
+		mutex        sync.Mutex
+		//cacheVersion - the version of the project model the cached graphs belong to
+		cacheVersion int
+		cache        map[string][]byte
+		//rendering - the graphs that are drawn right now, concurrent requests for the same graph wait for them
+		rendering map[string]*graphRendering
+	}
+
+	// graphRendering - a graph that is drawn by one request and awaited by the others
+	graphRendering struct {
+		done    chan struct{}
+		content []byte
+		err     *graphError
+		//aborted - the drawing request went away before the graph was done
+		aborted bool
+	}
+
+	// graphRequest - the parameters of a graph
+	graphRequest struct {
+		view            string
+		format          string
+		subView         string
+		application     string
+		hidePlanned     bool
+		summaryRelation bool
+	}
+
+	// graphError - an error with the http status
+	graphError struct {
+		status int
+		err    error
+	}
+)
+
+const (
+	GRAPH_VIEW_COMPLETE    = "complete"
+	GRAPH_VIEW_APPLICATION = "application"
+	GRAPH_VIEW_GROUP       = "group"
+	GRAPH_VIEW_TEAM        = "team"
+
+	//GRAPH_RENDER_TIMEOUT - the time the dot command gets to render a graph - below the request timeout of the server
+	GRAPH_RENDER_TIMEOUT = 10 * time.Second
+)
+
+var graphContentTypes = map[string]string{
+	graphviz.FORMAT_DOT: "text/vnd.graphviz; charset=utf-8",
+	graphviz.FORMAT_SVG: "image/svg+xml",
+	graphviz.FORMAT_PNG: "image/png",
+}
+
+func (g *GraphController) Inject(projectModel *application.ProjectModel, iconPath string) {
+	g.projectModel = projectModel
+	g.iconPath = iconPath
+}
+
+//GraphAction - returns the graph of the view (complete, application, group or team) in the format (dot, svg or png) of the path "/graphs/{view}.{format}".
+// Query parameters: subview, application (required for the application view), hidePlanned and summaryRelation
+func (g *GraphController) GraphAction(w http.ResponseWriter, r *http.Request) {
+	query := r.URL.Query()
+	request := graphRequest{
+		view:            mux.Vars(r)["view"],
+		format:          mux.Vars(r)["format"],
+		subView:         query.Get("subview"),
+		application:     query.Get("application"),
+		hidePlanned:     query.Get("hidePlanned") != "",
+		summaryRelation: query.Get("summaryRelation") != "",
+	}
+	contentType, known := graphContentTypes[request.format]
+	if !known {
+		http.Error(w, fmt.Sprintf("Unknown format '%v' - use %v, %v or %v", request.format, graphviz.FORMAT_DOT, graphviz.FORMAT_SVG, graphviz.FORMAT_PNG), http.StatusBadRequest)
+		return
+	}
+	if request.hidePlanned && request.view != GRAPH_VIEW_COMPLETE {
+		http.Error(w, fmt.Sprintf("hidePlanned is only supported by the %v view", GRAPH_VIEW_COMPLETE), http.StatusBadRequest)
+		return
+	}
+	snapshot := g.projectModel.Snapshot()
+	keyHash := fnv.New32a()
+	_, _ = keyHash.Write([]byte(request.key()))
+	//the load time keeps the etag unique over restarts of the server, which start the versions again
+	eTag := fmt.Sprintf("\"%x-%v-%x\"", snapshot.LoadedAt.UnixNano(), snapshot.Version, keyHash.Sum32())
+	if r.Header.Get("If-None-Match") == eTag {
+		w.WriteHeader(http.StatusNotModified)
+		return
+	}
+	content, err := g.graph(r.Context(), snapshot, request)
+	if err != nil {
+		http.Error(w, err.err.Error(), err.status)
+		return
+	}
+	w.Header().Set("Content-Type", contentType)
+	w.Header().Set("Cache-Control", "no-cache")
+	w.Header().Set("ETag", eTag)
+	_, _ = w.Write(content)
+}
+
+// graph - the cached graph of the request, or the graph drawn by this or a concurrent request for the same graph
+func (g *GraphController) graph(ctx context.Context, snapshot *application.ProjectSnapshot, request graphRequest) ([]byte, *graphError) {
+	key := request.key()
+	renderingKey := fmt.Sprintf("%v|%v", snapshot.Version, key)
+	for {
+		g.mutex.Lock()
+		if content, found := g.cache[key]; found && g.cacheVersion == snapshot.Version {
+			g.mutex.Unlock()
+			return content, nil
+		}
+		if running, found := g.rendering[renderingKey]; found {
+			g.mutex.Unlock()
+			select {
+			case <-running.done:
+			case <-ctx.Done():
+				return nil, &graphError{http.StatusServiceUnavailable, ctx.Err()}
+			}
+			if running.aborted {
+				//the drawing request went away - draw it for this one
+				continue
+			}
+			return running.content, running.err
+		}
+		running := &graphRendering{done: make(chan struct{})}
+		if g.rendering == nil {
+			g.rendering = make(map[string]*graphRendering)
+		}
+		g.rendering[renderingKey] = running
+		g.mutex.Unlock()
+
+		running.content, running.err = g.draw(ctx, snapshot, request)
+		running.aborted = running.err != nil && ctx.Err() != nil
+		g.mutex.Lock()
+		delete(g.rendering, renderingKey)
+		g.mutex.Unlock()
+		if running.err == nil {
+			g.store(snapshot.Version, key, running.content)
+		}
+		close(running.done)
+		return running.content, running.err
+	}
+}
+
+// draw - the graph of the request in the requested format
+func (g *GraphController) draw(ctx context.Context, snapshot *application.ProjectSnapshot, request graphRequest) ([]byte, *graphError) {
+	project, err := snapshot.SubView(request.subView)
+	if err != nil {
+		return nil, &graphError{http.StatusNotFound, err}
+	}
+	var graph string
+	switch request.view {
+	case GRAPH_VIEW_COMPLETE:
+		graph = graphviz.CreateProjectDrawer(project, g.iconPath).DrawComplete(request.hidePlanned)
+	case GRAPH_VIEW_APPLICATION:
+		component, err := project.FindApplication(request.application)
+		if err != nil {
+			return nil, &graphError{http.StatusNotFound, err}
+		}
+		graph = graphviz.CreateProjectDrawer(project, g.iconPath).DrawComponent(component)
+	case GRAPH_VIEW_GROUP:
+		graph = graphviz.CreateGroupDrawer(project, request.summaryRelation).DrawComplete()
+	case GRAPH_VIEW_TEAM:
+		graph = graphviz.CreateTeamDependencyDrawer(project, request.summaryRelation).DrawComplete()
+	default:
+		return nil, &graphError{http.StatusNotFound, errors.New(fmt.Sprintf("Unknown view '%v' - use %v, %v, %v or %v", request.view, GRAPH_VIEW_COMPLETE, GRAPH_VIEW_APPLICATION, GRAPH_VIEW_GROUP, GRAPH_VIEW_TEAM))}
+	}
+	if request.format == graphviz.FORMAT_DOT {
+		return []byte(graph), nil
+	}
+	if !graphviz.IsRendererAvailable() {
+		return nil, &graphError{http.StatusNotImplemented, errors.New(fmt.Sprintf("Cannot render %v - the graphviz command '%v' is not installed, use the dot format", request.format, graphviz.DOT_COMMAND))}
+	}
+	renderCtx, cancel := context.WithTimeout(ctx, GRAPH_RENDER_TIMEOUT)
+	defer cancel()
+	rendered, err := graphviz.Render(renderCtx, graph, request.format)
+	if err != nil {
+		if renderCtx.Err() != nil {
+			return nil, &graphError{http.StatusServiceUnavailable, err}
+		}
+		return nil, &graphError{http.StatusInternalServerError, err}
+	}
+	return rendered, nil
+}
+
+// store - caches the graph, the graphs of older versions are dropped
+func (g *GraphController) store(version int, key string, content []byte) {
+	g.mutex.Lock()
+	defer g.mutex.Unlock()
+	if version < g.cacheVersion {
+		return
+	}
+	if version > g.cacheVersion || g.cache == nil {
+		g.cacheVersion = version
+		g.cache = make(map[string][]byte)
+	}
+	g.cache[key] = content
+}
+
+// key - identifies the graph within a version of the project model
+func (r graphRequest) key() string {
+	return strings.Join([]string{r.view, r.format, r.subView, r.application, fmt.Sprint(r.hidePlanned), fmt.Sprint(r.summaryRelation)}, "|")
+}
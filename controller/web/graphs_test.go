@@ -0,0 +1,135 @@
+package web
+
+import (
+	"io/ioutil"
+	"net/http"
+	"net/http/httptest"
+	"os"
+	"path/filepath"
+	"runtime"
+	"strings"
+	"sync"
+	"testing"
+
+	"github.com/gorilla/mux"
+)
+
+func createTestGraphController(t *testing.T) (*GraphController, string) {
+	projectModel, folder := createTestModel(t, testDefinitions)
+	controller := &GraphController{}
+	controller.Inject(projectModel, "")
+	return controller, folder
+}
+
+func getGraph(controller *GraphController, url string, vars map[string]string, header http.Header) *httptest.ResponseRecorder {
+	request := mux.SetURLVars(httptest.NewRequest(http.MethodGet, url, nil), vars)
+	for key, values := range header {
+		request.Header[key] = values
+	}
+	response := httptest.NewRecorder()
+	controller.GraphAction(response, request)
+	return response
+}
+
+// useFakeDot - puts a dot command on the PATH that counts its calls in the returned file and prints an svg
+func useFakeDot(t *testing.T) string {
+	if runtime.GOOS == "windows" {
+		t.Skip("the fake dot command is a shell script")
+	}
+	folder := t.TempDir()
+	calls := filepath.Join(folder, "calls")
+	script := "#!/bin/sh\necho call >> " + calls + "\nsleep 0.2\ncat > /dev/null\necho '<svg/>'\n"
+	if err := ioutil.WriteFile(filepath.Join(folder, "dot"), []byte(script), 0755); err != nil {
+		t.Fatal(err)
+	}
+	path := os.Getenv("PATH")
+	_ = os.Setenv("PATH", folder+string(os.PathListSeparator)+path)
+	t.Cleanup(func() {
+		_ = os.Setenv("PATH", path)
+	})
+	return calls
+}
+
+func TestGraphController_Dot(t *testing.T) {
+	controller, folder := createTestGraphController(t)
+
+	response := getGraph(controller, "/graphs/complete.dot", map[string]string{"view": "complete", "format": "dot"}, nil)
+	if response.Code != http.StatusOK || !strings.HasPrefix(response.Body.String(), "digraph") || !strings.Contains(response.Body.String(), "shop") {
+		t.Fatalf("Expected the complete graph, got %v %v", response.Code, response.Body.String())
+	}
+	if contentType := response.Header().Get("Content-Type"); contentType != "text/vnd.graphviz; charset=utf-8" {
+		t.Errorf("Expected the graphviz content type, got %v", contentType)
+	}
+	eTag := response.Header().Get("ETag")
+
+	hidden := getGraph(controller, "/graphs/complete.dot?hidePlanned=1", map[string]string{"view": "complete", "format": "dot"}, nil)
+	if strings.Contains(hidden.Body.String(), "shop") || hidden.Header().Get("ETag") == eTag {
+		t.Errorf("Expected the graph without the planned shop and another etag, got %v %v", hidden.Header().Get("ETag"), hidden.Body.String())
+	}
+
+	notModified := getGraph(controller, "/graphs/complete.dot", map[string]string{"view": "complete", "format": "dot"}, http.Header{"If-None-Match": {eTag}})
+	if notModified.Code != http.StatusNotModified {
+		t.Errorf("Expected 304 for the etag of the graph, got %v", notModified.Code)
+	}
+
+	writeTestFile(t, filepath.Join(folder, "apps/search.yml"), "name: search\n")
+	if err := controller.projectModel.Reload(); err != nil {
+		t.Fatal(err)
+	}
+	reloaded := getGraph(controller, "/graphs/complete.dot", map[string]string{"view": "complete", "format": "dot"}, http.Header{"If-None-Match": {eTag}})
+	if reloaded.Code != http.StatusOK || reloaded.Header().Get("ETag") == eTag || !strings.Contains(reloaded.Body.String(), "search") {
+		t.Errorf("Expected the reloaded graph with a new etag, got %v %v", reloaded.Code, reloaded.Header().Get("ETag"))
+	}
+}
+
+func TestGraphController_InvalidRequests(t *testing.T) {
+	controller, _ := createTestGraphController(t)
+
+	for url, expected := range map[string]int{
+		"/graphs/complete.gif":                        http.StatusBadRequest,
+		"/graphs/unknown.dot":                         http.StatusNotFound,
+		"/graphs/application.dot?application=unknown": http.StatusNotFound,
+		"/graphs/complete.dot?subview=unknown":        http.StatusNotFound,
+		"/graphs/team.dot?hidePlanned=1":              http.StatusBadRequest,
+		"/graphs/application.dot?application=shop":    http.StatusOK,
+		"/graphs/group.dot?summaryRelation=1":         http.StatusOK,
+		"/graphs/team.dot?subview=checkout":           http.StatusOK,
+	} {
+		name := strings.TrimPrefix(strings.SplitN(url, "?", 2)[0], "/graphs/")
+		vars := map[string]string{"view": strings.Split(name, ".")[0], "format": strings.Split(name, ".")[1]}
+		if response := getGraph(controller, url, vars, nil); response.Code != expected {
+			t.Errorf("Expected %v for %v, got %v %v", expected, url, response.Code, response.Body.String())
+		}
+	}
+}
+
+func TestGraphController_RendersOncePerGraph(t *testing.T) {
+	calls := useFakeDot(t)
+	controller, _ := createTestGraphController(t)
+
+	var wait sync.WaitGroup
+	responses := make([]*httptest.ResponseRecorder, 3)
+	for i := range responses {
+		wait.Add(1)
+		go func(i int) {
+			defer wait.Done()
+			responses[i] = getGraph(controller, "/graphs/team.svg", map[string]string{"view": "team", "format": "svg"}, nil)
+		}(i)
+	}
+	wait.Wait()
+	for _, response := range responses {
+		if response.Code != http.StatusOK || response.Body.String() != "<svg/>\n" || response.Header().Get("Content-Type") != "image/svg+xml" {
+			t.Errorf("Expected the rendered svg, got %v %v", response.Code, response.Body.String())
+		}
+	}
+	if cached := getGraph(controller, "/graphs/team.svg", map[string]string{"view": "team", "format": "svg"}, nil); cached.Code != http.StatusOK {
+		t.Errorf("Expected the cached svg, got %v", cached.Code)
+	}
+	rendered, err := ioutil.ReadFile(calls)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if count := strings.Count(string(rendered), "call"); count != 1 {
+		t.Errorf("Expected the graph to be rendered once, got %v renderings", count)
+	}
+}
@@ -10,18 +10,20 @@ import (
 	"net/http"
 	"os"
 	"path/filepath"
+	"strconv"
 	"strings"
+	"time"
 
 	"github.com/AOEpeople/vistecture/v2/application"
+	"github.com/AOEpeople/vistecture/v2/model/analyze"
 	"github.com/AOEpeople/vistecture/v2/model/core"
+	"github.com/AOEpeople/vistecture/v2/model/graphviz"
 )
 
 type (
 	ProjectController struct {
-		projectDefinitions    *application.ProjectConfig
-		projectLoader         *application.ProjectLoader
-		definitionsBaseFolder string
-		skipValidation        bool
+		projectModel   *application.ProjectModel
+		skipValidation bool
 	}
 
 	Result struct {
@@ -36,6 +38,18 @@ type (
 		MissingApplications MissingApplications `json:"missingApplications"`
 		//UnincludedApplications - list of applications that are referenced but not included in current selection (e.g. because of selected subview or due to a filter)
 		UnincludedApplications MissingApplications `json:"unincludedApplications"`
+		//Version - the version of the served project model (incremented whenever the definitions are reloaded)
+		Version int `json:"version"`
+		//ReloadErrors - errors of the last failed reload, the previous version is served until the definitions are fixed
+		ReloadErrors []string `json:"reloadErrors"`
+	}
+
+	//ModelStatus - the state of the served project model
+	ModelStatus struct {
+		Version      int       `json:"version"`
+		LoadedAt     time.Time `json:"loadedAt"`
+		Errors       []string  `json:"errors"`
+		ReloadErrors []string  `json:"reloadErrors"`
 	}
 
 	AvailableGroups struct {
@@ -66,10 +80,8 @@ var (
 	builtInTemplates embed.FS
 )
 
-func (p *ProjectController) Inject(definitions *application.ProjectConfig, projectLoader *application.ProjectLoader, definitionsBaseFolder string, skipValidation bool) {
-	p.projectDefinitions = definitions
-	p.projectLoader = projectLoader
-	p.definitionsBaseFolder = definitionsBaseFolder
+func (p *ProjectController) Inject(projectModel *application.ProjectModel, skipValidation bool) {
+	p.projectModel = projectModel
 	p.skipValidation = skipValidation
 }
 
@@ -106,15 +118,16 @@ func (p *ProjectController) DataAction(w http.ResponseWriter, r *http.Request, d
 	result := Result{}
 
 	subViewName, _ := r.URL.Query()["subview"]
-	completeProject, err := p.projectLoader.LoadProject(p.projectDefinitions, p.definitionsBaseFolder, "")
-	if err != nil {
+	snapshot := p.projectModel.Snapshot()
+	result.Version = snapshot.Version
+	for _, err := range snapshot.Errors {
 		result.AddError(err)
 	}
-	if completeProject == nil {
-		p.writeJson(w, result, false)
-		return
+	for _, err := range p.projectModel.ReloadErrors() {
+		result.ReloadErrors = append(result.ReloadErrors, err.Error())
 	}
-	project, err := p.projectLoader.LoadProject(p.projectDefinitions, p.definitionsBaseFolder, strings.Join(subViewName, ""))
+	completeProject := snapshot.Project
+	project, err := snapshot.SubView(strings.Join(subViewName, ""))
 	if err != nil {
 		result.AddError(err)
 	}
@@ -137,6 +150,7 @@ func (p *ProjectController) DataAction(w http.ResponseWriter, r *http.Request, d
 		project = &core.Project{
 			Name:         project.Name,
 			Applications: filteredApplications,
+			Vocabulary:   project.Vocabulary,
 		}
 	}
 
@@ -149,10 +163,12 @@ func (p *ProjectController) DataAction(w http.ResponseWriter, r *http.Request, d
 		return
 	}
 
-	for _, subViewConfig := range p.projectDefinitions.SubViewConfig {
+	for _, subViewConfig := range snapshot.Config.SubViewConfig {
 		result.AvailableSubViews = append(result.AvailableSubViews, subViewConfig.Name)
 	}
 
+	completeGraph := completeProject.Graph()
+	graph := project.Graph()
 	allMissingApps := new(MissingApplications)
 	allUnincludedApps := new(MissingApplications)
 	for _, app := range project.Applications {
@@ -181,11 +197,11 @@ func (p *ProjectController) DataAction(w http.ResponseWriter, r *http.Request, d
 			return false
 		}
 
-		for _, missing := range app.GetMissingDependencies(completeProject) {
+		for _, missing := range completeGraph.MissingReferences(app.Name) {
 			dependenciesToMissingApplications = append(dependenciesToMissingApplications, newMissingApp(missing))
 			allMissingApps = allMissingApps.Add(newMissingApp(missing))
 		}
-		for _, missing := range app.GetMissingDependencies(project) {
+		for _, missing := range graph.MissingReferences(app.Name) {
 			if isInList(missing, dependenciesToMissingApplications) {
 				continue
 			}
@@ -195,7 +211,7 @@ func (p *ProjectController) DataAction(w http.ResponseWriter, r *http.Request, d
 
 		result.ApplicationsDto = append(result.ApplicationsDto, &ApplicationDto{
 			Application:                          app,
-			DependenciesGrouped:                  app.GetDependenciesGrouped(project),
+			DependenciesGrouped:                  graph.DependenciesGrouped(app),
 			DependenciesToMissingApplications:    dependenciesToMissingApplications,
 			DependenciesToUnincludedApplications: dependenciesToUnincludedApplications,
 		})
@@ -212,6 +228,77 @@ func (p *ProjectController) DataAction(w http.ResponseWriter, r *http.Request, d
 
 }
 
+//MAX_SERVED_PATH_LENGTH - the highest maxLength of PathAction - finding all paths is exponential in the length
+const MAX_SERVED_PATH_LENGTH = analyze.DEFAULT_MAX_PATH_LENGTH
+
+//PathAction - returns the paths between the applications of the query parameters from and to (optional: maxLength up to MAX_SERVED_PATH_LENGTH, skipPlanned, skipOptional, subview) as JSON or with format=dot as graphviz graph
+func (p *ProjectController) PathAction(w http.ResponseWriter, r *http.Request) {
+	query := r.URL.Query()
+	project, err := p.projectModel.Snapshot().SubView(query.Get("subview"))
+	if err != nil {
+		http.Error(w, err.Error(), http.StatusBadRequest)
+		return
+	}
+	maxLength := 0
+	if query.Get("maxLength") != "" {
+		maxLength, err = strconv.Atoi(query.Get("maxLength"))
+		if err != nil || maxLength < 1 || maxLength > MAX_SERVED_PATH_LENGTH {
+			http.Error(w, fmt.Sprintf("Invalid maxLength '%v' - use 1 to %d", query.Get("maxLength"), MAX_SERVED_PATH_LENGTH), http.StatusBadRequest)
+			return
+		}
+	}
+	var projectAnalyzer analyze.ProjectAnalyzer
+	for _, parameter := range []struct {
+		name string
+		skip *bool
+	}{{"skipOptional", &projectAnalyzer.SkipOptionalDependencies}, {"skipPlanned", &projectAnalyzer.SkipPlannedDependencies}} {
+		if query.Get(parameter.name) == "" {
+			continue
+		}
+		*parameter.skip, err = strconv.ParseBool(query.Get(parameter.name))
+		if err != nil {
+			http.Error(w, fmt.Sprintf("Invalid %v '%v' - use true or false", parameter.name, query.Get(parameter.name)), http.StatusBadRequest)
+			return
+		}
+	}
+	pathReport, err := projectAnalyzer.FindPaths(project, query.Get("from"), query.Get("to"), maxLength)
+	if err != nil {
+		http.Error(w, err.Error(), http.StatusBadRequest)
+		return
+	}
+	if query.Get("format") == "dot" {
+		w.Header().Set("Content-Type", "text/vnd.graphviz; charset=utf-8")
+		_, _ = fmt.Fprint(w, graphviz.CreatePathDrawer(pathReport).Draw())
+		return
+	}
+	b, err := json.Marshal(pathReport)
+	if err != nil {
+		http.Error(w, err.Error(), http.StatusInternalServerError)
+		return
+	}
+	w.Header().Set("Content-Type", "application/json")
+	_, _ = w.Write(b)
+}
+
+//StatusAction - returns the version, the load time and the errors of the served project model
+func (p *ProjectController) StatusAction(w http.ResponseWriter, r *http.Request) {
+	snapshot := p.projectModel.Snapshot()
+	status := ModelStatus{Version: snapshot.Version, LoadedAt: snapshot.LoadedAt, Errors: []string{}, ReloadErrors: []string{}}
+	for _, err := range snapshot.Errors {
+		status.Errors = append(status.Errors, err.Error())
+	}
+	for _, err := range p.projectModel.ReloadErrors() {
+		status.ReloadErrors = append(status.ReloadErrors, err.Error())
+	}
+	b, err := json.Marshal(status)
+	if err != nil {
+		http.Error(w, err.Error(), http.StatusInternalServerError)
+		return
+	}
+	w.Header().Set("Content-Type", "application/json")
+	_, _ = w.Write(b)
+}
+
 func (p *ProjectController) writeJson(w http.ResponseWriter, result Result, isHardError bool) {
 	b, err := json.Marshal(result)
 	if err != nil {
@@ -0,0 +1,122 @@
+package web
+
+import (
+	"encoding/json"
+	"io/ioutil"
+	"net/http"
+	"net/http/httptest"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+
+	"github.com/AOEpeople/vistecture/v2/application"
+)
+
+// testDefinitions - a project with a subview and three applications
+var testDefinitions = map[string]string{
+	"project.yml": "projectName: shop\nappDefinitionsPaths:\n- apps\nsubViews:\n- name: checkout\n  included-applications:\n  - checkout\n  - payment\n",
+	"apps/checkout.yml": `name: checkout
+team: team1
+group: backend
+summary: Creates orders and sends invoices
+provided-services:
+- name: api
+  type: api
+dependencies:
+- reference: payment.api
+`,
+	"apps/payment.yml": `name: payment
+team: team2
+group: backend
+provided-services:
+- name: api
+  type: api
+`,
+	"apps/shop.yml": `name: shop
+team: team1
+group: frontend
+status: planned
+dependencies:
+- reference: checkout.api
+`,
+}
+
+// createTestModel - writes the definitions into a temporary folder and loads the project model
+func createTestModel(t *testing.T, definitions map[string]string) (*application.ProjectModel, string) {
+	folder := t.TempDir()
+	for name, content := range definitions {
+		writeTestFile(t, filepath.Join(folder, name), content)
+	}
+	projectModel, err := application.CreateProjectModel(&application.ProjectLoader{}, filepath.Join(folder, "project.yml"))
+	if err != nil {
+		t.Fatal(err)
+	}
+	return projectModel, folder
+}
+
+func writeTestFile(t *testing.T, file string, content string) {
+	if err := os.MkdirAll(filepath.Dir(file), 0755); err != nil {
+		t.Fatal(err)
+	}
+	if err := ioutil.WriteFile(file, []byte(content), 0644); err != nil {
+		t.Fatal(err)
+	}
+}
+
+// getJson - calls the handler and decodes the json response into result
+func getJson(t *testing.T, handler http.HandlerFunc, url string, result interface{}) *httptest.ResponseRecorder {
+	response := httptest.NewRecorder()
+	handler(response, httptest.NewRequest(http.MethodGet, url, nil))
+	if response.Code == http.StatusOK && result != nil {
+		if err := json.Unmarshal(response.Body.Bytes(), result); err != nil {
+			t.Fatalf("Cannot decode response of %v: %v %v", url, err, response.Body.String())
+		}
+	}
+	return response
+}
+
+func TestProjectController_ServesLastGoodModelAfterFailedReload(t *testing.T) {
+	projectModel, folder := createTestModel(t, testDefinitions)
+	controller := ProjectController{}
+	controller.Inject(projectModel, false)
+	data := func(w http.ResponseWriter, r *http.Request) {
+		controller.DataAction(w, r, "")
+	}
+
+	var result Result
+	getJson(t, data, "/data", &result)
+	if result.Version != 1 || len(result.ApplicationsDto) != 3 || len(result.ReloadErrors) != 0 {
+		t.Fatalf("Expected version 1 with 3 applications, got %#v", result)
+	}
+
+	writeTestFile(t, filepath.Join(folder, "apps/payment.yml"), "name: [payment\n")
+	if err := projectModel.Reload(); err == nil {
+		t.Fatal("Expected the reload of the broken definition to fail")
+	}
+
+	result = Result{}
+	getJson(t, data, "/data?subview=checkout", &result)
+	if result.Version != 1 || len(result.ApplicationsDto) != 2 {
+		t.Errorf("Expected the subview of version 1 with 2 applications, got version %v with %v applications", result.Version, len(result.ApplicationsDto))
+	}
+	if len(result.ReloadErrors) != 1 || !strings.Contains(result.ReloadErrors[0], "payment.yml") {
+		t.Errorf("Expected the reload error of payment.yml, got %v", result.ReloadErrors)
+	}
+
+	var status ModelStatus
+	getJson(t, controller.StatusAction, "/status", &status)
+	if status.Version != 1 || len(status.ReloadErrors) != 1 || !strings.Contains(status.ReloadErrors[0], "payment.yml") {
+		t.Errorf("Expected version 1 with the reload error of payment.yml, got %#v", status)
+	}
+
+	writeTestFile(t, filepath.Join(folder, "apps/payment.yml"), testDefinitions["apps/payment.yml"])
+	if err := projectModel.Reload(); err != nil {
+		t.Fatal(err)
+	}
+	status = ModelStatus{}
+	getJson(t, controller.StatusAction, "/status", &status)
+	if status.Version != 2 || len(status.ReloadErrors) != 0 {
+		t.Errorf("Expected version 2 without reload errors, got %#v", status)
+	}
+}
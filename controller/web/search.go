@@ -0,0 +1,83 @@
+package web
+
+import (
+	"errors"
+	"fmt"
+	"net/http"
+	"strconv"
+	"strings"
+	"sync"
+
+	"github.com/AOEpeople/vistecture/v2/application"
+	"github.com/AOEpeople/vistecture/v2/model/search"
+)
+
+type (
+	//SearchController - full-text search over the served project. The search indexes are built on demand and kept until the project model is reloaded
+	SearchController struct {
+		projectModel *application.ProjectModel
+		mutex        sync.Mutex
+		//indexVersion - the version of the project model the indexes belong to
+		indexVersion int
+		//indexes - the search index of every requested subview ("" for the complete project)
+		indexes map[string]*search.Index
+	}
+)
+
+//DEFAULT_SEARCH_LIMIT - number of returned applications if the limit parameter is missing
+const DEFAULT_SEARCH_LIMIT = 20
+
+func (s *SearchController) Inject(projectModel *application.ProjectModel) {
+	s.projectModel = projectModel
+}
+
+//SearchAction - returns the applications matching the query parameter q as JSON, the best first with highlighted snippets.
+// Query parameters: q, limit (0 for all) and subview
+func (s *SearchController) SearchAction(w http.ResponseWriter, r *http.Request) {
+	query := r.URL.Query()
+	if strings.TrimSpace(query.Get("q")) == "" {
+		writeApiError(w, http.StatusBadRequest, errors.New("Query parameter q missing"))
+		return
+	}
+	limit := DEFAULT_SEARCH_LIMIT
+	if query.Get("limit") != "" {
+		var err error
+		if limit, err = strconv.Atoi(query.Get("limit")); err != nil {
+			writeApiError(w, http.StatusBadRequest, errors.New(fmt.Sprintf("Invalid limit '%v'", query.Get("limit"))))
+			return
+		}
+	}
+	index, err := s.index(query.Get("subview"))
+	if err != nil {
+		writeApiError(w, http.StatusNotFound, err)
+		return
+	}
+	results := index.Search(query.Get("q"), limit)
+	if results == nil {
+		results = []*search.Result{}
+	}
+	writeApiJson(w, http.StatusOK, results)
+}
+
+// index - the search index of the subview for the current version of the project model
+func (s *SearchController) index(subView string) (*search.Index, error) {
+	snapshot := s.projectModel.Snapshot()
+	s.mutex.Lock()
+	defer s.mutex.Unlock()
+	if snapshot.Version > s.indexVersion || s.indexes == nil {
+		s.indexVersion = snapshot.Version
+		s.indexes = make(map[string]*search.Index)
+	}
+	if index, found := s.indexes[subView]; found && snapshot.Version == s.indexVersion {
+		return index, nil
+	}
+	project, err := snapshot.SubView(subView)
+	if err != nil {
+		return nil, err
+	}
+	index := search.CreateIndex(project)
+	if snapshot.Version == s.indexVersion {
+		s.indexes[subView] = index
+	}
+	return index, nil
+}
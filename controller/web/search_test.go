@@ -0,0 +1,65 @@
+package web
+
+import (
+	"net/http"
+	"path/filepath"
+	"testing"
+	"unicode/utf16"
+
+	"github.com/AOEpeople/vistecture/v2/model/search"
+)
+
+func TestSearchController_SearchAction(t *testing.T) {
+	projectModel, folder := createTestModel(t, testDefinitions)
+	controller := &SearchController{}
+	controller.Inject(projectModel)
+
+	var results []*search.Result
+	if status := apiGet(t, controller.SearchAction, "/search?q=sends+invoices", nil, &results); status != http.StatusOK {
+		t.Fatalf("Expected status 200, got %v", status)
+	}
+	if len(results) != 1 || results[0].Application != "checkout" || results[0].Matches[0].Field != "summary" {
+		t.Fatalf("Expected the summary of checkout, got %v", results)
+	}
+
+	results = nil
+	if apiGet(t, controller.SearchAction, "/search?q=api&limit=1", nil, &results); len(results) != 1 {
+		t.Errorf("Expected one result with limit 1, got %v", len(results))
+	}
+	results = nil
+	if apiGet(t, controller.SearchAction, "/search?q=api&subview=checkout&limit=0", nil, &results); len(results) != 2 {
+		t.Errorf("Expected checkout and payment of the subview, got %v", results)
+	}
+	results = nil
+	if apiGet(t, controller.SearchAction, "/search?q=unknown", nil, &results); results == nil || len(results) != 0 {
+		t.Errorf("Expected an empty list, got %v", results)
+	}
+
+	var apiError ApiError
+	for url, expected := range map[string]int{
+		"/search":                       http.StatusBadRequest,
+		"/search?q=api&limit=all":       http.StatusBadRequest,
+		"/search?q=api&subview=unknown": http.StatusNotFound,
+		"/search?q=+&subview=checkout":  http.StatusBadRequest,
+	} {
+		if status := apiGet(t, controller.SearchAction, url, nil, &apiError); status != expected {
+			t.Errorf("Expected %v for %v, got %v", expected, url, status)
+		}
+	}
+
+	//the index is rebuilt after a reload
+	writeTestFile(t, filepath.Join(folder, "apps/search.yml"), "name: search\nsummary: Größe 📄 finder for the products\n")
+	if err := projectModel.Reload(); err != nil {
+		t.Fatal(err)
+	}
+	results = nil
+	apiGet(t, controller.SearchAction, "/search?q=products", nil, &results)
+	if len(results) != 1 || results[0].Application != "search" {
+		t.Fatalf("Expected the reloaded application, got %v", results)
+	}
+	match := results[0].Matches[0]
+	snippet := utf16.Encode([]rune(match.Snippet))
+	if len(match.Highlights) != 1 || string(utf16.Decode(snippet[match.Highlights[0].Start:match.Highlights[0].End])) != "products" {
+		t.Errorf("Expected the UTF-16 offsets of products in %q, got %v", match.Snippet, match.Highlights)
+	}
+}
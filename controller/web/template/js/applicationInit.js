@@ -19,32 +19,49 @@ $(function() {
     $( "#select-project" ).change(applicationInit.DrawConfiguredGraph);
     $( "#updateGraphConfiguration" ).click(applicationInit.DrawConfiguredGraph);
     $('#networkConfigureForm').change(applicationInit.DrawConfiguredGraph)
+    vistectureHelper.WatchVistectureData(applicationInit.ReloadGraph)
 });
 
 var applicationInit = {}
+//loadedVersion - version of the drawn project data
+applicationInit.loadedVersion = 0
 
 applicationInit.DrawConfiguredGraph = function() {
-    let value = $("#select-graph").val()
+    applicationInit.drawGraph(null)
+}
+
+//ReloadGraph - draws the graph again with the current subview, group filter and zoom if the server has a newer version of the project data
+applicationInit.ReloadGraph = function(version) {
+    //nothing drawn yet - the running initial load gets the newest version anyway
+    if (applicationInit.loadedVersion == 0 || version <= applicationInit.loadedVersion) {
+        return
+    }
+    applicationInit.drawGraph(visRenderer.GetView())
+}
+
+applicationInit.drawGraph = function(view) {
     let config = layout.GetGraphConfiguration()
     let selectedSubView = $("#select-project").val()
     let networkFilterGroups = $("#networkFilterGroups").val()
 
     vistectureHelper.LoadVistectureData(selectedSubView,networkFilterGroups,function(projectData) {
+        applicationInit.loadedVersion = projectData.version
         applicationInit.updateProjectDropdown(projectData.availableSubViews, config)
         applicationInit.updateGroups(projectData.applicationsByGroup, projectData.availableGroups, config)
         layout.SetDocumentsMenu(projectData.staticDocumentations)
         visRenderer.RenderNetwork(document.getElementById('maincontent'),projectData, config)
+        visRenderer.RestoreView(view)
     })
 }
 
 applicationInit.updateProjectDropdown = function(availableSubViews) {
-    let selected =  $("#select-project").val()
+    let selectedSubView =  $("#select-project").val()
     $("#select-project").find('option').remove()
     $("#select-project").append(new Option("Select project",""));
     for (var i in availableSubViews) {
         let name = availableSubViews[i]
         let selected = false
-        if (selected == name) {
+        if (selectedSubView == name) {
             selected = true
         }
         $("#select-project").append(new Option(name,name,selected,selected));
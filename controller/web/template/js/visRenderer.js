@@ -136,6 +136,25 @@ visRenderer.RenderNetwork = function(container, projectData,configurationData) {
 
 
 
+//GetView - returns the current zoom and position of the rendered network (null if nothing is rendered yet)
+visRenderer.GetView = function() {
+    if (visRenderer.networkInstance == null) {
+        return null
+    }
+    return {
+        position: visRenderer.networkInstance.getViewPosition(),
+        scale: visRenderer.networkInstance.getScale()
+    }
+}
+
+//RestoreView - moves the rendered network to the zoom and position returned by GetView
+visRenderer.RestoreView = function(view) {
+    if (visRenderer.networkInstance == null || view == null) {
+        return
+    }
+    visRenderer.networkInstance.moveTo({position: view.position, scale: view.scale, animation: false})
+}
+
 visRenderer.clickEventListener= function (nodeParams, projectData) {
     if (nodeParams.nodes.length<1) {
         return
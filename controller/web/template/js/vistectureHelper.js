@@ -41,6 +41,20 @@ vistectureHelper.LoadVistectureData = function(selectedSubView, networkFilterGro
 
 
 
+//WatchVistectureData - calls the callback with the new version whenever the server reloaded the project data (Server-Sent Events)
+vistectureHelper.WatchVistectureData = function(callback) {
+    if (typeof EVENTSURL == 'undefined' || !EVENTSURL || typeof EventSource == 'undefined') {
+        return
+    }
+    let events = new EventSource(EVENTSURL)
+    let onEvent = function(event) {
+        callback(JSON.parse(event.data).version)
+    }
+    //"version" is sent on every (re)connect - reloads during a reconnect are detected by comparing the version
+    events.addEventListener('version', onEvent)
+    events.addEventListener('reload', onEvent)
+}
+
 vistectureHelper.FindApp = function(appId, projectData) {
     for (var i in projectData.applications) {
         let app = projectData.applications[i]
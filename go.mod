@@ -7,4 +7,5 @@ require (
 	github.com/russross/blackfriday v1.6.0
 	github.com/urfave/cli v1.22.9
 	gopkg.in/yaml.v2 v2.4.0
+	gopkg.in/yaml.v3 v3.0.1
 )
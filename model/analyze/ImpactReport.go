@@ -0,0 +1,141 @@
+package analyze
+
+import (
+	"errors"
+	"fmt"
+	"sort"
+
+	"github.com/AOEpeople/vistecture/v2/model/core"
+)
+
+type (
+	//ImpactReport - lists for every analyzed application which other applications may be influenced if it fails. Sorted by blast radius (biggest first)
+	ImpactReport struct {
+		Applications []*ApplicationImpact `json:"applications"`
+	}
+
+	//ApplicationImpact - the impact of a failure of one application
+	ApplicationImpact struct {
+		Application string `json:"application"`
+		Team        string `json:"team,omitempty"`
+		Group       string `json:"group,omitempty"`
+		//DirectCount - number of applications that directly depend on the application
+		DirectCount int `json:"direct"`
+		//TransitiveCount - number of applications that directly or indirectly depend on the application (the blast radius)
+		TransitiveCount int `json:"transitive"`
+		MaxDepth        int `json:"maxDepth"`
+		//Dependents - every dependent application once, with the depth it is reached (1 = direct)
+		Dependents []*ImpactedApplication `json:"dependents"`
+		//ImpactedTeams - the dependents split by team
+		ImpactedTeams []*ImpactSummary `json:"impactedTeams"`
+		//ImpactedGroups - the dependents split by (main) group
+		ImpactedGroups []*ImpactSummary `json:"impactedGroups"`
+	}
+
+	//ImpactedApplication - an application that may be influenced
+	ImpactedApplication struct {
+		Name  string `json:"name"`
+		Team  string `json:"team,omitempty"`
+		Group string `json:"group,omitempty"`
+		Depth int    `json:"depth"`
+	}
+
+	//ImpactSummary - number of impacted applications of one team or group
+	ImpactSummary struct {
+		Name         string   `json:"name"`
+		Count        int      `json:"count"`
+		Applications []string `json:"applications"`
+	}
+)
+
+//ImpactAnalyze - builds the impact report for all applications of the project, or only for the application with the given name
+func (projectAnalyzer *ProjectAnalyzer) ImpactAnalyze(project *core.Project, applicationName string) (*ImpactReport, error) {
+	graph := project.Graph()
+	applications := graph.Applications()
+	if applicationName != "" {
+		app, found := graph.Application(applicationName)
+		if !found {
+			return nil, errors.New(fmt.Sprintf("Application with name '%v' not found", applicationName))
+		}
+		applications = []*core.Application{app}
+	}
+
+	report := &ImpactReport{}
+	for _, app := range applications {
+		report.Applications = append(report.Applications, projectAnalyzer.applicationImpact(graph, app))
+	}
+	sort.SliceStable(report.Applications, func(i, j int) bool {
+		a, b := report.Applications[i], report.Applications[j]
+		if a.TransitiveCount != b.TransitiveCount {
+			return a.TransitiveCount > b.TransitiveCount
+		}
+		if a.DirectCount != b.DirectCount {
+			return a.DirectCount > b.DirectCount
+		}
+		return a.Application < b.Application
+	})
+	return report, nil
+}
+
+func (projectAnalyzer *ProjectAnalyzer) applicationImpact(graph *core.Graph, app *core.Application) *ApplicationImpact {
+	impact := &ApplicationImpact{
+		Application: app.Name,
+		Team:        app.Team,
+		Group:       app.Group,
+	}
+	teams := make(map[string]*ImpactSummary)
+	groups := make(map[string]*ImpactSummary)
+
+	for _, reached := range graph.Walk(app.Name, core.UPSTREAM, 0, projectAnalyzer.follow) {
+		if reached.Application.Name == app.Name {
+			continue
+		}
+		impact.Dependents = append(impact.Dependents, &ImpactedApplication{
+			Name:  reached.Application.Name,
+			Team:  reached.Application.Team,
+			Group: reached.Application.Group,
+			Depth: reached.Depth,
+		})
+		if reached.Depth == 1 {
+			impact.DirectCount++
+		}
+		if reached.Depth > impact.MaxDepth {
+			impact.MaxDepth = reached.Depth
+		}
+		team := reached.Application.Team
+		if team == "" {
+			team = core.NOTEAM
+		}
+		impact.ImpactedTeams = addToSummary(impact.ImpactedTeams, teams, team, reached.Application.Name)
+		group := reached.Application.GetMainGroup()
+		if group == "" {
+			group = core.NOGROUP
+		}
+		impact.ImpactedGroups = addToSummary(impact.ImpactedGroups, groups, group, reached.Application.Name)
+	}
+	impact.TransitiveCount = len(impact.Dependents)
+	sortSummaries(impact.ImpactedTeams)
+	sortSummaries(impact.ImpactedGroups)
+	return impact
+}
+
+func addToSummary(summaries []*ImpactSummary, byName map[string]*ImpactSummary, name string, applicationName string) []*ImpactSummary {
+	summary, found := byName[name]
+	if !found {
+		summary = &ImpactSummary{Name: name}
+		byName[name] = summary
+		summaries = append(summaries, summary)
+	}
+	summary.Count++
+	summary.Applications = append(summary.Applications, applicationName)
+	return summaries
+}
+
+func sortSummaries(summaries []*ImpactSummary) {
+	sort.SliceStable(summaries, func(i, j int) bool {
+		if summaries[i].Count != summaries[j].Count {
+			return summaries[i].Count > summaries[j].Count
+		}
+		return summaries[i].Name < summaries[j].Name
+	})
+}
@@ -0,0 +1,93 @@
+package analyze
+
+import (
+	"errors"
+	"fmt"
+
+	"github.com/AOEpeople/vistecture/v2/model/core"
+)
+
+type (
+	//PathReport - the shortest path and all simple paths from one application to another
+	PathReport struct {
+		From      string `json:"from"`
+		To        string `json:"to"`
+		MaxLength int    `json:"maxLength"`
+		//Shortest - nil if the application is not reachable
+		Shortest *Path   `json:"shortest"`
+		Paths    []*Path `json:"paths"`
+	}
+
+	//Path - a path through the dependency graph
+	Path struct {
+		Length int        `json:"length"`
+		Hops   []*PathHop `json:"hops"`
+		//Description - the path like "app1 -> app2.api (acl) -> app3"
+		Description string `json:"description"`
+	}
+
+	//PathHop - the dependencies that lead from one application to the next one
+	PathHop struct {
+		From         string            `json:"from"`
+		To           string            `json:"to"`
+		Dependencies []*PathDependency `json:"dependencies"`
+	}
+
+	//PathDependency - one dependency of a hop with the used service and the relationship
+	PathDependency struct {
+		//SourceService - the provided service that declares the dependency - empty if declared on application level
+		SourceService string            `json:"sourceService,omitempty"`
+		Reference     string            `json:"reference"`
+		Service       string            `json:"service,omitempty"`
+		Relationship  core.Relationship `json:"relationship,omitempty"`
+		Status        core.Status       `json:"status,omitempty"`
+		IsOptional    bool              `json:"isOptional,omitempty"`
+	}
+)
+
+//DEFAULT_MAX_PATH_LENGTH - the maximum number of hops of the listed paths if nothing else is given
+const DEFAULT_MAX_PATH_LENGTH = 6
+
+//FindPaths - returns the shortest path and all simple paths with at most maxLength hops between the applications.
+// Planned and optional dependencies are skipped like in the cycle detection (SkipPlannedDependencies, SkipOptionalDependencies)
+func (projectAnalyzer *ProjectAnalyzer) FindPaths(project *core.Project, from string, to string, maxLength int) (*PathReport, error) {
+	graph := project.Graph()
+	for _, name := range []string{from, to} {
+		if _, found := graph.Application(name); !found {
+			return nil, errors.New(fmt.Sprintf("Application with name '%v' not found", name))
+		}
+	}
+	if from == to {
+		return nil, errors.New("Start and target application of the path are the same")
+	}
+	if maxLength <= 0 {
+		maxLength = DEFAULT_MAX_PATH_LENGTH
+	}
+	report := &PathReport{From: from, To: to, MaxLength: maxLength, Paths: []*Path{}}
+	if shortest := graph.ShortestPath(from, to, projectAnalyzer.follow); shortest != nil {
+		report.Shortest = newPath(shortest)
+	}
+	for _, path := range graph.AllPaths(from, to, maxLength, projectAnalyzer.follow) {
+		report.Paths = append(report.Paths, newPath(path))
+	}
+	return report, nil
+}
+
+func newPath(graphPath *core.GraphPath) *Path {
+	path := &Path{Length: len(graphPath.Hops), Description: graphPath.String()}
+	for _, graphHop := range graphPath.Hops {
+		hop := &PathHop{From: graphHop.From.Name, To: graphHop.To.Name}
+		for _, edge := range graphHop.Edges {
+			hop.Dependencies = append(hop.Dependencies, &PathDependency{
+				SourceService: edge.SourceService,
+				Reference:     edge.Reference(),
+				Service:       edge.TargetService,
+				Relationship:  edge.EffectiveRelationship(),
+				Status:        edge.Status,
+				IsOptional:    edge.IsOptional,
+			})
+		}
+		path.Hops = append(path.Hops, hop)
+	}
+	return path
+}
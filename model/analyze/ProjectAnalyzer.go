@@ -1,88 +1,339 @@
 package analyze
 
 import (
-	"errors"
-	"log"
-	"strconv"
+	"fmt"
+	"sort"
+	"strings"
 
 	"github.com/AOEpeople/vistecture/v2/model/core"
 )
 
-type ProjectAnalyzer struct{}
+type (
+	ProjectAnalyzer struct {
+		//SkipOptionalDependencies - if set, dependencies marked as isOptional are ignored by the cycle detection
+		SkipOptionalDependencies bool
+		//SkipPlannedDependencies - if set, planned dependencies and dependencies from or to planned applications are ignored by the cycle detection
+		SkipPlannedDependencies bool
+	}
+
+	//CyclicDependency - one elementary cycle in the dependency graph. The last step leads back to the application of the first step
+	CyclicDependency struct {
+		Steps []CycleStep
+	}
+
+	//CycleStep - one hop in a cycle from an application to the next one
+	CycleStep struct {
+		Application string
+		//Dependencies - all dependencies that lead from Application to the next application in the cycle
+		Dependencies []CycleDependency
+	}
+
+	//CycleDependency - a dependency that is part of a cycle together with the service that declares it (empty if declared on application level)
+	CycleDependency struct {
+		SourceService string
+		Reference     string
+		//Source - where the dependency is defined
+		Source core.SourcePosition
+	}
+
+	//dependencyGraph - adjacency of the applications (by index in the project) used for cycle detection
+	dependencyGraph struct {
+		applications []*core.Application
+		successors   [][]int
+		dependencies map[[2]int][]CycleDependency
+	}
+)
 
-//Analyze validates project and Components
+//CHECK_CYCLIC_DEPENDENCY - identifies cyclic dependency findings
+const CHECK_CYCLIC_DEPENDENCY = "cyclic-dependency"
+
+//AnalyzeCyclicDependencies - returns an error for every distinct cyclic dependency and for every dependency that cannot be resolved
 func (projectAnalyzer *ProjectAnalyzer) AnalyzeCyclicDependencies(project *core.Project) []error {
-	//Walk dependencies add add service to called stack
-	var stack []string
-	var errors []error
-	for _, component := range project.Applications {
-		err := projectAnalyzer.walkDependencies(project, component, stack)
-		if err != nil {
-			errors = append(errors, err)
+	cycles, errs := projectAnalyzer.FindCyclicDependencies(project)
+	for _, cycle := range cycles {
+		errs = append(errs, cycle)
+	}
+	return errs
+}
+
+//FindCyclicDependencies - detects all elementary cycles in the dependency graph of the project (application and service dependencies).
+// The strongly connected components are determined with Tarjan's algorithm, the cycles inside a component are enumerated with Johnson's algorithm so that each cycle is reported exactly once.
+func (projectAnalyzer *ProjectAnalyzer) FindCyclicDependencies(project *core.Project) ([]*CyclicDependency, []error) {
+	graph, errs := projectAnalyzer.buildDependencyGraph(project)
+
+	var cycles []*CyclicDependency
+	for _, component := range graph.stronglyConnectedComponents() {
+		cycles = append(cycles, graph.findCycles(component)...)
+	}
+	return cycles, errs
+}
+
+//Error - the cycle is reported as an error
+func (c *CyclicDependency) Error() string {
+	var positions []string
+	for _, step := range c.Steps {
+		for _, dependency := range step.Dependencies {
+			if dependency.Source.IsKnown() {
+				positions = append(positions, dependency.Source.String())
+			}
 		}
 	}
-	return errors
+	if len(positions) > 0 {
+		return "Cyclic dependency: " + c.String() + " (defined at " + strings.Join(positions, ", ") + ")"
+	}
+	return "Cyclic dependency: " + c.String()
 }
 
-//Analyze validates project and Components
-func (projectAnalyzer *ProjectAnalyzer) ImpactAnalyze(project *core.Project) []string {
-	//Walk dependencies add add service to called stack
-	var impactsPerComponent []string
-	for _, component := range project.Applications {
-		directComponents := project.FindApplicationThatReferenceTo(component, false)
-		allIndirectComponents := project.FindApplicationThatReferenceTo(component, true)
-		impactsPerComponent = append(impactsPerComponent, strconv.Itoa(len(directComponents))+"\t\t"+strconv.Itoa(len(allIndirectComponents))+"\t\t"+component.Name)
+//String - returns the cycle path like "app1[ui] -> app2.api -> app3 -> app1"
+func (c *CyclicDependency) String() string {
+	if len(c.Steps) == 0 {
+		return ""
 	}
-	return impactsPerComponent
+	var parts []string
+	for i, step := range c.Steps {
+		label := step.Application
+		if i > 0 {
+			label = joinReferences(c.Steps[i-1].Dependencies)
+		}
+		if services := joinSourceServices(step.Dependencies); services != "" {
+			label += "[" + services + "]"
+		}
+		parts = append(parts, label)
+	}
+	parts = append(parts, joinReferences(c.Steps[len(c.Steps)-1].Dependencies))
+	return strings.Join(parts, " -> ")
 }
 
-// called recursive and adds the dependency components to the stack
-// if a component appears again it throws an error (= cyclic dependencie)
-func (projectAnalyzer *ProjectAnalyzer) walkDependencies(project *core.Project, component *core.Application, callStack []string) error {
-	//end of recursion
-	if len(callStack) >= 50 {
-		return errors.New("More than 50 depth")
+//Applications - returns the names of the applications in the cycle
+func (c *CyclicDependency) Applications() []string {
+	var names []string
+	for _, step := range c.Steps {
+		names = append(names, step.Application)
 	}
-	if arrayContainsName(callStack, component.Name) {
-		errorMessage := "Cyclic dependency: "
+	return names
+}
 
-		for _, v := range callStack {
-			errorMessage += v + " -> "
+func joinReferences(dependencies []CycleDependency) string {
+	var references []string
+	for _, dependency := range dependencies {
+		if !containsString(references, dependency.Reference) {
+			references = append(references, dependency.Reference)
 		}
-		return errors.New(errorMessage + component.Name)
 	}
+	return strings.Join(references, "|")
+}
 
-	callStack = append(callStack, component.Name)
-	//Walk Dependencies that are global for the given component
-	dependencies := component.Dependencies
+func joinSourceServices(dependencies []CycleDependency) string {
+	var services []string
 	for _, dependency := range dependencies {
-		nextComponent, _ := dependency.GetApplication(project)
-		if nextComponent == nil {
-			log.Fatalf("Could not find dependency \"%s\" for application \"%s\"", dependency.Reference, component.Name)
-		}
-		err := projectAnalyzer.walkDependencies(project, nextComponent, callStack)
-		if err != nil {
-			return err
-		}
-	}
-	//Walk Dependencies that are related to the called service
-	service, e := component.FindService("ssss")
-	if e == nil {
-		dependencies2 := service.Dependencies
-		for _, dependency := range dependencies2 {
-			nextComponent, _ := dependency.GetApplication(project)
-			err := projectAnalyzer.walkDependencies(project, nextComponent, callStack)
-			if err != nil {
-				return err
+		if dependency.SourceService != "" && !containsString(services, dependency.SourceService) {
+			services = append(services, dependency.SourceService)
+		}
+	}
+	return strings.Join(services, "|")
+}
+
+// buildDependencyGraph - indexes the project dependency graph for the cycle detection. Unresolvable references are returned as errors unless the dependency is optional
+func (projectAnalyzer *ProjectAnalyzer) buildDependencyGraph(project *core.Project) (*dependencyGraph, []error) {
+	var errs []error
+	projectGraph := project.Graph()
+	graph := &dependencyGraph{
+		applications: projectGraph.Applications(),
+		successors:   make([][]int, len(projectGraph.Applications())),
+		dependencies: make(map[[2]int][]CycleDependency),
+	}
+	indexByName := make(map[string]int)
+	for i, app := range graph.applications {
+		if _, exists := indexByName[app.Name]; !exists {
+			indexByName[app.Name] = i
+		}
+	}
+
+	for _, missing := range projectGraph.MissingEdges() {
+		if !missing.IsOptional {
+			errs = append(errs, &core.ValidationError{
+				CheckId:     core.CHECK_MISSING_DEPENDENCY,
+				Application: missing.From.Name,
+				Service:     missing.SourceService,
+				Dependency:  missing.Reference(),
+				Source:      missing.Dependency.Source,
+				Message:     fmt.Sprintf("Application '%v' references unknown application '%v'", missing.From.Name, missing.Reference()),
+			})
+		}
+	}
+	for from, app := range graph.applications {
+		for _, edge := range projectGraph.OutgoingEdges(app.Name) {
+			if edge.To == nil || !projectAnalyzer.follow(edge) {
+				continue
+			}
+			key := [2]int{from, indexByName[edge.TargetName]}
+			if _, exists := graph.dependencies[key]; !exists {
+				graph.successors[from] = append(graph.successors[from], key[1])
+			}
+			graph.dependencies[key] = append(graph.dependencies[key], CycleDependency{SourceService: edge.SourceService, Reference: edge.Dependency.Reference, Source: edge.Dependency.Source})
+		}
+	}
+	return graph, errs
+}
+
+// follow - edge filter according to the analyzer settings
+func (projectAnalyzer *ProjectAnalyzer) follow(edge *core.GraphEdge) bool {
+	if projectAnalyzer.SkipOptionalDependencies && edge.IsOptional {
+		return false
+	}
+	if projectAnalyzer.SkipPlannedDependencies && edge.IsPlanned() {
+		return false
+	}
+	return true
+}
+
+// stronglyConnectedComponents - Tarjan's algorithm. Only components that can contain a cycle (more than one node or a self reference) are returned
+func (g *dependencyGraph) stronglyConnectedComponents() [][]int {
+	index := 0
+	indexes := make([]int, len(g.applications))
+	lowLinks := make([]int, len(g.applications))
+	onStack := make([]bool, len(g.applications))
+	for i := range indexes {
+		indexes[i] = -1
+	}
+	var stack []int
+	var components [][]int
+
+	var strongConnect func(v int)
+	strongConnect = func(v int) {
+		indexes[v] = index
+		lowLinks[v] = index
+		index++
+		stack = append(stack, v)
+		onStack[v] = true
+
+		for _, w := range g.successors[v] {
+			if indexes[w] == -1 {
+				strongConnect(w)
+				if lowLinks[w] < lowLinks[v] {
+					lowLinks[v] = lowLinks[w]
+				}
+			} else if onStack[w] && indexes[w] < lowLinks[v] {
+				lowLinks[v] = indexes[w]
+			}
+		}
+
+		if lowLinks[v] != indexes[v] {
+			return
+		}
+		var component []int
+		for {
+			w := stack[len(stack)-1]
+			stack = stack[:len(stack)-1]
+			onStack[w] = false
+			component = append(component, w)
+			if w == v {
+				break
+			}
+		}
+		if len(component) > 1 || g.hasEdge(v, v) {
+			components = append(components, component)
+		}
+	}
+
+	for v := range g.applications {
+		if indexes[v] == -1 {
+			strongConnect(v)
+		}
+	}
+	return components
+}
+
+func (g *dependencyGraph) hasEdge(from int, to int) bool {
+	_, found := g.dependencies[[2]int{from, to}]
+	return found
+}
+
+// findCycles - Johnson's algorithm restricted to one strongly connected component. Every cycle is found once - starting at its node with the lowest index
+func (g *dependencyGraph) findCycles(component []int) []*CyclicDependency {
+	inComponent := make(map[int]bool)
+	for _, v := range component {
+		inComponent[v] = true
+	}
+	var cycles []*CyclicDependency
+	var path []int
+	blocked := make(map[int]bool)
+	blockedBy := make(map[int]map[int]bool)
+
+	var unblock func(u int)
+	unblock = func(u int) {
+		blocked[u] = false
+		for w := range blockedBy[u] {
+			delete(blockedBy[u], w)
+			if blocked[w] {
+				unblock(w)
+			}
+		}
+	}
+
+	var circuit func(v int, start int) bool
+	circuit = func(v int, start int) bool {
+		found := false
+		path = append(path, v)
+		blocked[v] = true
+		for _, w := range g.successors[v] {
+			if !inComponent[w] || w < start {
+				continue
 			}
+			if w == start {
+				cycles = append(cycles, g.newCyclicDependency(path))
+				found = true
+			} else if !blocked[w] && circuit(w, start) {
+				found = true
+			}
+		}
+		if found {
+			unblock(v)
+		} else {
+			for _, w := range g.successors[v] {
+				if !inComponent[w] || w < start {
+					continue
+				}
+				if blockedBy[w] == nil {
+					blockedBy[w] = make(map[int]bool)
+				}
+				blockedBy[w][v] = true
+			}
+		}
+		path = path[:len(path)-1]
+		return found
+	}
+
+	starts := append([]int(nil), component...)
+	sort.Ints(starts)
+	for _, start := range starts {
+		for v := range inComponent {
+			blocked[v] = false
+			blockedBy[v] = nil
+		}
+		circuit(start, start)
+	}
+	return cycles
+}
+
+func (g *dependencyGraph) newCyclicDependency(path []int) *CyclicDependency {
+	cycle := &CyclicDependency{}
+	for i, v := range path {
+		next := path[0]
+		if i+1 < len(path) {
+			next = path[i+1]
 		}
+		cycle.Steps = append(cycle.Steps, CycleStep{
+			Application:  g.applications[v].Name,
+			Dependencies: g.dependencies[[2]int{v, next}],
+		})
 	}
-	return nil
+	return cycle
 }
 
-func arrayContainsName(callStack []string, name string) bool {
-	for _, v := range callStack {
-		if v == name {
+func containsString(list []string, search string) bool {
+	for _, v := range list {
+		if v == search {
 			return true
 		}
 	}
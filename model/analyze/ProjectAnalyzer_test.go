@@ -0,0 +1,171 @@
+package analyze
+
+import (
+	"reflect"
+	"testing"
+
+	"github.com/AOEpeople/vistecture/v2/model/core"
+)
+
+func TestProjectAnalyzer_FindCyclicDependencies(t *testing.T) {
+
+	project := core.Project{
+		Name: "Project1",
+		Applications: []*core.Application{
+			{
+				Name: "app1",
+				Dependencies: []core.Dependency{
+					{Reference: "app2.api"},
+				},
+			},
+			{
+				Name: "app2",
+				ProvidedServices: []core.Service{
+					{
+						Name: "api",
+						Dependencies: []core.Dependency{
+							{Reference: "app3"},
+						},
+					},
+				},
+			},
+			{
+				Name: "app3",
+				Dependencies: []core.Dependency{
+					{Reference: "app1"},
+					{Reference: "app2", IsOptional: true},
+					{Reference: "unknown", IsOptional: true},
+				},
+			},
+		},
+	}
+
+	var analyzer ProjectAnalyzer
+	cycles, errs := analyzer.FindCyclicDependencies(&project)
+	if len(errs) != 0 {
+		t.Fatal("Expected no errors, got", errs)
+	}
+	if len(cycles) != 2 {
+		t.Fatal("Expected two cycles, got", cycles)
+	}
+	if cycles[0].String() != "app1 -> app2.api[api] -> app3 -> app1" {
+		t.Error("Unexpected cycle path", cycles[0].String())
+	}
+
+	analyzer.SkipOptionalDependencies = true
+	cycles, _ = analyzer.FindCyclicDependencies(&project)
+	if len(cycles) != 1 {
+		t.Error("Expected optional dependency to be skipped, got", cycles)
+	}
+
+	project.Applications[2].Dependencies[0].Status = core.STATUS_PLANNED
+	project.ResetGraph()
+	analyzer.SkipPlannedDependencies = true
+	cycles, _ = analyzer.FindCyclicDependencies(&project)
+	if len(cycles) != 0 {
+		t.Error("Expected planned dependency to be skipped, got", cycles)
+	}
+}
+
+func TestProjectAnalyzer_AnalyzeCyclicDependencies_MissingApplication(t *testing.T) {
+
+	project := core.Project{
+		Name: "Project1",
+		Applications: []*core.Application{
+			{
+				Name: "app1",
+				Dependencies: []core.Dependency{
+					{Reference: "unknown"},
+				},
+			},
+		},
+	}
+
+	var analyzer ProjectAnalyzer
+	if errs := analyzer.AnalyzeCyclicDependencies(&project); len(errs) != 1 {
+		t.Error("Expected one error for the unknown application, got", errs)
+	}
+}
+
+func TestProjectAnalyzer_ImpactAnalyze(t *testing.T) {
+
+	project := core.Project{
+		Name: "Project1",
+		Applications: []*core.Application{
+			{Name: "app1", Team: "team1", Dependencies: []core.Dependency{{Reference: "app2"}, {Reference: "app3"}}},
+			{Name: "app2", Team: "team2", Dependencies: []core.Dependency{{Reference: "app3"}}},
+			{Name: "app3", Team: "team2", Dependencies: []core.Dependency{{Reference: "app1"}}},
+		},
+	}
+
+	var analyzer ProjectAnalyzer
+	report, err := analyzer.ImpactAnalyze(&project, "app3")
+	if err != nil {
+		t.Fatal(err)
+	}
+	impact := report.Applications[0]
+	if impact.DirectCount != 2 || impact.TransitiveCount != 2 || impact.MaxDepth != 1 {
+		t.Error("Expected two direct dependents without duplicates or the application itself", impact.Dependents)
+	}
+	if len(impact.ImpactedTeams) != 2 {
+		t.Error("Expected dependents split into two teams", impact.ImpactedTeams)
+	}
+
+	if _, err := analyzer.ImpactAnalyze(&project, "unknown"); err == nil {
+		t.Error("Expected error for unknown application")
+	}
+}
+
+func TestProjectAnalyzer_FindPaths(t *testing.T) {
+	project := core.Project{
+		Applications: []*core.Application{
+			{
+				Name: "portal",
+				Dependencies: []core.Dependency{
+					{Reference: "orders.api", Relationship: core.RELATIONSHIP_CUSTOMER_SUPPLIER},
+					{Reference: "payment", Status: core.STATUS_PLANNED},
+					{Reference: "gateway", IsOptional: true},
+				},
+			},
+			{
+				Name:             "orders",
+				ProvidedServices: []core.Service{{Name: "api", Dependencies: []core.Dependency{{Reference: "gateway"}}}},
+				Dependencies:     []core.Dependency{{Reference: "portal"}},
+			},
+			{Name: "gateway", Dependencies: []core.Dependency{{Reference: "payment.charge", Relationship: core.RELATIONSHIP_ACL}}},
+			{Name: "payment"},
+		},
+	}
+
+	var analyzer ProjectAnalyzer
+	report, err := analyzer.FindPaths(&project, "portal", "payment", 0)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if report.Shortest == nil || report.Shortest.Description != "portal -> payment" {
+		t.Errorf("Expected the planned dependency as shortest path, got %#v", report.Shortest)
+	}
+	var descriptions []string
+	for _, path := range report.Paths {
+		descriptions = append(descriptions, path.Description)
+	}
+	expected := []string{"portal -> payment", "portal -> gateway -> payment.charge (acl)", "portal -> orders.api (customer-supplier) -> gateway -> payment.charge (acl)"}
+	if !reflect.DeepEqual(descriptions, expected) {
+		t.Errorf("Expected paths %v, got %v", expected, descriptions)
+	}
+
+	analyzer.SkipPlannedDependencies = true
+	analyzer.SkipOptionalDependencies = true
+	report, _ = analyzer.FindPaths(&project, "portal", "payment", 2)
+	if report.Shortest == nil || report.Shortest.Length != 3 || len(report.Paths) != 0 {
+		t.Errorf("Expected only the path with 3 hops (longer than the max length), got %#v", report)
+	}
+	hop := report.Shortest.Hops[0]
+	if hop.From != "portal" || hop.To != "orders" || hop.Dependencies[0].Service != "api" || hop.Dependencies[0].Relationship != core.RELATIONSHIP_CUSTOMER_SUPPLIER {
+		t.Errorf("Expected the used service and relationship of the hop, got %#v", hop.Dependencies[0])
+	}
+
+	if _, err := analyzer.FindPaths(&project, "portal", "unknown", 0); err == nil {
+		t.Error("Expected an error for an unknown application")
+	}
+}
@@ -0,0 +1,218 @@
+package analyze
+
+import (
+	"errors"
+	"fmt"
+	"sort"
+	"strings"
+
+	"github.com/AOEpeople/vistecture/v2/model/core"
+)
+
+type (
+	//Rule - a declarative architecture constraint (defined in the project config under "rules")
+	Rule struct {
+		Id          string `json:"id" yaml:"id"`
+		Description string `json:"description,omitempty" yaml:"description,omitempty"`
+		//Severity - error (default), warning or info. Only errors fail the analysis
+		Severity string `json:"severity,omitempty" yaml:"severity,omitempty"`
+		//Type - one of RULE_FORBIDDEN_DEPENDENCY, RULE_DEPENDENCY_REQUIREMENT or RULE_MAX_TEAM_DEPENDENCIES
+		Type string `json:"type" yaml:"type"`
+		//From - selects the depending applications
+		From *core.ApplicationSelector `json:"from,omitempty" yaml:"from,omitempty"`
+		//To - selects the referenced applications
+		To *core.ApplicationSelector `json:"to,omitempty" yaml:"to,omitempty"`
+		//Service - selects the referenced service. If set, only dependencies that reference a service are matched
+		Service *core.ServiceSelector `json:"service,omitempty" yaml:"service,omitempty"`
+		//Dependency - for forbidden-dependency it further restricts the forbidden dependencies, for dependency-requirement every matched dependency needs to match it
+		Dependency *core.DependencySelector `json:"dependency,omitempty" yaml:"dependency,omitempty"`
+		//Max - maximum for max-team-dependencies (required, a missing max is not treated as 0)
+		Max *int `json:"max,omitempty" yaml:"max,omitempty"`
+	}
+
+	//RuleViolation - a finding of the rule engine
+	RuleViolation struct {
+		RuleId   string `json:"ruleId"`
+		Severity string `json:"severity"`
+		Message  string `json:"message"`
+		//Application - the application that violates the rule (for team rules: empty)
+		Application string `json:"application,omitempty"`
+		//Service - the provided service that declares the violating dependency
+		Service string `json:"service,omitempty"`
+		//Dependency - the reference of the violating dependency
+		Dependency string `json:"dependency,omitempty"`
+		Team       string `json:"team,omitempty"`
+		//Source - where the violating dependency is defined
+		Source core.SourcePosition `json:"source"`
+	}
+)
+
+const (
+	//RULE_FORBIDDEN_DEPENDENCY - every dependency matching from, to, service and dependency is a violation
+	RULE_FORBIDDEN_DEPENDENCY = "forbidden-dependency"
+	//RULE_DEPENDENCY_REQUIREMENT - every dependency matching from, to and service needs to match the dependency selector
+	RULE_DEPENDENCY_REQUIREMENT = "dependency-requirement"
+	//RULE_MAX_TEAM_DEPENDENCIES - teams of the applications matching from may depend on at most max other teams (of applications matching to)
+	RULE_MAX_TEAM_DEPENDENCIES = "max-team-dependencies"
+
+	SEVERITY_ERROR   = "error"
+	SEVERITY_WARNING = "warning"
+	SEVERITY_INFO    = "info"
+)
+
+//Validate - checks that the rule is well defined
+func (r *Rule) Validate() error {
+	if r.Id == "" {
+		return errors.New("rule with no id found")
+	}
+	switch r.GetSeverity() {
+	case SEVERITY_ERROR, SEVERITY_WARNING, SEVERITY_INFO:
+	default:
+		return errors.New(fmt.Sprintf("rule '%v' has unknown severity '%v'", r.Id, r.Severity))
+	}
+	switch r.Type {
+	case RULE_FORBIDDEN_DEPENDENCY:
+	case RULE_DEPENDENCY_REQUIREMENT:
+		if r.Dependency == nil {
+			return errors.New(fmt.Sprintf("rule '%v' of type %v needs a 'dependency' selector", r.Id, r.Type))
+		}
+	case RULE_MAX_TEAM_DEPENDENCIES:
+		if r.Max == nil || *r.Max < 0 {
+			return errors.New(fmt.Sprintf("rule '%v' of type %v needs a 'max' >= 0", r.Id, r.Type))
+		}
+	default:
+		return errors.New(fmt.Sprintf("rule '%v' has unknown type '%v' (use %v, %v or %v)", r.Id, r.Type, RULE_FORBIDDEN_DEPENDENCY, RULE_DEPENDENCY_REQUIREMENT, RULE_MAX_TEAM_DEPENDENCIES))
+	}
+	return nil
+}
+
+//GetSeverity - returns the severity - defaults to error
+func (r *Rule) GetSeverity() string {
+	if r.Severity == "" {
+		return SEVERITY_ERROR
+	}
+	return r.Severity
+}
+
+//Error - a violation can be used as error
+func (v *RuleViolation) Error() string {
+	if v.Source.IsKnown() {
+		return fmt.Sprintf("%v: [%v] %v: %v", v.Source, v.Severity, v.RuleId, v.Message)
+	}
+	return fmt.Sprintf("[%v] %v: %v", v.Severity, v.RuleId, v.Message)
+}
+
+//IsError - true if the violation should fail the analysis
+func (v *RuleViolation) IsError() bool {
+	return v.Severity == SEVERITY_ERROR
+}
+
+//CheckRules - evaluates the rules against the dependency graph of the project. Invalid rules are returned as error and not evaluated
+func (projectAnalyzer *ProjectAnalyzer) CheckRules(project *core.Project, rules []*Rule) ([]*RuleViolation, error) {
+	var violations []*RuleViolation
+	var invalidRules []string
+	graph := project.Graph()
+	for _, rule := range rules {
+		if err := rule.Validate(); err != nil {
+			invalidRules = append(invalidRules, err.Error())
+			continue
+		}
+		if rule.Type == RULE_MAX_TEAM_DEPENDENCIES {
+			violations = append(violations, projectAnalyzer.checkTeamDependencies(graph, rule)...)
+			continue
+		}
+		for _, edge := range projectAnalyzer.matchingEdges(graph, rule) {
+			if rule.Type == RULE_DEPENDENCY_REQUIREMENT && rule.Dependency.Matches(&edge.Dependency) {
+				continue
+			}
+			violations = append(violations, newEdgeViolation(rule, edge))
+		}
+	}
+	if len(invalidRules) > 0 {
+		return violations, errors.New("Invalid rules: " + strings.Join(invalidRules, "; "))
+	}
+	return violations, nil
+}
+
+// matchingEdges - returns all resolvable dependencies matching the from, to and service selector of the rule (and the dependency selector for forbidden dependencies)
+func (projectAnalyzer *ProjectAnalyzer) matchingEdges(graph *core.Graph, rule *Rule) []*core.GraphEdge {
+	var result []*core.GraphEdge
+	for _, app := range graph.Applications() {
+		if !rule.From.Matches(app) {
+			continue
+		}
+		for _, edge := range graph.OutgoingEdges(app.Name) {
+			if edge.To == nil || !projectAnalyzer.follow(edge) || !rule.To.Matches(edge.To) {
+				continue
+			}
+			if rule.Service != nil && !rule.Service.Matches(edge.To.GetServiceForDependency(&edge.Dependency)) {
+				continue
+			}
+			if rule.Type == RULE_FORBIDDEN_DEPENDENCY && !rule.Dependency.Matches(&edge.Dependency) {
+				continue
+			}
+			result = append(result, edge)
+		}
+	}
+	return result
+}
+
+func (projectAnalyzer *ProjectAnalyzer) checkTeamDependencies(graph *core.Graph, rule *Rule) []*RuleViolation {
+	teamDependencies := make(map[string]map[string]bool)
+	for _, app := range graph.Applications() {
+		if app.Team == "" || !rule.From.Matches(app) {
+			continue
+		}
+		if teamDependencies[app.Team] == nil {
+			teamDependencies[app.Team] = make(map[string]bool)
+		}
+		for _, edge := range graph.OutgoingEdges(app.Name) {
+			if edge.To == nil || edge.To.Team == "" || edge.To.Team == app.Team || !projectAnalyzer.follow(edge) || !rule.To.Matches(edge.To) {
+				continue
+			}
+			teamDependencies[app.Team][edge.To.Team] = true
+		}
+	}
+
+	var violations []*RuleViolation
+	for team, otherTeams := range teamDependencies {
+		if len(otherTeams) <= *rule.Max {
+			continue
+		}
+		var names []string
+		for name := range otherTeams {
+			names = append(names, name)
+		}
+		sort.Strings(names)
+		violations = append(violations, &RuleViolation{
+			RuleId:   rule.Id,
+			Severity: rule.GetSeverity(),
+			Message:  fmt.Sprintf("Team '%v' depends on %d other teams (max %d): %v", team, len(names), *rule.Max, strings.Join(names, ", ")),
+			Team:     team,
+		})
+	}
+	sort.Slice(violations, func(i, j int) bool {
+		return violations[i].Team < violations[j].Team
+	})
+	return violations
+}
+
+func newEdgeViolation(rule *Rule, edge *core.GraphEdge) *RuleViolation {
+	message := fmt.Sprintf("Application '%v' must not depend on '%v'", edge.From.Name, edge.Reference())
+	if rule.Type == RULE_DEPENDENCY_REQUIREMENT {
+		message = fmt.Sprintf("Dependency from '%v' to '%v' does not fulfill the requirements", edge.From.Name, edge.Reference())
+	}
+	if rule.Description != "" {
+		message += " (" + rule.Description + ")"
+	}
+	return &RuleViolation{
+		RuleId:      rule.Id,
+		Severity:    rule.GetSeverity(),
+		Message:     message,
+		Application: edge.From.Name,
+		Service:     edge.SourceService,
+		Dependency:  edge.Reference(),
+		Team:        edge.From.Team,
+		Source:      edge.Dependency.Source,
+	}
+}
@@ -0,0 +1,73 @@
+package analyze
+
+import (
+	"testing"
+
+	"github.com/AOEpeople/vistecture/v2/model/core"
+)
+
+func TestProjectAnalyzer_CheckRules(t *testing.T) {
+
+	isPublic := true
+	maxTeams := 2
+	project := core.Project{
+		Name: "Project1",
+		Applications: []*core.Application{
+			{
+				Name:             "shop",
+				Group:            "frontend",
+				Team:             "team1",
+				ProvidedServices: []core.Service{{Name: "web", IsPublic: true}},
+				Dependencies: []core.Dependency{
+					{Reference: "orders-db"},
+					{Reference: "payment"},
+					{Reference: "customers.api"},
+					{Reference: "search", Relationship: "acl"},
+				},
+			},
+			{Name: "orders-db", Group: "backend/db", Team: "team2"},
+			{Name: "payment", Category: core.CATEGORY_EXTERNAL, Team: "team3"},
+			{Name: "search", Category: core.CATEGORY_EXTERNAL, Team: "team4"},
+			{Name: "customers", Team: "team2", ProvidedServices: []core.Service{{Name: "api", SecurityLevel: "restricted"}}},
+		},
+	}
+	rules := []*Rule{
+		{Id: "frontend-no-db", Type: RULE_FORBIDDEN_DEPENDENCY, From: &core.ApplicationSelector{Group: "frontend"}, To: &core.ApplicationSelector{Group: "backend/db"}},
+		{Id: "external-via-acl", Type: RULE_DEPENDENCY_REQUIREMENT, Severity: SEVERITY_WARNING, To: &core.ApplicationSelector{Category: core.CATEGORY_EXTERNAL}, Dependency: &core.DependencySelector{Relationship: "acl"}},
+		{Id: "high-security", Type: RULE_FORBIDDEN_DEPENDENCY, From: &core.ApplicationSelector{HasPublicService: &isPublic}, Service: &core.ServiceSelector{SecurityLevel: "restricted"}},
+		{Id: "team-coupling", Type: RULE_MAX_TEAM_DEPENDENCIES, Max: &maxTeams},
+	}
+
+	var analyzer ProjectAnalyzer
+	violations, err := analyzer.CheckRules(&project, rules)
+	if err != nil {
+		t.Fatal(err)
+	}
+	expected := map[string]string{
+		"frontend-no-db":   "orders-db",
+		"external-via-acl": "payment",
+		"high-security":    "customers.api",
+		"team-coupling":    "",
+	}
+	if len(violations) != len(expected) {
+		t.Fatal("Expected one violation per rule, got", violations)
+	}
+	for _, violation := range violations {
+		dependency, found := expected[violation.RuleId]
+		if !found || dependency != violation.Dependency {
+			t.Error("Unexpected violation", violation)
+		}
+		if violation.RuleId == "external-via-acl" && violation.IsError() {
+			t.Error("Expected warning severity", violation)
+		}
+	}
+
+	_, err = analyzer.CheckRules(&project, []*Rule{{Id: "broken", Type: "unknown"}})
+	if err == nil {
+		t.Error("Expected error for unknown rule type")
+	}
+	_, err = analyzer.CheckRules(&project, []*Rule{{Id: "no-max", Type: RULE_MAX_TEAM_DEPENDENCIES}})
+	if err == nil {
+		t.Error("Expected error for max-team-dependencies without max")
+	}
+}
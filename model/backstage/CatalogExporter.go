@@ -0,0 +1,238 @@
+package backstage
+
+import (
+	"sort"
+
+	"github.com/AOEpeople/vistecture/v2/model/core"
+)
+
+type (
+	//CatalogExporter - maps a project to Backstage entities: teams become Groups, groups Systems, applications Components,
+	//provided services APIs and infrastructure dependencies Resources
+	CatalogExporter struct {
+		project *core.Project
+	}
+)
+
+//CreateCatalogExporter - Factory
+func CreateCatalogExporter(project *core.Project) *CatalogExporter {
+	return &CatalogExporter{project: project}
+}
+
+//Entities - returns the Groups, Systems and Resources followed by each Component with its APIs
+func (e *CatalogExporter) Entities() []*Entity {
+	var entities []*Entity
+	entities = append(entities, e.groups()...)
+	entities = append(entities, e.systems()...)
+	entities = append(entities, e.resources()...)
+	for _, application := range e.project.Applications {
+		entities = append(entities, e.component(application))
+		for _, service := range application.ProvidedServices {
+			entities = append(entities, e.api(application, service))
+		}
+	}
+	return entities
+}
+
+func (e *CatalogExporter) component(application *core.Application) *Entity {
+	component := &Entity{
+		ApiVersion: API_VERSION,
+		Kind:       KIND_COMPONENT,
+		Metadata: Metadata{
+			Name:        EntityName(application.Name),
+			Title:       application.Title,
+			Description: application.GetSummary(),
+			Annotations: annotations(map[string]string{
+				ANNOTATION_TECHNOLOGY: application.Technology,
+				ANNOTATION_CATEGORY:   application.Category,
+				ANNOTATION_GROUP:      application.Group,
+			}),
+		},
+		Spec: Spec{
+			Type:      "service",
+			Lifecycle: lifecycle(application.Status),
+			Owner:     owner(application.Team),
+		},
+	}
+	if application.Group != "" {
+		component.Spec.System = EntityName(application.Group)
+	}
+	for _, service := range application.ProvidedServices {
+		component.Spec.ProvidesApis = append(component.Spec.ProvidesApis, apiName(application.Name, service.Name))
+	}
+	for _, dependency := range application.GetAllDependencies() {
+		if dependency.GetApplicationName() == application.Name {
+			continue
+		}
+		if dependency.GetServiceName() != "" {
+			component.Spec.ConsumesApis = appendMissing(component.Spec.ConsumesApis, apiName(dependency.GetApplicationName(), dependency.GetServiceName()))
+		} else {
+			component.Spec.DependsOn = appendMissing(component.Spec.DependsOn, "component:"+EntityName(dependency.GetApplicationName()))
+		}
+	}
+	for _, infrastructureDependency := range application.InfrastructureDependencies {
+		component.Spec.DependsOn = appendMissing(component.Spec.DependsOn, "resource:"+EntityName(infrastructureDependency.Type))
+	}
+	return component
+}
+
+func (e *CatalogExporter) api(application *core.Application, service core.Service) *Entity {
+	apiType := string(service.Type)
+	if apiType == "" {
+		apiType = "other"
+	}
+	definition := service.Description
+	if definition == "" {
+		definition = service.Summary
+	}
+	if definition == "" {
+		definition = "n/a"
+	}
+	status := application.Status
+	if service.Status != "" {
+		status = service.Status
+	}
+	return &Entity{
+		ApiVersion: API_VERSION,
+		Kind:       KIND_API,
+		Metadata: Metadata{
+			Name:        apiName(application.Name, service.Name),
+			Title:       service.Title,
+			Description: service.Summary,
+			Annotations: annotations(map[string]string{
+				ANNOTATION_SERVICE: service.Name,
+			}),
+		},
+		Spec: Spec{
+			Type:       apiType,
+			Lifecycle:  lifecycle(status),
+			Owner:      owner(application.Team),
+			System:     EntityName(application.Group),
+			Definition: definition,
+		},
+	}
+}
+
+// groups - one Group per team with the type "team"
+func (e *CatalogExporter) groups() []*Entity {
+	var teams []string
+	for _, application := range e.project.Applications {
+		if application.Team != "" {
+			teams = appendMissing(teams, application.Team)
+		}
+	}
+	sort.Strings(teams)
+
+	var entities []*Entity
+	for _, team := range teams {
+		entities = append(entities, &Entity{
+			ApiVersion: API_VERSION,
+			Kind:       KIND_GROUP,
+			Metadata:   Metadata{Name: EntityName(team), Title: team},
+			Spec:       Spec{Type: "team", Children: &[]string{}},
+		})
+	}
+	return entities
+}
+
+// systems - one System per (sub)group owned by the team owning most of its applications
+func (e *CatalogExporter) systems() []*Entity {
+	var groups []string
+	teamsByGroup := make(map[string]map[string]int)
+	for _, application := range e.project.Applications {
+		if application.Group == "" {
+			continue
+		}
+		if _, found := teamsByGroup[application.Group]; !found {
+			groups = append(groups, application.Group)
+			teamsByGroup[application.Group] = make(map[string]int)
+		}
+		teamsByGroup[application.Group][application.Team]++
+	}
+	sort.Strings(groups)
+
+	var entities []*Entity
+	for _, group := range groups {
+		groupOwner := ""
+		for team, count := range teamsByGroup[group] {
+			if team != "" && (groupOwner == "" || count > teamsByGroup[group][groupOwner] || (count == teamsByGroup[group][groupOwner] && team < groupOwner)) {
+				groupOwner = team
+			}
+		}
+		entities = append(entities, &Entity{
+			ApiVersion: API_VERSION,
+			Kind:       KIND_SYSTEM,
+			Metadata: Metadata{
+				Name:        EntityName(group),
+				Title:       group,
+				Annotations: map[string]string{ANNOTATION_GROUP: group},
+			},
+			Spec: Spec{Owner: owner(groupOwner)},
+		})
+	}
+	return entities
+}
+
+// resources - one Resource per infrastructure type
+func (e *CatalogExporter) resources() []*Entity {
+	var types []string
+	for _, application := range e.project.Applications {
+		for _, infrastructureDependency := range application.InfrastructureDependencies {
+			types = appendMissing(types, infrastructureDependency.Type)
+		}
+	}
+	sort.Strings(types)
+
+	var entities []*Entity
+	for _, infrastructureType := range types {
+		entities = append(entities, &Entity{
+			ApiVersion: API_VERSION,
+			Kind:       KIND_RESOURCE,
+			Metadata:   Metadata{Name: EntityName(infrastructureType), Title: infrastructureType},
+			Spec:       Spec{Type: "infrastructure", Owner: UNKNOWN_OWNER},
+		})
+	}
+	return entities
+}
+
+func apiName(applicationName string, serviceName string) string {
+	return EntityName(applicationName + "-" + serviceName)
+}
+
+func owner(team string) string {
+	if team == "" {
+		return UNKNOWN_OWNER
+	}
+	return "group:" + EntityName(team)
+}
+
+func lifecycle(status core.Status) string {
+	if status == core.STATUS_PLANNED {
+		return LIFECYCLE_EXPERIMENTAL
+	}
+	return LIFECYCLE_PRODUCTION
+}
+
+// annotations - returns the annotations with a value (nil if there are none)
+func annotations(values map[string]string) map[string]string {
+	var result map[string]string
+	for key, value := range values {
+		if value == "" {
+			continue
+		}
+		if result == nil {
+			result = make(map[string]string)
+		}
+		result[key] = value
+	}
+	return result
+}
+
+func appendMissing(values []string, value string) []string {
+	for _, existing := range values {
+		if existing == value {
+			return values
+		}
+	}
+	return append(values, value)
+}
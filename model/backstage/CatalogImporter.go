@@ -0,0 +1,192 @@
+package backstage
+
+import (
+	"errors"
+	"fmt"
+	"os"
+	"path/filepath"
+	"strings"
+
+	"github.com/AOEpeople/vistecture/v2/model/core"
+)
+
+type (
+	//CatalogImporter - maps Backstage Components with their APIs back to applications (the reverse of the CatalogExporter)
+	CatalogImporter struct {
+		entities []*Entity
+		//apis - API entities by name
+		apis map[string]*Entity
+		//providers - name of the providing component by API name
+		providers  map[string]string
+		systems    map[string]*Entity
+		vocabulary *core.Vocabulary
+	}
+)
+
+//CreateCatalogImporter - Factory
+func CreateCatalogImporter(entities []*Entity) *CatalogImporter {
+	importer := &CatalogImporter{
+		entities:   entities,
+		apis:       make(map[string]*Entity),
+		providers:  make(map[string]string),
+		systems:    make(map[string]*Entity),
+		vocabulary: core.DefaultVocabulary(),
+	}
+	for _, entity := range entities {
+		switch entity.Kind {
+		case KIND_API:
+			importer.apis[entity.Metadata.Name] = entity
+		case KIND_SYSTEM:
+			importer.systems[entity.Metadata.Name] = entity
+		case KIND_COMPONENT:
+			for _, reference := range entity.Spec.ProvidesApis {
+				_, name := ParseEntityRef(reference)
+				importer.providers[name] = entity.Metadata.Name
+			}
+		}
+	}
+	return importer
+}
+
+//ReadEntitiesFromPath - reads the entities of a catalog file or of all YAML files in a folder (recursive)
+func ReadEntitiesFromPath(path string) ([]*Entity, error) {
+	var entities []*Entity
+	err := filepath.Walk(path, func(fileName string, info os.FileInfo, err error) error {
+		if err != nil {
+			return err
+		}
+		if info.IsDir() || !(strings.HasSuffix(fileName, ".yml") || strings.HasSuffix(fileName, ".yaml")) {
+			return nil
+		}
+		file, err := os.Open(fileName)
+		if err != nil {
+			return err
+		}
+		defer file.Close()
+		read, err := ReadEntities(file)
+		if err != nil {
+			return errors.New(fmt.Sprintf("%v: %v", fileName, err))
+		}
+		entities = append(entities, read...)
+		return nil
+	})
+	return entities, err
+}
+
+//Applications - returns an application per Component. The provided APIs become services, consumed APIs and components dependencies and resources infrastructure dependencies
+func (i *CatalogImporter) Applications() []*core.Application {
+	var applications []*core.Application
+	for _, entity := range i.entities {
+		if entity.Kind == KIND_COMPONENT {
+			applications = append(applications, i.application(entity))
+		}
+	}
+	return applications
+}
+
+func (i *CatalogImporter) application(component *Entity) *core.Application {
+	application := &core.Application{
+		Name:       component.Metadata.Name,
+		Title:      component.Metadata.Title,
+		Summary:    component.Metadata.Description,
+		Team:       i.team(component.Spec.Owner),
+		Group:      i.group(component),
+		Technology: component.Metadata.Annotations[ANNOTATION_TECHNOLOGY],
+		Category:   component.Metadata.Annotations[ANNOTATION_CATEGORY],
+		Status:     status(component.Spec.Lifecycle),
+	}
+	for _, reference := range component.Spec.ProvidesApis {
+		_, name := ParseEntityRef(reference)
+		application.ProvidedServices = append(application.ProvidedServices, i.service(application, name))
+	}
+	for _, reference := range component.Spec.ConsumesApis {
+		_, name := ParseEntityRef(reference)
+		application.Dependencies = append(application.Dependencies, core.Dependency{Reference: i.serviceReference(name)})
+	}
+	for _, reference := range component.Spec.DependsOn {
+		kind, name := ParseEntityRef(reference)
+		switch kind {
+		case "resource":
+			application.InfrastructureDependencies = append(application.InfrastructureDependencies, core.InfrastructureDependency{Type: name})
+		case "", "component":
+			application.Dependencies = append(application.Dependencies, core.Dependency{Reference: name})
+		}
+	}
+	return application
+}
+
+func (i *CatalogImporter) service(application *core.Application, apiName string) core.Service {
+	service := core.Service{Name: serviceName(application.Name, apiName, i.apis[apiName])}
+	if api, found := i.apis[apiName]; found {
+		service.Title = api.Metadata.Title
+		service.Summary = api.Metadata.Description
+		service.Type = i.serviceType(api.Spec.Type)
+		if status(api.Spec.Lifecycle) != application.Status {
+			service.Status = status(api.Spec.Lifecycle)
+		}
+	}
+	return service
+}
+
+// serviceReference - the reference (application.service) of a consumed API - the API name if the providing component is unknown
+func (i *CatalogImporter) serviceReference(apiName string) string {
+	provider, found := i.providers[apiName]
+	if !found {
+		return apiName
+	}
+	return provider + "." + serviceName(provider, apiName, i.apis[apiName])
+}
+
+// group - the original group annotation or the title of the system
+func (i *CatalogImporter) group(component *Entity) string {
+	if group := component.Metadata.Annotations[ANNOTATION_GROUP]; group != "" {
+		return group
+	}
+	if component.Spec.System == "" {
+		return ""
+	}
+	_, name := ParseEntityRef(component.Spec.System)
+	if system, found := i.systems[name]; found {
+		if group := system.Metadata.Annotations[ANNOTATION_GROUP]; group != "" {
+			return group
+		}
+	}
+	return name
+}
+
+func (i *CatalogImporter) team(owner string) string {
+	_, name := ParseEntityRef(owner)
+	if name == UNKNOWN_OWNER {
+		return ""
+	}
+	return name
+}
+
+// serviceType - keeps known service types and maps the common Backstage API types
+func (i *CatalogImporter) serviceType(apiType string) core.ServiceType {
+	switch apiType {
+	case "openapi", "graphql", "grpc", "trpc":
+		return core.SERVICE_TYPE_API
+	case "asyncapi":
+		return core.SERVICE_TYPE_TOPIC
+	}
+	if apiType != "other" && i.vocabulary.IsKnownServiceType(core.ServiceType(apiType)) {
+		return core.ServiceType(apiType)
+	}
+	return ""
+}
+
+// serviceName - the original service annotation or the API name without the application prefix
+func serviceName(applicationName string, apiName string, api *Entity) string {
+	if api != nil && api.Metadata.Annotations[ANNOTATION_SERVICE] != "" {
+		return api.Metadata.Annotations[ANNOTATION_SERVICE]
+	}
+	return strings.TrimPrefix(apiName, applicationName+"-")
+}
+
+func status(lifecycle string) core.Status {
+	if lifecycle == LIFECYCLE_EXPERIMENTAL {
+		return core.STATUS_PLANNED
+	}
+	return ""
+}
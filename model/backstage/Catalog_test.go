@@ -0,0 +1,95 @@
+package backstage
+
+import (
+	"bytes"
+	"reflect"
+	"strings"
+	"testing"
+
+	"github.com/AOEpeople/vistecture/v2/model/core"
+)
+
+func TestCatalogExporter_Entities(t *testing.T) {
+	project := &core.Project{
+		Name: "Shop",
+		Applications: []*core.Application{
+			{
+				Name:       "checkout",
+				Team:       "team1",
+				Group:      "shop/sales",
+				Technology: "go",
+				ProvidedServices: []core.Service{
+					{Name: "api", Type: core.SERVICE_TYPE_API},
+				},
+				InfrastructureDependencies: []core.InfrastructureDependency{{Type: "rdbms"}},
+				Dependencies: []core.Dependency{
+					{Reference: "payment.auth"},
+					{Reference: "cart"},
+				},
+			},
+			{Name: "cart", Status: core.STATUS_PLANNED},
+			{
+				Name:             "payment",
+				Category:         core.CATEGORY_EXTERNAL,
+				ProvidedServices: []core.Service{{Name: "auth"}},
+			},
+		},
+	}
+
+	var out bytes.Buffer
+	if err := WriteEntities(&out, CreateCatalogExporter(project).Entities()); err != nil {
+		t.Fatal(err)
+	}
+	catalog := out.String()
+	for _, expected := range []string{
+		"---\napiVersion: backstage.io/v1alpha1\nkind: Group\nmetadata:\n  name: team1\n",
+		"kind: System\nmetadata:\n  name: shop-sales\n  title: shop/sales\n",
+		"kind: Resource\nmetadata:\n  name: rdbms\n",
+		"  lifecycle: production\n  owner: group:team1\n  system: shop-sales\n  providesApis:\n  - checkout-api\n  consumesApis:\n  - payment-auth\n  dependsOn:\n  - component:cart\n  - resource:rdbms\n",
+		"kind: API\nmetadata:\n  name: payment-auth\n",
+		"  lifecycle: experimental\n  owner: unknown\n",
+	} {
+		if !strings.Contains(catalog, expected) {
+			t.Errorf("Expected %q in catalog:\n%v", expected, catalog)
+		}
+	}
+
+	entities, err := ReadEntities(strings.NewReader(catalog))
+	if err != nil {
+		t.Fatal(err)
+	}
+	applications := CreateCatalogImporter(entities).Applications()
+	if len(applications) != 3 {
+		t.Fatalf("Expected 3 imported applications, got %v", len(applications))
+	}
+	checkout := applications[0]
+	if checkout.Name != "checkout" || checkout.Team != "team1" || checkout.Group != "shop/sales" || checkout.Technology != "go" {
+		t.Errorf("Unexpected imported application %#v", checkout)
+	}
+	if !reflect.DeepEqual(checkout.ProvidedServices, project.Applications[0].ProvidedServices) {
+		t.Errorf("Expected services %v got %v", project.Applications[0].ProvidedServices, checkout.ProvidedServices)
+	}
+	if !reflect.DeepEqual(checkout.Dependencies, project.Applications[0].Dependencies) {
+		t.Errorf("Expected dependencies %v got %v", project.Applications[0].Dependencies, checkout.Dependencies)
+	}
+	if !reflect.DeepEqual(checkout.InfrastructureDependencies, project.Applications[0].InfrastructureDependencies) {
+		t.Errorf("Expected infrastructure dependencies %v got %v", project.Applications[0].InfrastructureDependencies, checkout.InfrastructureDependencies)
+	}
+	if applications[1].Status != core.STATUS_PLANNED || applications[2].Category != core.CATEGORY_EXTERNAL {
+		t.Errorf("Expected status and category to be imported: %#v %#v", applications[1], applications[2])
+	}
+}
+
+func TestParseEntityRef(t *testing.T) {
+	for reference, expected := range map[string][2]string{
+		"checkout":                   {"", "checkout"},
+		"component:checkout":         {"component", "checkout"},
+		"Component:default/checkout": {"component", "checkout"},
+		"default/checkout":           {"", "checkout"},
+	} {
+		kind, name := ParseEntityRef(reference)
+		if kind != expected[0] || name != expected[1] {
+			t.Errorf("Expected %v for %v got %v %v", expected, reference, kind, name)
+		}
+	}
+}
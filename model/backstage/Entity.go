@@ -0,0 +1,118 @@
+package backstage
+
+import (
+	"errors"
+	"fmt"
+	"io"
+	"regexp"
+	"strings"
+
+	"gopkg.in/yaml.v2"
+)
+
+type (
+	//Entity - a Backstage catalog entity (only the fields used by vistecture)
+	Entity struct {
+		ApiVersion string   `yaml:"apiVersion"`
+		Kind       string   `yaml:"kind"`
+		Metadata   Metadata `yaml:"metadata"`
+		Spec       Spec     `yaml:"spec"`
+	}
+
+	Metadata struct {
+		Name        string            `yaml:"name"`
+		Namespace   string            `yaml:"namespace,omitempty"`
+		Title       string            `yaml:"title,omitempty"`
+		Description string            `yaml:"description,omitempty"`
+		Annotations map[string]string `yaml:"annotations,omitempty"`
+		Tags        []string          `yaml:"tags,omitempty"`
+	}
+
+	//Spec - union of the spec fields of the supported kinds (Component, API, Group, System and Resource)
+	Spec struct {
+		Type         string   `yaml:"type,omitempty"`
+		Lifecycle    string   `yaml:"lifecycle,omitempty"`
+		Owner        string   `yaml:"owner,omitempty"`
+		System       string   `yaml:"system,omitempty"`
+		Definition   string   `yaml:"definition,omitempty"`
+		ProvidesApis []string `yaml:"providesApis,omitempty"`
+		ConsumesApis []string `yaml:"consumesApis,omitempty"`
+		DependsOn    []string `yaml:"dependsOn,omitempty"`
+		//Children - required for groups (written as empty list)
+		Children *[]string `yaml:"children,omitempty"`
+	}
+)
+
+const (
+	API_VERSION = "backstage.io/v1alpha1"
+
+	KIND_COMPONENT = "Component"
+	KIND_API       = "API"
+	KIND_GROUP     = "Group"
+	KIND_SYSTEM    = "System"
+	KIND_RESOURCE  = "Resource"
+
+	LIFECYCLE_PRODUCTION   = "production"
+	LIFECYCLE_EXPERIMENTAL = "experimental"
+
+	ANNOTATION_TECHNOLOGY = "vistecture/technology"
+	ANNOTATION_CATEGORY   = "vistecture/category"
+	ANNOTATION_GROUP      = "vistecture/group"
+	ANNOTATION_SERVICE    = "vistecture/service"
+
+	//UNKNOWN_OWNER - owner of entities without team (the owner is required by Backstage)
+	UNKNOWN_OWNER = "unknown"
+)
+
+var invalidNameCharacters = regexp.MustCompile(`[^A-Za-z0-9._-]+`)
+
+//WriteEntities - writes the entities as multi document YAML (the format of catalog-info.yaml)
+func WriteEntities(w io.Writer, entities []*Entity) error {
+	for _, entity := range entities {
+		content, err := yaml.Marshal(entity)
+		if err != nil {
+			return err
+		}
+		if _, err := io.WriteString(w, "---\n"+string(content)); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+//ReadEntities - reads all entities of a (multi document) YAML file. Documents without kind are skipped
+func ReadEntities(r io.Reader) ([]*Entity, error) {
+	var entities []*Entity
+	decoder := yaml.NewDecoder(r)
+	for {
+		var entity Entity
+		err := decoder.Decode(&entity)
+		if err == io.EOF {
+			return entities, nil
+		}
+		if err != nil {
+			return entities, errors.New(fmt.Sprintf("Cannot parse backstage entity: %v", err))
+		}
+		if entity.Kind != "" {
+			entities = append(entities, &entity)
+		}
+	}
+}
+
+//EntityName - returns a valid entity name ([a-zA-Z0-9] separated by -, _ or .)
+func EntityName(name string) string {
+	return strings.Trim(invalidNameCharacters.ReplaceAllString(name, "-"), "-_.")
+}
+
+//ParseEntityRef - returns the kind (empty if not part of the reference) and the name of a reference in the format [kind:][namespace/]name
+func ParseEntityRef(reference string) (string, string) {
+	kind := ""
+	if i := strings.Index(reference, ":"); i >= 0 {
+		kind = reference[:i]
+		reference = reference[i+1:]
+	}
+	if i := strings.LastIndex(reference, "/"); i >= 0 {
+		reference = reference[i+1:]
+	}
+	return strings.ToLower(kind), reference
+}
@@ -0,0 +1,219 @@
+package compose
+
+import (
+	"errors"
+	"fmt"
+	"io/ioutil"
+	"path/filepath"
+	"regexp"
+	"strings"
+
+	"github.com/AOEpeople/vistecture/v2/model/core"
+	yamlv3 "gopkg.in/yaml.v3"
+)
+
+type (
+	//Service - a service of a docker-compose file (only the fields used by the importer)
+	Service struct {
+		Image string `yaml:"image"`
+		//DependsOn - list of service names or map of service name to condition
+		DependsOn yamlv3.Node `yaml:"depends_on"`
+		Links     []string    `yaml:"links"`
+		//Ports - short ("8080:80/tcp") or long syntax (target, published)
+		Ports  []yamlv3.Node `yaml:"ports"`
+		Expose []string      `yaml:"expose"`
+		//Labels - list ("key=value") or map
+		Labels yamlv3.Node `yaml:"labels"`
+	}
+
+	//namedService - a compose service with its name and the line it is defined in
+	namedService struct {
+		name    string
+		line    int
+		service Service
+	}
+)
+
+const (
+	LABEL_PREFIX = "vistecture."
+)
+
+var composeFileName = regexp.MustCompile(`^(docker-)?compose([.-].*)?\.ya?ml$`)
+
+//IsComposeFile - true for docker-compose*.yml and compose*.yml files (and the .yaml variants)
+func IsComposeFile(fileName string) bool {
+	return composeFileName.MatchString(filepath.Base(fileName))
+}
+
+//ReadApplications - returns an application per compose service that does not run a known infrastructure image.
+//depends_on and links become dependencies (infrastructure dependencies for infrastructure services), ports provided services and vistecture.* labels the attributes
+func ReadApplications(fileName string) ([]*core.Application, error) {
+	content, err := ioutil.ReadFile(fileName)
+	if err != nil {
+		return nil, err
+	}
+	services, err := parse(content)
+	if err != nil {
+		return nil, errors.New(fmt.Sprintf("%v: cannot parse compose file: %v", fileName, err))
+	}
+
+	infrastructure := make(map[string]string)
+	for _, service := range services {
+		if infrastructureType := core.InfrastructureTypeOfImage(service.service.Image); infrastructureType != "" {
+			infrastructure[service.name] = infrastructureType
+		}
+	}
+
+	var applications []*core.Application
+	for _, service := range services {
+		if _, isInfrastructure := infrastructure[service.name]; isInfrastructure {
+			continue
+		}
+		application := &core.Application{
+			Name:   service.name,
+			Source: core.SourcePosition{File: fileName, Line: service.line},
+		}
+		for _, dependency := range dependencies(service.service) {
+			if infrastructureType, isInfrastructure := infrastructure[dependency]; isInfrastructure {
+				application.AddInfrastructureDependency(infrastructureType)
+			} else if !hasDependency(application, dependency) {
+				application.Dependencies = append(application.Dependencies, core.Dependency{Reference: dependency})
+			}
+		}
+		for _, port := range containerPorts(service.service) {
+			application.AddProvidedService(core.Service{Name: "port-" + port, Type: core.SERVICE_TYPE_API})
+		}
+		applyLabels(application, labels(service.service.Labels))
+		applications = append(applications, application)
+	}
+	return applications, nil
+}
+
+// parse - returns the services in the order of the file
+func parse(content []byte) ([]namedService, error) {
+	var document yamlv3.Node
+	if err := yamlv3.Unmarshal(content, &document); err != nil {
+		return nil, err
+	}
+	if len(document.Content) == 0 || document.Content[0].Kind != yamlv3.MappingNode {
+		return nil, errors.New("no services found")
+	}
+	root := document.Content[0]
+	for i := 0; i+1 < len(root.Content); i += 2 {
+		if root.Content[i].Value != "services" {
+			continue
+		}
+		var services []namedService
+		servicesNode := root.Content[i+1]
+		for j := 0; j+1 < len(servicesNode.Content); j += 2 {
+			service := namedService{name: servicesNode.Content[j].Value, line: servicesNode.Content[j].Line}
+			if err := servicesNode.Content[j+1].Decode(&service.service); err != nil {
+				return nil, errors.New(fmt.Sprintf("service %v: %v", service.name, err))
+			}
+			services = append(services, service)
+		}
+		return services, nil
+	}
+	return nil, errors.New("no services found")
+}
+
+// dependencies - the services of depends_on and links (without alias)
+func dependencies(service Service) []string {
+	var result []string
+	switch service.DependsOn.Kind {
+	case yamlv3.SequenceNode:
+		for _, item := range service.DependsOn.Content {
+			result = append(result, item.Value)
+		}
+	case yamlv3.MappingNode:
+		for i := 0; i < len(service.DependsOn.Content); i += 2 {
+			result = append(result, service.DependsOn.Content[i].Value)
+		}
+	}
+	for _, link := range service.Links {
+		result = append(result, strings.SplitN(link, ":", 2)[0])
+	}
+	return result
+}
+
+// containerPorts - the container ports of ports (short and long syntax) and expose, e.g. "80" for "127.0.0.1:8080:80/tcp"
+func containerPorts(service Service) []string {
+	var result []string
+	for _, port := range service.Ports {
+		if port.Kind == yamlv3.MappingNode {
+			var longSyntax struct {
+				Target string `yaml:"target"`
+			}
+			if port.Decode(&longSyntax) == nil && longSyntax.Target != "" {
+				result = append(result, longSyntax.Target)
+			}
+			continue
+		}
+		parts := strings.Split(strings.SplitN(port.Value, "/", 2)[0], ":")
+		result = append(result, parts[len(parts)-1])
+	}
+	for _, expose := range service.Expose {
+		result = append(result, strings.SplitN(expose, "/", 2)[0])
+	}
+	return result
+}
+
+// labels - the labels of the list or map syntax
+func labels(node yamlv3.Node) map[string]string {
+	result := make(map[string]string)
+	switch node.Kind {
+	case yamlv3.SequenceNode:
+		for _, item := range node.Content {
+			keyValue := strings.SplitN(item.Value, "=", 2)
+			if len(keyValue) == 2 {
+				result[keyValue[0]] = keyValue[1]
+			}
+		}
+	case yamlv3.MappingNode:
+		for i := 0; i+1 < len(node.Content); i += 2 {
+			result[node.Content[i].Value] = node.Content[i+1].Value
+		}
+	}
+	return result
+}
+
+// applyLabels - vistecture.team, .group, .technology, .title, .summary, .description, .category and .status set the attributes, all other vistecture.* labels become properties
+func applyLabels(application *core.Application, labels map[string]string) {
+	for key, value := range labels {
+		if !strings.HasPrefix(key, LABEL_PREFIX) {
+			continue
+		}
+		switch attribute := strings.TrimPrefix(key, LABEL_PREFIX); attribute {
+		case "team":
+			application.Team = value
+		case "group":
+			application.Group = value
+		case "technology":
+			application.Technology = value
+		case "title":
+			application.Title = value
+		case "summary":
+			application.Summary = value
+		case "description":
+			application.Description = value
+		case "category":
+			application.Category = value
+		case "status":
+			application.Status = core.Status(value)
+		default:
+			if application.Properties == nil {
+				application.Properties = make(map[string]string)
+			}
+			application.Properties[strings.TrimPrefix(attribute, "properties.")] = value
+		}
+	}
+}
+
+func hasDependency(application *core.Application, reference string) bool {
+	for _, dependency := range application.Dependencies {
+		if dependency.Reference == reference {
+			return true
+		}
+	}
+	return false
+}
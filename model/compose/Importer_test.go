@@ -0,0 +1,67 @@
+package compose
+
+import (
+	"reflect"
+	"testing"
+
+	"github.com/AOEpeople/vistecture/v2/model/core"
+)
+
+func TestIsComposeFile(t *testing.T) {
+	for fileName, expected := range map[string]bool{
+		"docker-compose.yml":          true,
+		"stack/docker-compose.yaml":   true,
+		"docker-compose.override.yml": true,
+		"compose.yaml":                true,
+		"compose-dev.yml":             true,
+		"shop.yml":                    false,
+		"composer.yml":                false,
+	} {
+		if IsComposeFile(fileName) != expected {
+			t.Errorf("Expected IsComposeFile(%v) to be %v", fileName, expected)
+		}
+	}
+}
+
+func TestReadApplications(t *testing.T) {
+	applications, err := ReadApplications("fixtures/docker-compose.yml")
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(applications) != 2 {
+		t.Fatalf("Expected shop and orders (db, cache and broker are infrastructure), got %v", applications)
+	}
+
+	shop := applications[0]
+	if shop.Name != "shop" || shop.Source.Line != 3 {
+		t.Errorf("Expected shop defined in line 3, got %v in %v", shop.Name, shop.Source)
+	}
+	if shop.Team != "storefront" || shop.Technology != "php" || shop.Summary != "The online shop" {
+		t.Errorf("Expected the attributes of the labels, got %#v", shop)
+	}
+	if !reflect.DeepEqual(shop.Properties, map[string]string{"repository": "git@example.com:shop.git"}) {
+		t.Errorf("Expected only the vistecture properties, got %v", shop.Properties)
+	}
+	expectedServices := []core.Service{{Name: "port-80", Type: core.SERVICE_TYPE_API}, {Name: "port-443", Type: core.SERVICE_TYPE_API}}
+	if !reflect.DeepEqual(shop.ProvidedServices, expectedServices) {
+		t.Errorf("Expected services %v, got %v", expectedServices, shop.ProvidedServices)
+	}
+	if len(shop.Dependencies) != 1 || shop.Dependencies[0].Reference != "orders" {
+		t.Errorf("Expected dependency to orders, got %v", shop.Dependencies)
+	}
+	if !reflect.DeepEqual(shop.InfrastructureDependencies, []core.InfrastructureDependency{{Type: "redis"}}) {
+		t.Errorf("Expected redis, got %v", shop.InfrastructureDependencies)
+	}
+
+	orders := applications[1]
+	if orders.Group != "checkout" || orders.Status != core.STATUS_PLANNED {
+		t.Errorf("Expected the attributes of the label list, got %#v", orders)
+	}
+	if !reflect.DeepEqual(orders.ProvidedServices, []core.Service{{Name: "port-8080", Type: core.SERVICE_TYPE_API}}) {
+		t.Errorf("Expected port-8080 once, got %v", orders.ProvidedServices)
+	}
+	expectedInfrastructure := []core.InfrastructureDependency{{Type: "postgres"}, {Type: "rabbitmq"}}
+	if !reflect.DeepEqual(orders.InfrastructureDependencies, expectedInfrastructure) {
+		t.Errorf("Expected infrastructure %v, got %v", expectedInfrastructure, orders.InfrastructureDependencies)
+	}
+}
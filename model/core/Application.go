@@ -25,7 +25,9 @@ type (
 		Dependencies               []Dependency               `json:"dependencies" yaml:"dependencies"`
 		Display                    ApplicationDisplaySettings `json:"display,omitempty" yaml:"display,omitempty"`
 		Properties                 map[string]string          `json:"properties" yaml:"properties"`
-		Status                     string                     `json:"status" yaml:"status"`
+		Status                     Status                     `json:"status" yaml:"status"`
+		//Source - where the application is defined
+		Source SourcePosition `json:"source" yaml:"-"`
 	}
 
 	ApplicationDisplaySettings struct {
@@ -46,24 +48,100 @@ type (
 	}
 )
 
-const (
-	STATUS_PLANNED    = "planned"
-	CATEGORY_EXTERNAL = "external"
-)
+//Clone - returns a deep copy of the application (no maps or slices are shared with the original)
+func (a *Application) Clone() *Application {
+	clone := *a
+	clone.ProvidedServices = nil
+	for _, service := range a.ProvidedServices {
+		clone.ProvidedServices = append(clone.ProvidedServices, service.Clone())
+	}
+	clone.InfrastructureDependencies = append([]InfrastructureDependency(nil), a.InfrastructureDependencies...)
+	clone.Dependencies = cloneDependencies(a.Dependencies)
+	clone.Properties = cloneProperties(a.Properties)
+	return &clone
+}
 
 //Validate - validates the Application
 func (a *Application) Validate() []error {
 	var foundErrors []error
 
 	if len(a.Name) <= 0 {
-		foundErrors = append(foundErrors, errors.New("a with no name found."))
+		foundErrors = append(foundErrors, a.newValidationError(CHECK_APPLICATION_NAME, "a with no name found."))
 	}
 	if strings.Contains(a.Name, ".") {
-		foundErrors = append(foundErrors, errors.New("a name contains '.'"))
+		foundErrors = append(foundErrors, a.newValidationError(CHECK_APPLICATION_NAME, "a name contains '.'"))
 	}
 	return foundErrors
 }
 
+//ValidateVocabulary - reports relationships, statuses, service types and categories that are not part of the vocabulary
+func (a *Application) ValidateVocabulary(vocabulary *Vocabulary) []error {
+	var foundErrors []error
+
+	if !vocabulary.IsKnownCategory(a.Category) {
+		foundErrors = append(foundErrors, a.newValidationError(CHECK_UNKNOWN_VALUE, unknownValueMessage("category", a.Category, "of application '"+a.Name+"'", vocabulary.Categories, "categories")))
+	}
+	if !vocabulary.IsKnownStatus(a.Status) {
+		foundErrors = append(foundErrors, a.newValidationError(CHECK_UNKNOWN_VALUE, unknownValueMessage("status", string(a.Status), "of application '"+a.Name+"'", vocabulary.Statuses, "statuses")))
+	}
+	foundErrors = append(foundErrors, a.validateDependenciesVocabulary(vocabulary, "", a.Dependencies)...)
+	for _, service := range a.ProvidedServices {
+		if !vocabulary.IsKnownServiceType(service.Type) {
+			foundErrors = append(foundErrors, a.newServiceValidationError(service, unknownValueMessage("service type", string(service.Type), "of service '"+a.Name+"."+service.Name+"'", vocabulary.ServiceTypes, "serviceTypes")))
+		}
+		if !vocabulary.IsKnownStatus(service.Status) {
+			foundErrors = append(foundErrors, a.newServiceValidationError(service, unknownValueMessage("status", string(service.Status), "of service '"+a.Name+"."+service.Name+"'", vocabulary.Statuses, "statuses")))
+		}
+		if !vocabulary.IsKnownSecurityLevel(service.SecurityLevel) {
+			foundErrors = append(foundErrors, a.newServiceValidationError(service, unknownValueMessage("security level", service.SecurityLevel, "of service '"+a.Name+"."+service.Name+"'", vocabulary.SecurityLevels, "securityLevels")))
+		}
+		foundErrors = append(foundErrors, a.validateDependenciesVocabulary(vocabulary, service.Name, service.Dependencies)...)
+	}
+	return foundErrors
+}
+
+func (a *Application) validateDependenciesVocabulary(vocabulary *Vocabulary, serviceName string, dependencies []Dependency) []error {
+	var foundErrors []error
+	for _, dependency := range dependencies {
+		element := "of dependency from '" + a.Name + "' to '" + dependency.Reference + "'"
+		if !vocabulary.IsKnownRelationship(dependency.Relationship) {
+			foundErrors = append(foundErrors, a.newDependencyValidationError(serviceName, dependency, unknownValueMessage("relationship", string(dependency.Relationship), element, vocabulary.Relationships, "relationships")))
+		}
+		if !vocabulary.IsKnownStatus(dependency.Status) {
+			foundErrors = append(foundErrors, a.newDependencyValidationError(serviceName, dependency, unknownValueMessage("status", string(dependency.Status), element, vocabulary.Statuses, "statuses")))
+		}
+	}
+	return foundErrors
+}
+
+func (a *Application) newServiceValidationError(service Service, message string) *ValidationError {
+	validationError := a.newValidationError(CHECK_UNKNOWN_VALUE, message)
+	validationError.Service = service.Name
+	if service.Source.IsKnown() {
+		validationError.Source = service.Source
+	}
+	return validationError
+}
+
+func (a *Application) newDependencyValidationError(serviceName string, dependency Dependency, message string) *ValidationError {
+	validationError := a.newValidationError(CHECK_UNKNOWN_VALUE, message)
+	validationError.Service = serviceName
+	validationError.Dependency = dependency.Reference
+	if dependency.Source.IsKnown() {
+		validationError.Source = dependency.Source
+	}
+	return validationError
+}
+
+func (a *Application) newValidationError(checkId string, message string) *ValidationError {
+	return &ValidationError{
+		CheckId:     checkId,
+		Application: a.Name,
+		Source:      a.Source,
+		Message:     message,
+	}
+}
+
 //GetDescriptionHtml - helper that renders the description text as markdown - to be used in HTML documentations
 func (a *Application) GetDescriptionHtml() template.HTML {
 	return template.HTML(blackfriday.MarkdownCommon([]byte(a.Description)))
@@ -153,13 +231,23 @@ func (a *Application) IsOpenHostApp() bool {
 		return false
 	}
 	for _, service := range a.ProvidedServices {
-		if !service.IsOpenHost && service.Type != "gui" {
+		if !service.IsOpenHost && service.Type != SERVICE_TYPE_GUI {
 			return false
 		}
 	}
 	return true
 }
 
+//HasPublicService - returns true if at least one of the provided services is public
+func (a *Application) HasPublicService() bool {
+	for _, service := range a.ProvidedServices {
+		if service.IsPublic {
+			return true
+		}
+	}
+	return false
+}
+
 //GetGroupPath - returns the list of Groups the application is part of (parent to leaf)
 func (a *Application) GetGroupPath() []string {
 	return strings.Split(a.Group, "/")
@@ -173,38 +261,7 @@ func (a *Application) GetMainGroup() string {
 
 //GetDependenciesGrouped - returns a list of grouped dependencies for this application to others. Useful if you are not interested in the indivudual dependencies but only the general "links" from this app to others
 func (a *Application) GetDependenciesGrouped(project *Project) []*DependenciesGrouped {
-	var result []*DependenciesGrouped
-
-	//private helper func
-	getGroupedDepToAppInList := func(list []*DependenciesGrouped, depApp *Application) *DependenciesGrouped {
-		for _, group := range list {
-			if group.Application == depApp {
-				return group
-
-			}
-		}
-		return nil
-	}
-
-	for _, dep := range a.GetAllDependencies() {
-		depApp, err := dep.GetApplication(project)
-		if err != nil {
-			continue
-		}
-		//add dependency to existing depGrouped if found
-		groupedDep := getGroupedDepToAppInList(result, depApp)
-		if groupedDep != nil {
-			groupedDep.Dependencies = append(groupedDep.Dependencies, dep)
-			continue
-		}
-		//else append new
-		result = append(result, &DependenciesGrouped{
-			Application:       depApp,
-			SourceApplication: a,
-			Dependencies:      []Dependency{dep},
-		})
-	}
-	return result
+	return project.Graph().DependenciesGrouped(a)
 }
 
 //GetMissingDependencies - returns a list of references application names that are not in the project
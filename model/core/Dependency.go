@@ -11,13 +11,15 @@ type (
 	Dependency struct {
 		Reference      string            `json:"reference" yaml:"reference"`
 		Description    string            `json:"description" yaml:"description"`
-		Relationship   string            `json:"relationship" yaml:"relationship"`
+		Relationship   Relationship      `json:"relationship" yaml:"relationship"`
 		IsSameLevel    bool              `json:"isSameLevel" yaml:"isSameLevel"`
 		IsBrowserBased bool              `json:"isBrowserBased" yaml:"isBrowserBased"`
-		Status         string            `json:"status" yaml:"status"`
+		Status         Status            `json:"status" yaml:"status"`
 		Properties     map[string]string `json:"properties" yaml:"properties"`
 		IsOptional     bool              `json:"isOptional" yaml:"isOptional"`
 		ConsumedEvents         []Event     `json:"events" yaml:"events"`
+		//Source - where the dependency is defined
+		Source SourcePosition `json:"source" yaml:"-"`
 	}
 
 	Event struct {
@@ -25,6 +27,37 @@ type (
 	}
 )
 
+//Clone - returns a deep copy of the dependency
+func (Dependency Dependency) Clone() Dependency {
+	Dependency.Properties = cloneProperties(Dependency.Properties)
+	Dependency.ConsumedEvents = append([]Event(nil), Dependency.ConsumedEvents...)
+	return Dependency
+}
+
+// cloneDependencies - deep copy of the dependencies (nil stays nil)
+func cloneDependencies(dependencies []Dependency) []Dependency {
+	if dependencies == nil {
+		return nil
+	}
+	clone := make([]Dependency, 0, len(dependencies))
+	for _, dependency := range dependencies {
+		clone = append(clone, dependency.Clone())
+	}
+	return clone
+}
+
+// cloneProperties - copy of the properties map (nil stays nil)
+func cloneProperties(properties map[string]string) map[string]string {
+	if properties == nil {
+		return nil
+	}
+	clone := make(map[string]string, len(properties))
+	for k, v := range properties {
+		clone[k] = v
+	}
+	return clone
+}
+
 // Returns the name of the "component" and "service" this dependecy points to
 // service might be empty if the dependency just defined the component
 func (Dependency *Dependency) GetApplicationAndServiceNames() (string, string) {
@@ -0,0 +1,475 @@
+package core
+
+import (
+	"errors"
+	"fmt"
+	"sort"
+	"strings"
+)
+
+type (
+	//Graph - the dependency graph of a project with adjacency in both directions. Get it from Project.Graph, which builds it once per project
+	Graph struct {
+		applications []*Application
+		byName       map[string]*Application
+		outgoing     map[string][]*GraphEdge
+		incoming     map[string][]*GraphEdge
+		between      map[[2]string][]*GraphEdge
+		missing      []*GraphEdge
+	}
+
+	//GraphEdge - one declared dependency from an application (or one of its provided services) to another application
+	GraphEdge struct {
+		From *Application
+		//SourceService - name of the provided service that declares the dependency - empty if declared on application level
+		SourceService string
+		//To - the referenced application - nil if the application is not part of the project
+		To *Application
+		//TargetName - name of the referenced application
+		TargetName string
+		//TargetService - name of the referenced service - empty if the dependency references the application only
+		TargetService string
+		Relationship  Relationship
+		Status        Status
+		IsOptional    bool
+		Dependency    Dependency
+	}
+
+	//ReachedApplication - an application found by walking the graph together with the number of hops needed to reach it
+	ReachedApplication struct {
+		Application *Application
+		Depth       int
+	}
+
+	//ClusterRelation - the dependencies from the applications of one cluster (e.g. a team or a group) to the applications of another cluster
+	ClusterRelation struct {
+		FromCluster string
+		ToCluster   string
+		Edges       []*GraphEdge
+	}
+
+	//EdgeFilter - decides if an edge should be followed while walking the graph
+	EdgeFilter func(edge *GraphEdge) bool
+
+	//GraphDirection - direction to walk the graph
+	GraphDirection int
+
+	//Clustering - groups the applications into clusters, e.g. by team or by main group (see TeamClustering and GroupClustering)
+	Clustering struct {
+		//Prefix - the kind of the clusters ("team" or "group"), drawers use it for the ids of the clusters
+		Prefix string
+		//ClusterOf - returns the cluster of an application (empty to skip the application)
+		ClusterOf func(application *Application) string
+		//HidePlanned - skips planned applications and dependencies
+		HidePlanned bool
+	}
+
+	//Cluster - the applications of one cluster and the colour to draw it
+	Cluster struct {
+		Name         string
+		Color        string
+		Applications []*Application
+	}
+
+	//Clusters - clusters sorted by name
+	Clusters []*Cluster
+)
+
+//UNGROUPED - the cluster of applications without group in the GroupClustering
+const UNGROUPED = "UNGROUPED"
+
+//ClusterColors - the colours of the clusters (assigned in the order of the cluster names)
+var ClusterColors = []string{"#9013a0", "#2936c4", "#147724", "#22a398", "#9e8142", "#bcae67", "#d62a2a"}
+
+const (
+	//DOWNSTREAM - follow dependencies (the applications that are used)
+	DOWNSTREAM GraphDirection = iota
+	//UPSTREAM - follow dependents (the applications that are using)
+	UPSTREAM
+)
+
+//CreateGraph - builds the dependency graph of all application and service dependencies of the project. Use Project.Graph to share it
+func CreateGraph(project *Project) *Graph {
+	g := &Graph{
+		byName:   make(map[string]*Application),
+		outgoing: make(map[string][]*GraphEdge),
+		incoming: make(map[string][]*GraphEdge),
+		between:  make(map[[2]string][]*GraphEdge),
+	}
+	if project == nil {
+		return g
+	}
+	g.applications = project.Applications
+	for _, app := range project.Applications {
+		if _, exists := g.byName[app.Name]; !exists {
+			g.byName[app.Name] = app
+		}
+	}
+	for _, app := range project.Applications {
+		if g.byName[app.Name] != app {
+			//duplicated names are resolved to the first application - like Project.FindApplication does
+			continue
+		}
+		for _, dependency := range app.Dependencies {
+			g.addEdge(app, "", dependency)
+		}
+		for _, service := range app.ProvidedServices {
+			for _, dependency := range service.Dependencies {
+				g.addEdge(app, service.Name, dependency)
+			}
+		}
+	}
+	return g
+}
+
+func (g *Graph) addEdge(from *Application, sourceService string, dependency Dependency) {
+	targetName, targetService := dependency.GetApplicationAndServiceNames()
+	edge := &GraphEdge{
+		From:          from,
+		SourceService: sourceService,
+		To:            g.byName[targetName],
+		TargetName:    targetName,
+		TargetService: targetService,
+		Relationship:  dependency.Relationship,
+		Status:        dependency.Status,
+		IsOptional:    dependency.IsOptional,
+		Dependency:    dependency,
+	}
+	g.outgoing[from.Name] = append(g.outgoing[from.Name], edge)
+	if edge.To == nil {
+		g.missing = append(g.missing, edge)
+		return
+	}
+	g.incoming[targetName] = append(g.incoming[targetName], edge)
+	key := [2]string{from.Name, targetName}
+	g.between[key] = append(g.between[key], edge)
+}
+
+//Applications - returns all applications of the graph in project order
+func (g *Graph) Applications() []*Application {
+	return g.applications
+}
+
+//Application - returns the application with the given name
+func (g *Graph) Application(name string) (*Application, bool) {
+	app, found := g.byName[name]
+	return app, found
+}
+
+//OutgoingEdges - returns all dependencies declared by the application (including the ones to missing applications)
+func (g *Graph) OutgoingEdges(applicationName string) []*GraphEdge {
+	return g.outgoing[applicationName]
+}
+
+//IncomingEdges - returns all dependencies that reference the application
+func (g *Graph) IncomingEdges(applicationName string) []*GraphEdge {
+	return g.incoming[applicationName]
+}
+
+//EdgesBetween - returns all dependencies from one application to another
+func (g *Graph) EdgesBetween(fromApplicationName string, toApplicationName string) []*GraphEdge {
+	return g.between[[2]string{fromApplicationName, toApplicationName}]
+}
+
+//MissingEdges - returns all dependencies that reference an application that is not part of the project
+func (g *Graph) MissingEdges() []*GraphEdge {
+	return g.missing
+}
+
+//MissingReferences - returns the distinct names of referenced applications that are not part of the project
+func (g *Graph) MissingReferences(applicationName string) []string {
+	var missing []string
+	for _, edge := range g.outgoing[applicationName] {
+		if edge.To == nil && !stringInSlice(edge.TargetName, missing) {
+			missing = append(missing, edge.TargetName)
+		}
+	}
+	return missing
+}
+
+//Dependencies - returns the distinct applications the application directly depends on
+func (g *Graph) Dependencies(applicationName string) []*Application {
+	return g.neighbours(applicationName, DOWNSTREAM, nil)
+}
+
+//Dependents - returns the distinct applications that directly depend on the application
+func (g *Graph) Dependents(applicationName string) []*Application {
+	return g.neighbours(applicationName, UPSTREAM, nil)
+}
+
+//TransitiveDependencies - returns every application that is directly or indirectly used by the application
+func (g *Graph) TransitiveDependencies(applicationName string) []*ReachedApplication {
+	return g.Walk(applicationName, DOWNSTREAM, 0, nil)
+}
+
+//TransitiveDependents - returns every application that directly or indirectly uses the application
+func (g *Graph) TransitiveDependents(applicationName string) []*ReachedApplication {
+	return g.Walk(applicationName, UPSTREAM, 0, nil)
+}
+
+//Walk - breadth first walk starting at the application. Every reached application is returned once with the shortest distance.
+// maxDepth <= 0 means unlimited. The start application itself is not part of the result
+func (g *Graph) Walk(applicationName string, direction GraphDirection, maxDepth int, filter EdgeFilter) []*ReachedApplication {
+	var result []*ReachedApplication
+	visited := map[string]bool{applicationName: true}
+	current := []string{applicationName}
+	for depth := 1; len(current) > 0 && (maxDepth <= 0 || depth <= maxDepth); depth++ {
+		var next []string
+		for _, name := range current {
+			for _, neighbour := range g.neighbours(name, direction, filter) {
+				if visited[neighbour.Name] {
+					continue
+				}
+				visited[neighbour.Name] = true
+				result = append(result, &ReachedApplication{Application: neighbour, Depth: depth})
+				next = append(next, neighbour.Name)
+			}
+		}
+		current = next
+	}
+	return result
+}
+
+//TopologicalOrder - returns the applications ordered so that every application comes after the applications it depends on.
+// Returns an error listing the involved applications if the graph contains cycles
+func (g *Graph) TopologicalOrder() ([]*Application, error) {
+	remaining := make(map[string]int)
+	for name := range g.byName {
+		remaining[name] = len(g.Dependencies(name))
+	}
+	var result []*Application
+	var ready []*Application
+	for _, app := range g.applications {
+		if g.byName[app.Name] == app && remaining[app.Name] == 0 {
+			ready = append(ready, app)
+		}
+	}
+	for len(ready) > 0 {
+		app := ready[0]
+		ready = ready[1:]
+		result = append(result, app)
+		for _, dependent := range g.Dependents(app.Name) {
+			remaining[dependent.Name]--
+			if remaining[dependent.Name] == 0 {
+				ready = append(ready, dependent)
+			}
+		}
+	}
+	if len(result) < len(g.byName) {
+		var cyclic []string
+		for _, app := range g.applications {
+			if remaining[app.Name] > 0 && !stringInSlice(app.Name, cyclic) {
+				cyclic = append(cyclic, app.Name)
+			}
+		}
+		return result, errors.New(fmt.Sprintf("No topological order - the applications %v are part of or depend on a cycle", strings.Join(cyclic, ", ")))
+	}
+	return result, nil
+}
+
+//DependenciesGrouped - returns the dependencies of the application grouped by the referenced application
+func (g *Graph) DependenciesGrouped(application *Application) []*DependenciesGrouped {
+	var result []*DependenciesGrouped
+	byTarget := make(map[string]*DependenciesGrouped)
+	for _, edge := range g.outgoing[application.Name] {
+		if edge.To == nil {
+			continue
+		}
+		if groupedDep, found := byTarget[edge.TargetName]; found {
+			groupedDep.Dependencies = append(groupedDep.Dependencies, edge.Dependency)
+			continue
+		}
+		groupedDep := &DependenciesGrouped{
+			Application:       edge.To,
+			SourceApplication: application,
+			Dependencies:      []Dependency{edge.Dependency},
+		}
+		byTarget[edge.TargetName] = groupedDep
+		result = append(result, groupedDep)
+	}
+	return result
+}
+
+//IsPlanned - true if the dependency or one of the involved applications or the declaring service is planned
+func (e *GraphEdge) IsPlanned() bool {
+	if e.Status == STATUS_PLANNED || e.From.Status == STATUS_PLANNED {
+		return true
+	}
+	if e.To != nil && e.To.Status == STATUS_PLANNED {
+		return true
+	}
+	if e.SourceService != "" {
+		if service, err := e.From.FindService(e.SourceService); err == nil && service.Status == STATUS_PLANNED {
+			return true
+		}
+	}
+	return false
+}
+
+//EffectiveRelationship - the declared relationship or "open-host" if none is declared and the referenced application is an open host
+func (e *GraphEdge) EffectiveRelationship() Relationship {
+	if e.Relationship == "" && e.To != nil && e.To.IsOpenHostApp() {
+		return RELATIONSHIP_OPEN_HOST
+	}
+	return e.Relationship
+}
+
+//ClusterRelations - returns the relations between clusters of applications sorted by cluster names. cluster returns the cluster of an application (empty to ignore the application), edges within a cluster and edges rejected by the filter (may be nil) are ignored
+func (g *Graph) ClusterRelations(cluster func(application *Application) string, filter EdgeFilter) []*ClusterRelation {
+	var result []*ClusterRelation
+	byClusters := make(map[[2]string]*ClusterRelation)
+	for _, application := range g.applications {
+		fromCluster := cluster(application)
+		if fromCluster == "" {
+			continue
+		}
+		for _, edge := range g.outgoing[application.Name] {
+			if edge.To == nil || (filter != nil && !filter(edge)) {
+				continue
+			}
+			toCluster := cluster(edge.To)
+			if toCluster == "" || toCluster == fromCluster {
+				continue
+			}
+			relation, found := byClusters[[2]string{fromCluster, toCluster}]
+			if !found {
+				relation = &ClusterRelation{FromCluster: fromCluster, ToCluster: toCluster}
+				byClusters[[2]string{fromCluster, toCluster}] = relation
+				result = append(result, relation)
+			}
+			relation.Edges = append(relation.Edges, edge)
+		}
+	}
+	sort.SliceStable(result, func(i, j int) bool {
+		if result[i].FromCluster != result[j].FromCluster {
+			return result[i].FromCluster < result[j].FromCluster
+		}
+		return result[i].ToCluster < result[j].ToCluster
+	})
+	return result
+}
+
+//TeamClustering - clusters the applications by team (applications without team are skipped)
+func TeamClustering(hidePlanned bool) *Clustering {
+	return &Clustering{
+		Prefix: "team",
+		ClusterOf: func(application *Application) string {
+			return application.Team
+		},
+		HidePlanned: hidePlanned,
+	}
+}
+
+//GroupClustering - clusters the applications by main group (applications without group are in the cluster UNGROUPED)
+func GroupClustering(hidePlanned bool) *Clustering {
+	return &Clustering{
+		Prefix: "group",
+		ClusterOf: func(application *Application) string {
+			if application.GetMainGroup() == "" {
+				return UNGROUPED
+			}
+			return application.GetMainGroup()
+		},
+		HidePlanned: hidePlanned,
+	}
+}
+
+//Clusters - returns the clusters of the applications of the graph sorted by name with their colours
+func (c *Clustering) Clusters(g *Graph) Clusters {
+	var result Clusters
+	byName := make(map[string]*Cluster)
+	for _, application := range g.applications {
+		clusterName := c.ClusterOf(application)
+		if clusterName == "" || (c.HidePlanned && application.Status == STATUS_PLANNED) {
+			continue
+		}
+		cluster, found := byName[clusterName]
+		if !found {
+			cluster = &Cluster{Name: clusterName}
+			byName[clusterName] = cluster
+			result = append(result, cluster)
+		}
+		cluster.Applications = append(cluster.Applications, application)
+	}
+	sort.SliceStable(result, func(i, j int) bool {
+		return result[i].Name < result[j].Name
+	})
+	for i, cluster := range result {
+		cluster.Color = ClusterColors[i%len(ClusterColors)]
+	}
+	return result
+}
+
+//Relations - returns the relations between the clusters (see Graph.ClusterRelations), with HidePlanned without planned dependencies
+func (c *Clustering) Relations(g *Graph) []*ClusterRelation {
+	var filter EdgeFilter
+	if c.HidePlanned {
+		filter = func(edge *GraphEdge) bool {
+			return !edge.IsPlanned()
+		}
+	}
+	return g.ClusterRelations(c.ClusterOf, filter)
+}
+
+//Color - returns the colour of the cluster (empty if unknown)
+func (c Clusters) Color(name string) string {
+	for _, cluster := range c {
+		if cluster.Name == name {
+			return cluster.Color
+		}
+	}
+	return ""
+}
+
+//StrongestRelationship - the relationship of the edges with the highest collaboration level (see Relationship.Weight)
+func (r *ClusterRelation) StrongestRelationship() Relationship {
+	var strongest Relationship
+	for i, edge := range r.Edges {
+		relationship := edge.EffectiveRelationship()
+		if i == 0 || strongest.Weight() < relationship.Weight() {
+			strongest = relationship
+		}
+	}
+	return strongest
+}
+
+//Reference - returns the reference in the format used in the definitions (Applicationname.Servicename)
+func (e *GraphEdge) Reference() string {
+	if e.TargetService != "" {
+		return e.TargetName + "." + e.TargetService
+	}
+	return e.TargetName
+}
+
+func (g *Graph) neighbours(applicationName string, direction GraphDirection, filter EdgeFilter) []*Application {
+	edges := g.outgoing[applicationName]
+	if direction == UPSTREAM {
+		edges = g.incoming[applicationName]
+	}
+	var result []*Application
+	seen := make(map[string]bool)
+	for _, edge := range edges {
+		if edge.To == nil || (filter != nil && !filter(edge)) {
+			continue
+		}
+		neighbour := edge.To
+		if direction == UPSTREAM {
+			neighbour = edge.From
+		}
+		if seen[neighbour.Name] {
+			continue
+		}
+		seen[neighbour.Name] = true
+		result = append(result, neighbour)
+	}
+	return result
+}
+
+func stringInSlice(search string, in []string) bool {
+	for _, v := range in {
+		if v == search {
+			return true
+		}
+	}
+	return false
+}
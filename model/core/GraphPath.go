@@ -0,0 +1,143 @@
+package core
+
+import (
+	"sort"
+	"strings"
+)
+
+type (
+	//GraphPath - a path through the dependency graph from one application to another
+	GraphPath struct {
+		Hops []*PathHop
+	}
+
+	//PathHop - one hop of a path with all dependencies that lead from one application to the next one
+	PathHop struct {
+		From  *Application
+		To    *Application
+		Edges []*GraphEdge
+	}
+)
+
+//ShortestPath - returns a path with the fewest hops from one application to another (breadth first search) or nil if the application is not reachable.
+// Only edges accepted by the filter (may be nil) are used
+func (g *Graph) ShortestPath(fromApplicationName string, toApplicationName string, filter EdgeFilter) *GraphPath {
+	if _, found := g.byName[fromApplicationName]; !found || fromApplicationName == toApplicationName {
+		return nil
+	}
+	predecessors := map[string]string{fromApplicationName: ""}
+	current := []string{fromApplicationName}
+	for len(current) > 0 {
+		var next []string
+		for _, name := range current {
+			for _, neighbour := range g.neighbours(name, DOWNSTREAM, filter) {
+				if _, visited := predecessors[neighbour.Name]; visited {
+					continue
+				}
+				predecessors[neighbour.Name] = name
+				if neighbour.Name == toApplicationName {
+					return g.pathOf(predecessorChain(predecessors, toApplicationName), filter)
+				}
+				next = append(next, neighbour.Name)
+			}
+		}
+		current = next
+	}
+	return nil
+}
+
+//AllPaths - returns all simple paths (no application is visited twice) with at most maxLength hops from one application to another, shortest first.
+// maxLength <= 0 means unlimited. Only edges accepted by the filter (may be nil) are used
+func (g *Graph) AllPaths(fromApplicationName string, toApplicationName string, maxLength int, filter EdgeFilter) []*GraphPath {
+	var paths []*GraphPath
+	if _, found := g.byName[fromApplicationName]; !found || fromApplicationName == toApplicationName {
+		return paths
+	}
+	visited := map[string]bool{fromApplicationName: true}
+	var walk func(names []string)
+	walk = func(names []string) {
+		last := names[len(names)-1]
+		if last == toApplicationName {
+			paths = append(paths, g.pathOf(names, filter))
+			return
+		}
+		if maxLength > 0 && len(names) > maxLength {
+			return
+		}
+		for _, neighbour := range g.neighbours(last, DOWNSTREAM, filter) {
+			if visited[neighbour.Name] {
+				continue
+			}
+			visited[neighbour.Name] = true
+			walk(append(append([]string(nil), names...), neighbour.Name))
+			visited[neighbour.Name] = false
+		}
+	}
+	walk([]string{fromApplicationName})
+	sort.SliceStable(paths, func(i, j int) bool {
+		return len(paths[i].Hops) < len(paths[j].Hops)
+	})
+	return paths
+}
+
+//Applications - returns the applications of the path in order (including start and end)
+func (p *GraphPath) Applications() []*Application {
+	if len(p.Hops) == 0 {
+		return nil
+	}
+	applications := []*Application{p.Hops[0].From}
+	for _, hop := range p.Hops {
+		applications = append(applications, hop.To)
+	}
+	return applications
+}
+
+//String - returns the path like "app1 -> app2.api (acl) -> app3", alternative dependencies of a hop are separated by "|"
+func (p *GraphPath) String() string {
+	if len(p.Hops) == 0 {
+		return ""
+	}
+	parts := []string{p.Hops[0].From.Name}
+	for _, hop := range p.Hops {
+		parts = append(parts, hop.String())
+	}
+	return strings.Join(parts, " -> ")
+}
+
+//String - the used dependencies of the hop like "app2.api (acl)|app2"
+func (h *PathHop) String() string {
+	var references []string
+	for _, edge := range h.Edges {
+		reference := edge.Reference()
+		if relationship := edge.EffectiveRelationship(); relationship != "" {
+			reference += " (" + string(relationship) + ")"
+		}
+		if !stringInSlice(reference, references) {
+			references = append(references, reference)
+		}
+	}
+	return strings.Join(references, "|")
+}
+
+// pathOf - the path along the applications with all accepted edges of every hop
+func (g *Graph) pathOf(names []string, filter EdgeFilter) *GraphPath {
+	path := &GraphPath{}
+	for i := 1; i < len(names); i++ {
+		hop := &PathHop{From: g.byName[names[i-1]], To: g.byName[names[i]]}
+		for _, edge := range g.EdgesBetween(names[i-1], names[i]) {
+			if filter == nil || filter(edge) {
+				hop.Edges = append(hop.Edges, edge)
+			}
+		}
+		path.Hops = append(path.Hops, hop)
+	}
+	return path
+}
+
+func predecessorChain(predecessors map[string]string, last string) []string {
+	var names []string
+	for name := last; name != ""; name = predecessors[name] {
+		names = append([]string{name}, names...)
+	}
+	return names
+}
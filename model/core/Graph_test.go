@@ -0,0 +1,99 @@
+package core
+
+import (
+	"testing"
+)
+
+func TestGraph_TransitiveDependents(t *testing.T) {
+
+	project := Project{
+		Name: "Project1",
+		Applications: []*Application{
+			{
+				Name: "app1",
+				Dependencies: []Dependency{
+					{Reference: "app2"},
+					{Reference: "app2.api"},
+				},
+			},
+			{
+				Name: "app2",
+				ProvidedServices: []Service{
+					{
+						Name: "api",
+						Dependencies: []Dependency{
+							{Reference: "app3", Relationship: "acl"},
+						},
+					},
+				},
+			},
+			{
+				Name: "app3",
+				Dependencies: []Dependency{
+					{Reference: "app1"},
+					{Reference: "missing"},
+				},
+			},
+		},
+	}
+	graph := CreateGraph(&project)
+
+	if edges := graph.EdgesBetween("app2", "app3"); len(edges) != 1 || edges[0].SourceService != "api" || edges[0].Relationship != "acl" {
+		t.Error("Expected one acl edge from service app2.api to app3", edges)
+	}
+	if dependents := graph.Dependents("app2"); len(dependents) != 1 || dependents[0].Name != "app1" {
+		t.Error("Expected app1 as the only dependent of app2", dependents)
+	}
+	if missing := graph.MissingReferences("app3"); len(missing) != 1 || missing[0] != "missing" {
+		t.Error("Expected missing reference", missing)
+	}
+
+	reached := graph.TransitiveDependents("app3")
+	if len(reached) != 2 {
+		t.Fatal("Expected the cycle to be walked once without duplicates", reached)
+	}
+	if reached[0].Application.Name != "app2" || reached[0].Depth != 1 || reached[1].Application.Name != "app1" || reached[1].Depth != 2 {
+		t.Error("Unexpected transitive dependents", reached[0], reached[1])
+	}
+
+	if _, err := graph.TopologicalOrder(); err == nil {
+		t.Error("Expected an error for the topological order of a cyclic graph")
+	}
+	project.Applications[2].Dependencies = nil
+	order, err := CreateGraph(&project).TopologicalOrder()
+	if err != nil {
+		t.Fatal(err)
+	}
+	if order[0].Name != "app3" || order[2].Name != "app1" {
+		t.Error("Expected dependencies first in the topological order", order)
+	}
+}
+
+func TestClustering_Clusters(t *testing.T) {
+	project := Project{
+		Applications: []*Application{
+			{Name: "shop", Group: "frontend/web", Team: "team2", Dependencies: []Dependency{{Reference: "orders"}, {Reference: "search", Status: STATUS_PLANNED}}},
+			{Name: "orders", Group: "backend", Team: "team1"},
+			{Name: "search", Team: "team1", Status: STATUS_PLANNED},
+			{Name: "legacy"},
+		},
+	}
+	graph := CreateGraph(&project)
+
+	clusters := GroupClustering(false).Clusters(graph)
+	if len(clusters) != 3 || clusters[0].Name != "UNGROUPED" || clusters[1].Name != "backend" || clusters[2].Name != "frontend" {
+		t.Fatal("Expected the main groups sorted by name", clusters)
+	}
+	if len(clusters[0].Applications) != 2 || clusters[0].Color != ClusterColors[0] || clusters.Color("frontend") != ClusterColors[2] {
+		t.Error("Expected the ungrouped applications and colours in the order of the names", clusters[0])
+	}
+
+	teams := TeamClustering(true)
+	clusters = teams.Clusters(graph)
+	if len(clusters) != 2 || len(clusters[0].Applications) != 1 || clusters[0].Applications[0].Name != "orders" {
+		t.Error("Expected applications without team and planned applications to be skipped", clusters)
+	}
+	if relations := teams.Relations(graph); len(relations) != 1 || relations[0].FromCluster != "team2" || len(relations[0].Edges) != 1 {
+		t.Error("Expected the planned dependency to be skipped", relations)
+	}
+}
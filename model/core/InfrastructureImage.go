@@ -0,0 +1,56 @@
+package core
+
+import "strings"
+
+//infrastructureImages - the infrastructure type by container image name (without registry, path and tag)
+var infrastructureImages = map[string]string{
+	"postgres":      "postgres",
+	"postgresql":    "postgres",
+	"mysql":         "mysql",
+	"mariadb":       "mariadb",
+	"redis":         "redis",
+	"rabbitmq":      "rabbitmq",
+	"mongo":         "mongodb",
+	"mongodb":       "mongodb",
+	"elasticsearch": "elasticsearch",
+	"opensearch":    "opensearch",
+	"memcached":     "memcached",
+	"kafka":         "kafka",
+	"zookeeper":     "zookeeper",
+	"minio":         "minio",
+}
+
+//InfrastructureTypeOfImage - returns the infrastructure type of well known container images (e.g. "postgres" for "docker.io/bitnami/postgresql:15.1") or an empty string
+func InfrastructureTypeOfImage(image string) string {
+	name := image
+	if i := strings.LastIndex(name, "/"); i >= 0 {
+		name = name[i+1:]
+	}
+	if i := strings.IndexAny(name, ":@"); i >= 0 {
+		name = name[:i]
+	}
+	return infrastructureImages[strings.ToLower(name)]
+}
+
+//AddProvidedService - adds the service unless the application already provides a service with the same name (used by the importers)
+func (a *Application) AddProvidedService(service Service) {
+	for _, existing := range a.ProvidedServices {
+		if existing.Name == service.Name {
+			return
+		}
+	}
+	a.ProvidedServices = append(a.ProvidedServices, service)
+}
+
+//AddInfrastructureDependency - adds a dependency to the infrastructure type unless it is empty or already added (used by the importers)
+func (a *Application) AddInfrastructureDependency(infrastructureType string) {
+	if infrastructureType == "" {
+		return
+	}
+	for _, existing := range a.InfrastructureDependencies {
+		if existing.Type == infrastructureType {
+			return
+		}
+	}
+	a.InfrastructureDependencies = append(a.InfrastructureDependencies, InfrastructureDependency{Type: infrastructureType})
+}
@@ -0,0 +1,34 @@
+package core
+
+import (
+	"testing"
+)
+
+func TestInfrastructureTypeOfImage(t *testing.T) {
+	for image, expected := range map[string]string{
+		"postgres":                             "postgres",
+		"docker.io/bitnami/postgresql:15.1.0":  "postgres",
+		"redis:7-alpine":                       "redis",
+		"rabbitmq@sha256:abc":                  "rabbitmq",
+		"registry.example.com/shop/orders:1.4": "",
+	} {
+		if actual := InfrastructureTypeOfImage(image); actual != expected {
+			t.Errorf("Expected %q for %v, got %q", expected, image, actual)
+		}
+	}
+}
+
+func TestApplication_AddInfrastructureDependency(t *testing.T) {
+	application := &Application{Name: "orders"}
+	application.AddInfrastructureDependency("postgres")
+	application.AddInfrastructureDependency("postgres")
+	application.AddInfrastructureDependency("")
+	if len(application.InfrastructureDependencies) != 1 {
+		t.Errorf("Expected one infrastructure dependency, got %v", application.InfrastructureDependencies)
+	}
+	application.AddProvidedService(Service{Name: "api", Type: SERVICE_TYPE_API})
+	application.AddProvidedService(Service{Name: "api", Type: SERVICE_TYPE_GUI})
+	if len(application.ProvidedServices) != 1 || application.ProvidedServices[0].Type != SERVICE_TYPE_API {
+		t.Errorf("Expected the first api service only, got %v", application.ProvidedServices)
+	}
+}
@@ -4,12 +4,18 @@ import (
 	"errors"
 	"fmt"
 	"strings"
+	"sync"
 )
 
 type (
 	Project struct {
 		Name         string         `json:"name" yaml:"name"`
 		Applications []*Application `json:"applications" yaml:"applications"`
+		//Vocabulary - the allowed relationships, statuses, service types and categories (DefaultVocabulary if nil)
+		Vocabulary *Vocabulary `json:"vocabulary,omitempty" yaml:"vocabulary,omitempty"`
+		// graph - the dependency graph built on first use by Graph()
+		graph      *Graph
+		graphMutex sync.Mutex
 	}
 
 	ApplicationsByGroup struct {
@@ -32,26 +38,50 @@ func (p *Project) Validate() []error {
 
 	var foundErrors []error
 
+	vocabulary := p.Vocabulary
+	if vocabulary == nil {
+		vocabulary = DefaultVocabulary()
+	}
 	for _, application := range p.Applications {
 		foundErrors = append(foundErrors, application.Validate()...)
-		dependencies := application.GetAllDependencies()
-
-		for _, dependency := range dependencies {
-			dependendComponentName, serviceName := dependency.GetApplicationAndServiceNames()
-			if dependency.IsOptional {
-				continue
+		foundErrors = append(foundErrors, application.ValidateVocabulary(vocabulary)...)
+		for _, dependency := range application.Dependencies {
+			if err := p.validateDependency(application, "", dependency); err != nil {
+				foundErrors = append(foundErrors, err)
 			}
-
-			error := p.doesServiceExists(dependendComponentName, serviceName)
-			if error != nil {
-				foundErrors = append(foundErrors, errors.New(fmt.Sprintf("Application '%v' Dependencies has Error: %v ( Add this application or mark the dependency as 'isOptional')", application.Name, error)))
+		}
+		for _, service := range application.ProvidedServices {
+			for _, dependency := range service.Dependencies {
+				if err := p.validateDependency(application, service.Name, dependency); err != nil {
+					foundErrors = append(foundErrors, err)
+				}
 			}
 		}
 	}
 	return foundErrors
 }
 
+// internal method - returns an error if the (non optional) dependency references an application or service that does not exist
+func (p *Project) validateDependency(application *Application, serviceName string, dependency Dependency) error {
+	if dependency.IsOptional {
+		return nil
+	}
+	dependendComponentName, dependendServiceName := dependency.GetApplicationAndServiceNames()
+	error := p.doesServiceExists(dependendComponentName, dependendServiceName)
+	if error == nil {
+		return nil
+	}
+	validationError := application.newValidationError(CHECK_MISSING_DEPENDENCY, fmt.Sprintf("Application '%v' Dependencies has Error: %v ( Add this application or mark the dependency as 'isOptional')", application.Name, error))
+	validationError.Service = serviceName
+	validationError.Dependency = dependency.Reference
+	if dependency.Source.IsKnown() {
+		validationError.Source = dependency.Source
+	}
+	return validationError
+}
+
 func (p *Project) GenerateApplicationIds() {
+	p.ResetGraph()
 	i := 1
 	for _, app := range p.Applications {
 		if app.Id != 0 {
@@ -75,6 +105,23 @@ func (p *Project) FindApplication(nameToMatch string) (*Application, error) {
 	return nil, errors.New("Application with name '" + nameToMatch + "' not found")
 }
 
+//Graph - returns the dependency graph of the project. It is built once and reused - call ResetGraph after changing the applications
+func (p *Project) Graph() *Graph {
+	p.graphMutex.Lock()
+	defer p.graphMutex.Unlock()
+	if p.graph == nil {
+		p.graph = CreateGraph(p)
+	}
+	return p.graph
+}
+
+//ResetGraph - drops the cached dependency graph (GenerateApplicationIds does this after loading)
+func (p *Project) ResetGraph() {
+	p.graphMutex.Lock()
+	defer p.graphMutex.Unlock()
+	p.graph = nil
+}
+
 // GetApplicationsRootGroup - Returns the Root Group
 func (p *Project) GetApplicationsRootGroup() *ApplicationsByGroup {
 	appsByGroup := ApplicationsByGroup{
@@ -113,48 +160,18 @@ func (p *Project) FindApplicationThatReferenceTo(application *Application, recur
 
 }
 
+//FindAllApplicationsThatReferenceApplication - returns all applications that directly or indirectly depend on the given application (each application once)
 func (p *Project) FindAllApplicationsThatReferenceApplication(referencedApplication *Application) []*Application {
 	var referencingApps []*Application
-	for _, currentComponent := range p.FindApplicationsThatReferenceApplication(referencedApplication) {
-		referencingApps = append(referencingApps, currentComponent)
-		recursiveReferencingComponents := p.FindAllApplicationsThatReferenceApplication(currentComponent)
-		for _, currentComponent := range recursiveReferencingComponents {
-			referencingApps = append(referencingApps, currentComponent)
-			if sliceContains(referencingApps, currentComponent) {
-				continue
-			}
-		}
+	for _, reached := range p.Graph().TransitiveDependents(referencedApplication.Name) {
+		referencingApps = append(referencingApps, reached.Application)
 	}
 	return referencingApps
 }
 
 // returns all components that have a direct dependency to the given component
 func (p *Project) FindApplicationsThatReferenceApplication(referencedApplication *Application) []*Application {
-	var referencingApps []*Application
-	//walk through all registered compoents and return those who match
-	for _, currentComponent := range p.Applications {
-		currentComponentsDependencies := currentComponent.GetAllDependencies()
-		for _, dependency := range currentComponentsDependencies {
-			if dependency.GetApplicationName() != referencedApplication.Name {
-				continue
-			}
-			if sliceContains(referencingApps, currentComponent) {
-				continue
-			}
-			referencingApps = append(referencingApps, currentComponent)
-		}
-	}
-	return referencingApps
-}
-
-//Helper to check if a slice of components already contains a certain Component
-func sliceContains(searchInList []*Application, searchFor *Application) bool {
-	for _, s := range searchInList {
-		if s == searchFor {
-			return true
-		}
-	}
-	return false
+	return p.Graph().Dependents(referencedApplication.Name)
 }
 
 // internal method - Checks if a service exists and returns error if not
@@ -7,8 +7,8 @@ import (
 func TestProject_FindAllApplicationsThatReferenceApplication(t *testing.T) {
 
 	project := Project{
-		"Project1",
-		[]*Application{
+		Name: "Project1",
+		Applications: []*Application{
 			{
 				Name: "app1",
 
@@ -41,3 +41,52 @@ func contains(searchIn []*Application, findApp *Application) bool {
 	}
 	return false
 }
+
+func TestProject_ValidateVocabulary(t *testing.T) {
+
+	project := Project{
+		Name: "Project1",
+		Applications: []*Application{
+			{
+				Name:   "app1",
+				Status: "planed",
+				ProvidedServices: []Service{
+					{Name: "api", Type: "s3", SecurityLevel: "secret", Dependencies: []Dependency{{Reference: "app2", Relationship: RELATIONSHIP_ACL}}},
+				},
+				Dependencies: []Dependency{
+					{Reference: "app2", Relationship: "customer-suplier"},
+				},
+			},
+			{
+				Name:     "app2",
+				Category: CATEGORY_EXTERNAL,
+			},
+		},
+	}
+
+	if errs := project.Validate(); len(errs) != 4 {
+		t.Fatalf("Expected unknown status, relationship, service type and security level got %v", errs)
+	}
+	if err := project.Validate()[0].(*ValidationError); err.CheckId != CHECK_UNKNOWN_VALUE || err.Application != "app1" {
+		t.Error("Expected unknown value error for app1", err)
+	}
+
+	project.Vocabulary = DefaultVocabulary().Extend(&Vocabulary{Statuses: []string{"planed"}, ServiceTypes: []string{"s3"}, Relationships: []string{"customer-suplier"}, SecurityLevels: []string{"secret"}})
+	if errs := project.Validate(); len(errs) != 0 {
+		t.Errorf("Expected registered values to be valid got %v", errs)
+	}
+}
+
+func TestProject_Graph(t *testing.T) {
+	project := Project{Applications: []*Application{{Name: "app1", Dependencies: []Dependency{{Reference: "app2"}}}, {Name: "app2"}}}
+
+	graph := project.Graph()
+	if project.Graph() != graph {
+		t.Error("Expected the graph to be built once")
+	}
+	project.Applications = append(project.Applications, &Application{Name: "app3", Dependencies: []Dependency{{Reference: "app2"}}})
+	project.GenerateApplicationIds()
+	if project.Graph() == graph || len(project.FindApplicationsThatReferenceApplication(project.Applications[1])) != 2 {
+		t.Error("Expected GenerateApplicationIds to reset the graph")
+	}
+}
@@ -0,0 +1,159 @@
+package core
+
+import (
+	"path"
+	"strings"
+)
+
+type (
+	//ApplicationSelector - declarative matcher for applications. Every set attribute needs to match, unset attributes match everything
+	ApplicationSelector struct {
+		//Name - glob pattern (e.g. "order-*")
+		Name string `json:"name,omitempty" yaml:"name,omitempty"`
+		Team string `json:"team,omitempty" yaml:"team,omitempty"`
+		//Group - matches the group and all its subgroups (e.g. "backend" matches "backend/db")
+		Group      string `json:"group,omitempty" yaml:"group,omitempty"`
+		Category   string `json:"category,omitempty" yaml:"category,omitempty"`
+		Technology string `json:"technology,omitempty" yaml:"technology,omitempty"`
+		Status     Status `json:"status,omitempty" yaml:"status,omitempty"`
+		//Properties - all properties need to have the given value. Use "*" to only require the property to be set
+		Properties map[string]string `json:"properties,omitempty" yaml:"properties,omitempty"`
+		//HasPublicService - matches applications that provide (or do not provide) at least one public service
+		HasPublicService *bool `json:"hasPublicService,omitempty" yaml:"hasPublicService,omitempty"`
+	}
+
+	//ServiceSelector - declarative matcher for provided services
+	ServiceSelector struct {
+		//Name - glob pattern
+		Name          string            `json:"name,omitempty" yaml:"name,omitempty"`
+		Type          ServiceType       `json:"type,omitempty" yaml:"type,omitempty"`
+		SecurityLevel string            `json:"securityLevel,omitempty" yaml:"securityLevel,omitempty"`
+		Status        Status            `json:"status,omitempty" yaml:"status,omitempty"`
+		IsPublic      *bool             `json:"isPublic,omitempty" yaml:"isPublic,omitempty"`
+		IsOpenHost    *bool             `json:"isOpenHost,omitempty" yaml:"isOpenHost,omitempty"`
+		Properties    map[string]string `json:"properties,omitempty" yaml:"properties,omitempty"`
+	}
+
+	//DependencySelector - declarative matcher for dependencies
+	DependencySelector struct {
+		Relationship   Relationship      `json:"relationship,omitempty" yaml:"relationship,omitempty"`
+		Status         Status            `json:"status,omitempty" yaml:"status,omitempty"`
+		IsOptional     *bool             `json:"isOptional,omitempty" yaml:"isOptional,omitempty"`
+		IsBrowserBased *bool             `json:"isBrowserBased,omitempty" yaml:"isBrowserBased,omitempty"`
+		Properties     map[string]string `json:"properties,omitempty" yaml:"properties,omitempty"`
+	}
+)
+
+//Matches - returns true if the application matches the selector. A nil selector matches every application
+func (s *ApplicationSelector) Matches(app *Application) bool {
+	if s == nil {
+		return true
+	}
+	if app == nil {
+		return false
+	}
+	if s.Name != "" && !globMatches(s.Name, app.Name) {
+		return false
+	}
+	if s.Team != "" && s.Team != app.Team {
+		return false
+	}
+	if s.Group != "" && !IsInGroup(app.Group, s.Group) {
+		return false
+	}
+	if s.Category != "" && s.Category != app.Category {
+		return false
+	}
+	if s.Technology != "" && !strings.EqualFold(s.Technology, app.Technology) {
+		return false
+	}
+	if s.Status != "" && s.Status != app.Status {
+		return false
+	}
+	if !propertiesMatch(s.Properties, app.Properties) {
+		return false
+	}
+	if s.HasPublicService != nil && *s.HasPublicService != app.HasPublicService() {
+		return false
+	}
+	return true
+}
+
+//Matches - returns true if the service matches the selector. A nil selector matches every service, a nil service only matches a nil selector
+func (s *ServiceSelector) Matches(service *Service) bool {
+	if s == nil {
+		return true
+	}
+	if service == nil {
+		return false
+	}
+	if s.Name != "" && !globMatches(s.Name, service.Name) {
+		return false
+	}
+	if s.Type != "" && s.Type != service.Type {
+		return false
+	}
+	if s.SecurityLevel != "" && s.SecurityLevel != service.SecurityLevel {
+		return false
+	}
+	if s.Status != "" && s.Status != service.Status {
+		return false
+	}
+	if s.IsPublic != nil && *s.IsPublic != service.IsPublic {
+		return false
+	}
+	if s.IsOpenHost != nil && *s.IsOpenHost != service.IsOpenHost {
+		return false
+	}
+	return propertiesMatch(s.Properties, service.Properties)
+}
+
+//Matches - returns true if the dependency matches the selector. A nil selector matches every dependency
+func (s *DependencySelector) Matches(dependency *Dependency) bool {
+	if s == nil {
+		return true
+	}
+	if dependency == nil {
+		return false
+	}
+	if s.Relationship != "" && s.Relationship != dependency.Relationship {
+		return false
+	}
+	if s.Status != "" && s.Status != dependency.Status {
+		return false
+	}
+	if s.IsOptional != nil && *s.IsOptional != dependency.IsOptional {
+		return false
+	}
+	if s.IsBrowserBased != nil && *s.IsBrowserBased != dependency.IsBrowserBased {
+		return false
+	}
+	return propertiesMatch(s.Properties, dependency.Properties)
+}
+
+//IsInGroup - returns true if the group is the given group or one of its subgroups
+func IsInGroup(group string, parentGroup string) bool {
+	parentGroup = strings.Trim(parentGroup, "/")
+	return group == parentGroup || strings.HasPrefix(group, parentGroup+"/")
+}
+
+func globMatches(pattern string, name string) bool {
+	matched, err := path.Match(pattern, name)
+	if err != nil {
+		return pattern == name
+	}
+	return matched
+}
+
+func propertiesMatch(expected map[string]string, properties map[string]string) bool {
+	for key, expectedValue := range expected {
+		value, found := properties[key]
+		if !found {
+			return false
+		}
+		if expectedValue != "*" && expectedValue != value {
+			return false
+		}
+	}
+	return true
+}
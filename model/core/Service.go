@@ -11,13 +11,22 @@ type Service struct {
 	Title         string            `json:"title" yaml:"title"`
 	Summary       string            `json:"summary" yaml:"summary"`
 	Description   string            `json:"description" yaml:"description"`
-	Type          string            `json:"type,omitempty" yaml:"type,omitempty"`
+	Type          ServiceType       `json:"type,omitempty" yaml:"type,omitempty"`
 	IsPublic      bool              `json:"isPublic,omitempty" yaml:"isPublic,omitempty"`
 	IsOpenHost    bool              `json:"isOpenHost,omitempty" yaml:"isOpenHost,omitempty"`
 	SecurityLevel string            `json:"securityLevel" yaml:"securityLevel"`
 	Dependencies  []Dependency      `json:"dependencies" yaml:"dependencies"`
-	Status        string            `json:"status" yaml:"status"`
+	Status        Status            `json:"status" yaml:"status"`
 	Properties    map[string]string `json:"properties" yaml:"properties"`
+	//Source - where the service is defined
+	Source SourcePosition `json:"source" yaml:"-"`
+}
+
+//Clone - returns a deep copy of the service
+func (s Service) Clone() Service {
+	s.Dependencies = cloneDependencies(s.Dependencies)
+	s.Properties = cloneProperties(s.Properties)
+	return s
 }
 
 func (s *Service) HasPropertyWithValue(property string, compareValue string) bool {
@@ -0,0 +1,26 @@
+package core
+
+import (
+	"fmt"
+)
+
+type (
+	//SourcePosition - the definition file and line an element was loaded from
+	SourcePosition struct {
+		File string `json:"file,omitempty"`
+		Line int    `json:"line,omitempty"`
+	}
+)
+
+//IsKnown - true if at least the file is known
+func (s SourcePosition) IsKnown() bool {
+	return s.File != ""
+}
+
+//String - returns "file:line" (or only the file if the line is unknown)
+func (s SourcePosition) String() string {
+	if s.Line > 0 {
+		return fmt.Sprintf("%v:%d", s.File, s.Line)
+	}
+	return s.File
+}
@@ -0,0 +1,31 @@
+package core
+
+type (
+	//ValidationError - a problem found while loading or validating the definitions, with the location it refers to
+	ValidationError struct {
+		//CheckId - identifies the kind of problem (one of the CHECK_ constants)
+		CheckId     string
+		Application string
+		//Service - the provided service that declares the problematic dependency or is problematic itself
+		Service    string
+		Dependency string
+		//Source - the position in the definition files the problem was found at
+		Source  SourcePosition
+		Message string
+	}
+)
+
+const (
+	CHECK_DEFINITION_FILE       = "definition-file"
+	CHECK_DUPLICATE_APPLICATION = "duplicate-application"
+	CHECK_APPLICATION_NAME      = "application-name"
+	CHECK_MISSING_DEPENDENCY    = "missing-dependency"
+)
+
+//Error - the message prefixed with the "file:line" location if known
+func (e *ValidationError) Error() string {
+	if e.Source.IsKnown() {
+		return e.Source.String() + ": " + e.Message
+	}
+	return e.Message
+}
@@ -0,0 +1,136 @@
+package core
+
+import (
+	"fmt"
+	"strings"
+)
+
+type (
+	//Relationship - the collaboration level between the bounded contexts of two applications
+	Relationship string
+
+	//Status - lifecycle status of applications, services and dependencies (empty for existing ones)
+	Status string
+
+	//ServiceType - the kind of a provided service
+	ServiceType string
+
+	//Vocabulary - the allowed values of relationships, statuses, service types, categories and security levels.
+	//Projects can register additional values in the project config
+	Vocabulary struct {
+		Relationships []string `json:"relationships,omitempty" yaml:"relationships,omitempty"`
+		Statuses      []string `json:"statuses,omitempty" yaml:"statuses,omitempty"`
+		ServiceTypes  []string `json:"serviceTypes,omitempty" yaml:"serviceTypes,omitempty"`
+		Categories    []string `json:"categories,omitempty" yaml:"categories,omitempty"`
+		//SecurityLevels - the classifications of provided services
+		SecurityLevels []string `json:"securityLevels,omitempty" yaml:"securityLevels,omitempty"`
+	}
+)
+
+const (
+	RELATIONSHIP_ACL               Relationship = "acl"
+	RELATIONSHIP_CUSTOMER_SUPPLIER Relationship = "customer-supplier"
+	RELATIONSHIP_CONFORMIST        Relationship = "conformist"
+	RELATIONSHIP_PARTNERSHIP       Relationship = "partnership"
+	RELATIONSHIP_OPEN_HOST         Relationship = "open-host"
+
+	STATUS_PLANNED Status = "planned"
+
+	SERVICE_TYPE_API      ServiceType = "api"
+	SERVICE_TYPE_GUI      ServiceType = "gui"
+	SERVICE_TYPE_EXCHANGE ServiceType = "exchange"
+	SERVICE_TYPE_TOPIC    ServiceType = "topic"
+
+	CATEGORY_EXTERNAL = "external"
+
+	SECURITY_LEVEL_PUBLIC       = "public"
+	SECURITY_LEVEL_INTERNAL     = "internal"
+	SECURITY_LEVEL_CONFIDENTIAL = "confidential"
+	SECURITY_LEVEL_RESTRICTED   = "restricted"
+
+	CHECK_UNKNOWN_VALUE = "unknown-value"
+)
+
+//Weight - the collaboration level of the relationship: acl (0) < unknown (1) < customer-supplier (2) < conformist (3) < partnership (4)
+func (r Relationship) Weight() int {
+	switch r {
+	case RELATIONSHIP_ACL:
+		return 0
+	case RELATIONSHIP_CUSTOMER_SUPPLIER:
+		return 2
+	case RELATIONSHIP_CONFORMIST:
+		return 3
+	case RELATIONSHIP_PARTNERSHIP:
+		return 4
+	}
+	return 1
+}
+
+//DefaultVocabulary - the values the drawers know
+func DefaultVocabulary() *Vocabulary {
+	return &Vocabulary{
+		Relationships:  []string{string(RELATIONSHIP_ACL), string(RELATIONSHIP_CUSTOMER_SUPPLIER), string(RELATIONSHIP_CONFORMIST), string(RELATIONSHIP_PARTNERSHIP), string(RELATIONSHIP_OPEN_HOST)},
+		Statuses:       []string{string(STATUS_PLANNED)},
+		ServiceTypes:   []string{string(SERVICE_TYPE_API), string(SERVICE_TYPE_GUI), string(SERVICE_TYPE_EXCHANGE), string(SERVICE_TYPE_TOPIC)},
+		Categories:     []string{CATEGORY_EXTERNAL},
+		SecurityLevels: []string{SECURITY_LEVEL_PUBLIC, SECURITY_LEVEL_INTERNAL, SECURITY_LEVEL_CONFIDENTIAL, SECURITY_LEVEL_RESTRICTED},
+	}
+}
+
+//Extend - returns a new vocabulary with the values of both vocabularies (extra may be nil)
+func (v *Vocabulary) Extend(extra *Vocabulary) *Vocabulary {
+	extended := &Vocabulary{
+		Relationships:  appendMissing(nil, v.Relationships),
+		Statuses:       appendMissing(nil, v.Statuses),
+		ServiceTypes:   appendMissing(nil, v.ServiceTypes),
+		Categories:     appendMissing(nil, v.Categories),
+		SecurityLevels: appendMissing(nil, v.SecurityLevels),
+	}
+	if extra != nil {
+		extended.Relationships = appendMissing(extended.Relationships, extra.Relationships)
+		extended.Statuses = appendMissing(extended.Statuses, extra.Statuses)
+		extended.ServiceTypes = appendMissing(extended.ServiceTypes, extra.ServiceTypes)
+		extended.Categories = appendMissing(extended.Categories, extra.Categories)
+		extended.SecurityLevels = appendMissing(extended.SecurityLevels, extra.SecurityLevels)
+	}
+	return extended
+}
+
+//IsKnownRelationship - true if the relationship is empty or part of the vocabulary
+func (v *Vocabulary) IsKnownRelationship(relationship Relationship) bool {
+	return relationship == "" || stringInSlice(string(relationship), v.Relationships)
+}
+
+//IsKnownStatus - true if the status is empty or part of the vocabulary
+func (v *Vocabulary) IsKnownStatus(status Status) bool {
+	return status == "" || stringInSlice(string(status), v.Statuses)
+}
+
+//IsKnownServiceType - true if the service type is empty or part of the vocabulary
+func (v *Vocabulary) IsKnownServiceType(serviceType ServiceType) bool {
+	return serviceType == "" || stringInSlice(string(serviceType), v.ServiceTypes)
+}
+
+//IsKnownCategory - true if the category is empty or part of the vocabulary
+func (v *Vocabulary) IsKnownCategory(category string) bool {
+	return category == "" || stringInSlice(category, v.Categories)
+}
+
+//IsKnownSecurityLevel - true if the security level is empty or part of the vocabulary
+func (v *Vocabulary) IsKnownSecurityLevel(securityLevel string) bool {
+	return securityLevel == "" || stringInSlice(securityLevel, v.SecurityLevels)
+}
+
+// unknownValueMessage - message for values that are not part of the vocabulary
+func unknownValueMessage(kind string, value string, element string, allowed []string, key string) string {
+	return fmt.Sprintf("Unknown %v '%v' %v - use one of %v or register it in the vocabulary of the project config (vocabulary.%v)", kind, value, element, strings.Join(allowed, ", "), key)
+}
+
+func appendMissing(values []string, additional []string) []string {
+	for _, value := range additional {
+		if !stringInSlice(value, values) {
+			values = append(values, value)
+		}
+	}
+	return values
+}
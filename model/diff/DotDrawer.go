@@ -0,0 +1,119 @@
+package diff
+
+import (
+	"fmt"
+	"strings"
+
+	"github.com/AOEpeople/vistecture/v2/model/core"
+)
+
+type (
+	//DotDrawer - draws the application dependencies of the base and head project in one graphviz graph. Added elements are green, removed ones red and dashed, changed ones orange
+	DotDrawer struct {
+		diff *ProjectDiff
+	}
+
+	// dotEdge - a dependency between two applications (head names)
+	dotEdge struct {
+		from, to string
+	}
+
+	// dotEdges - distinct edges in order of their first occurrence
+	dotEdges struct {
+		order    []dotEdge
+		contains map[dotEdge]bool
+	}
+)
+
+const (
+	COLOR_ADDED     = "#2e7d32"
+	COLOR_REMOVED   = "#c62828"
+	COLOR_CHANGED   = "#ef6c00"
+	COLOR_UNCHANGED = "#9e9e9e"
+)
+
+//CreateDotDrawer - Factory
+func CreateDotDrawer(diff *ProjectDiff) *DotDrawer {
+	return &DotDrawer{diff: diff}
+}
+
+//Draw - returns the graph in DOT format
+func (d *DotDrawer) Draw() string {
+	var b strings.Builder
+	b.WriteString("digraph architecturediff {\n")
+	b.WriteString("  rankdir=LR;\n")
+	b.WriteString("  node [shape=box, style=\"rounded,filled\", fillcolor=white, fontname=Helvetica];\n")
+	b.WriteString("  edge [fontname=Helvetica, fontsize=10];\n")
+
+	changedApplications := make(map[string]bool)
+	for _, change := range d.diff.Changes {
+		if change.Application != "" {
+			changedApplications[change.Application] = true
+		}
+	}
+	for _, application := range d.diff.head.Applications() {
+		baseApplication, found := d.diff.baseApplication(application.Name)
+		switch {
+		case !found:
+			b.WriteString(fmt.Sprintf("  %q [color=%q, fillcolor=\"#e8f5e9\", penwidth=2];\n", application.Name, COLOR_ADDED))
+		case baseApplication.Name != application.Name:
+			b.WriteString(fmt.Sprintf("  %q [color=%q, fillcolor=\"#fff3e0\", penwidth=2, label=%q];\n", application.Name, COLOR_CHANGED, application.Name+"\n(was "+baseApplication.Name+")"))
+		case changedApplications[application.Name]:
+			b.WriteString(fmt.Sprintf("  %q [color=%q, fillcolor=\"#fff3e0\", penwidth=2];\n", application.Name, COLOR_CHANGED))
+		default:
+			b.WriteString(fmt.Sprintf("  %q [color=%q];\n", application.Name, COLOR_UNCHANGED))
+		}
+	}
+	for _, application := range d.diff.base.Applications() {
+		if d.isRemoved(application.Name) {
+			b.WriteString(fmt.Sprintf("  %q [color=%q, fillcolor=\"#ffebee\", style=\"rounded,filled,dashed\"];\n", application.Name, COLOR_REMOVED))
+		}
+	}
+
+	baseEdges := d.edges(d.diff.base, d.diff.headName)
+	headEdges := d.edges(d.diff.head, func(name string) string { return name })
+	for _, edge := range headEdges.order {
+		switch {
+		case !baseEdges.contains[edge]:
+			b.WriteString(fmt.Sprintf("  %q -> %q [color=%q, penwidth=2, label=\"added\", fontcolor=%q];\n", edge.from, edge.to, COLOR_ADDED, COLOR_ADDED))
+		case d.diff.changedEdges[[2]string{edge.from, edge.to}]:
+			b.WriteString(fmt.Sprintf("  %q -> %q [color=%q, penwidth=2, label=\"changed\", fontcolor=%q];\n", edge.from, edge.to, COLOR_CHANGED, COLOR_CHANGED))
+		default:
+			b.WriteString(fmt.Sprintf("  %q -> %q [color=%q];\n", edge.from, edge.to, COLOR_UNCHANGED))
+		}
+	}
+	for _, edge := range baseEdges.order {
+		if !headEdges.contains[edge] {
+			b.WriteString(fmt.Sprintf("  %q -> %q [color=%q, style=dashed, label=\"removed\", fontcolor=%q];\n", edge.from, edge.to, COLOR_REMOVED, COLOR_REMOVED))
+		}
+	}
+	b.WriteString("}\n")
+	return b.String()
+}
+
+// isRemoved - true if the base application is neither part of the head project nor renamed
+func (d *DotDrawer) isRemoved(baseName string) bool {
+	if _, isRenamed := d.diff.renamed[baseName]; isRenamed {
+		return false
+	}
+	_, found := d.diff.head.Application(baseName)
+	return !found
+}
+
+// edges - the distinct dependencies between applications of the graph. Removed applications keep their name, all others are named like in the head project
+func (d *DotDrawer) edges(graph *core.Graph, name func(string) string) *dotEdges {
+	result := &dotEdges{contains: make(map[dotEdge]bool)}
+	for _, application := range graph.Applications() {
+		for _, edge := range graph.OutgoingEdges(application.Name) {
+			if edge.To == nil {
+				continue
+			}
+			e := dotEdge{from: name(application.Name), to: name(edge.TargetName)}
+			if !result.contains[e] {
+				result.contains[e] = true
+				result.order = append(result.order, e)
+			}
+		}
+	}
+	return result
+}
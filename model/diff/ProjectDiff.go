@@ -0,0 +1,393 @@
+package diff
+
+import (
+	"fmt"
+	"sort"
+	"strings"
+
+	"github.com/AOEpeople/vistecture/v2/model/analyze"
+	"github.com/AOEpeople/vistecture/v2/model/core"
+)
+
+type (
+	//ProjectDiff - the semantic changes between two versions (base and head) of a project
+	ProjectDiff struct {
+		Changes []*Change `json:"changes"`
+		base    *core.Graph
+		head    *core.Graph
+		//renamed - the head name of renamed applications by base name
+		renamed map[string]string
+		//changedEdges - the applications (head names) between which dependencies are added, removed or changed
+		changedEdges map[[2]string]bool
+	}
+
+	//Change - one added, removed, renamed or changed element
+	Change struct {
+		Kind    string `json:"kind"`
+		Element string `json:"element"`
+		//Application - the (head) name of the application the element belongs to - empty for team couplings and cycles
+		Application string `json:"application,omitempty"`
+		//Name - the service name, the dependency like "app[service] -> reference", the team coupling or the cycle
+		Name string `json:"name,omitempty"`
+		//Attribute - the changed attribute (team, group, status or relationship)
+		Attribute string `json:"attribute,omitempty"`
+		Base      string `json:"base,omitempty"`
+		Head      string `json:"head,omitempty"`
+		//Source - where the element is defined (in the base project for removed elements)
+		Source core.SourcePosition `json:"source"`
+	}
+)
+
+const (
+	CHANGE_ADDED   = "added"
+	CHANGE_REMOVED = "removed"
+	CHANGE_RENAMED = "renamed"
+	CHANGE_CHANGED = "changed"
+
+	ELEMENT_APPLICATION   = "application"
+	ELEMENT_SERVICE       = "service"
+	ELEMENT_DEPENDENCY    = "dependency"
+	ELEMENT_TEAM_COUPLING = "team-coupling"
+	ELEMENT_CYCLE         = "cycle"
+
+	//renameSimilarity - the minimum share of equal services, dependencies and attributes of a removed and an added application to treat them as renamed
+	renameSimilarity = 0.5
+)
+
+//CreateProjectDiff - compares the head version of a project with the base version
+func CreateProjectDiff(base *core.Project, head *core.Project) *ProjectDiff {
+	d := &ProjectDiff{
+		base:         base.Graph(),
+		head:         head.Graph(),
+		renamed:      make(map[string]string),
+		changedEdges: make(map[[2]string]bool),
+	}
+	d.compareApplications()
+	d.compareTeamCouplings()
+	d.compareCycles(base, head)
+	return d
+}
+
+//HasChanges - true if any change was found
+func (d *ProjectDiff) HasChanges() bool {
+	return len(d.Changes) > 0
+}
+
+//ChangesOf - returns the changes of the element type (see ELEMENT_* constants)
+func (d *ProjectDiff) ChangesOf(element string) []*Change {
+	var changes []*Change
+	for _, change := range d.Changes {
+		if change.Element == element {
+			changes = append(changes, change)
+		}
+	}
+	return changes
+}
+
+//String - one line description like "changed team of application checkout: team1 -> team2"
+func (c *Change) String() string {
+	switch c.Kind {
+	case CHANGE_RENAMED:
+		return fmt.Sprintf("renamed %v %v to %v", c.Element, c.Base, c.Head)
+	case CHANGE_CHANGED:
+		return fmt.Sprintf("changed %v of %v %v: %v -> %v", c.Attribute, c.Element, c.QualifiedName(), valueOrNone(c.Base), valueOrNone(c.Head))
+	}
+	if c.Element == ELEMENT_TEAM_COUPLING {
+		return fmt.Sprintf("%v team coupling %v (%v)", c.Kind, c.Name, c.Head+c.Base)
+	}
+	return fmt.Sprintf("%v %v %v", c.Kind, c.Element, c.QualifiedName())
+}
+
+//QualifiedName - the name of the changed element including the application, e.g. "checkout.api" for a service
+func (c *Change) QualifiedName() string {
+	switch c.Element {
+	case ELEMENT_APPLICATION:
+		return c.Application
+	case ELEMENT_SERVICE:
+		return c.Application + "." + c.Name
+	}
+	return c.Name
+}
+
+func (d *ProjectDiff) compareApplications() {
+	var removed, added []*core.Application
+	for _, application := range d.base.Applications() {
+		if _, found := d.head.Application(application.Name); !found {
+			removed = append(removed, application)
+		}
+	}
+	for _, application := range d.head.Applications() {
+		if _, found := d.base.Application(application.Name); !found {
+			added = append(added, application)
+		}
+	}
+	d.detectRenames(removed, added)
+
+	for _, application := range removed {
+		if _, isRenamed := d.renamed[application.Name]; !isRenamed {
+			d.add(&Change{Kind: CHANGE_REMOVED, Element: ELEMENT_APPLICATION, Application: application.Name, Source: application.Source})
+		}
+	}
+	for _, application := range d.head.Applications() {
+		baseApplication, found := d.baseApplication(application.Name)
+		if !found {
+			d.add(&Change{Kind: CHANGE_ADDED, Element: ELEMENT_APPLICATION, Application: application.Name, Source: application.Source})
+			continue
+		}
+		if baseApplication.Name != application.Name {
+			d.add(&Change{Kind: CHANGE_RENAMED, Element: ELEMENT_APPLICATION, Application: application.Name, Base: baseApplication.Name, Head: application.Name, Source: application.Source})
+		}
+		d.compareApplication(baseApplication, application)
+	}
+}
+
+// detectRenames - pairs removed and added applications with the most similar services, dependencies and attributes
+func (d *ProjectDiff) detectRenames(removed []*core.Application, added []*core.Application) {
+	type candidate struct {
+		removed, added *core.Application
+		similarity     float64
+	}
+	var candidates []candidate
+	for _, removedApplication := range removed {
+		for _, addedApplication := range added {
+			if similarity := similarity(removedApplication, addedApplication); similarity >= renameSimilarity {
+				candidates = append(candidates, candidate{removedApplication, addedApplication, similarity})
+			}
+		}
+	}
+	sort.SliceStable(candidates, func(i, j int) bool {
+		return candidates[i].similarity > candidates[j].similarity
+	})
+	renamedTo := make(map[string]bool)
+	for _, c := range candidates {
+		if _, isRenamed := d.renamed[c.removed.Name]; isRenamed || renamedTo[c.added.Name] {
+			continue
+		}
+		d.renamed[c.removed.Name] = c.added.Name
+		renamedTo[c.added.Name] = true
+	}
+}
+
+// similarity - share of equal characteristics. Applications without any equal service or dependency are never similar
+func similarity(a *core.Application, b *core.Application) float64 {
+	aCharacteristics := characteristics(a)
+	bCharacteristics := characteristics(b)
+	equal, equalStructure := 0, 0
+	for characteristic := range aCharacteristics {
+		if bCharacteristics[characteristic] {
+			equal++
+			if !strings.HasPrefix(characteristic, "attribute:") {
+				equalStructure++
+			}
+		}
+	}
+	if equalStructure == 0 {
+		return 0
+	}
+	return float64(equal) / float64(len(aCharacteristics)+len(bCharacteristics)-equal)
+}
+
+func characteristics(application *core.Application) map[string]bool {
+	result := make(map[string]bool)
+	for _, service := range application.ProvidedServices {
+		result["service:"+service.Name] = true
+		for _, dependency := range service.Dependencies {
+			result["dependency:"+dependency.Reference] = true
+		}
+	}
+	for _, dependency := range application.Dependencies {
+		result["dependency:"+dependency.Reference] = true
+	}
+	for _, infrastructure := range application.InfrastructureDependencies {
+		result["infrastructure:"+infrastructure.Type] = true
+	}
+	for _, attribute := range []string{application.Team, application.Group, application.Technology} {
+		if attribute != "" {
+			result["attribute:"+attribute] = true
+		}
+	}
+	return result
+}
+
+func (d *ProjectDiff) compareApplication(base *core.Application, head *core.Application) {
+	d.compareAttribute(ELEMENT_APPLICATION, head.Name, "", "team", base.Team, head.Team, head.Source)
+	d.compareAttribute(ELEMENT_APPLICATION, head.Name, "", "group", base.Group, head.Group, head.Source)
+	d.compareAttribute(ELEMENT_APPLICATION, head.Name, "", "status", string(base.Status), string(head.Status), head.Source)
+
+	for _, service := range base.ProvidedServices {
+		if _, err := head.FindService(service.Name); err != nil {
+			d.add(&Change{Kind: CHANGE_REMOVED, Element: ELEMENT_SERVICE, Application: head.Name, Name: service.Name, Source: service.Source})
+		}
+	}
+	for _, service := range head.ProvidedServices {
+		baseService, err := base.FindService(service.Name)
+		if err != nil {
+			d.add(&Change{Kind: CHANGE_ADDED, Element: ELEMENT_SERVICE, Application: head.Name, Name: service.Name, Source: service.Source})
+			continue
+		}
+		d.compareAttribute(ELEMENT_SERVICE, head.Name, service.Name, "status", string(baseService.Status), string(service.Status), service.Source)
+	}
+
+	baseEdges := d.edgesByKey(d.base, base.Name, head.Name)
+	headEdges := d.edgesByKey(d.head, head.Name, head.Name)
+	for _, edge := range d.base.OutgoingEdges(base.Name) {
+		key := d.edgeKey(edge, head.Name)
+		if _, found := headEdges[key]; !found && baseEdges[key] == edge {
+			d.changedEdges[[2]string{head.Name, d.headName(edge.TargetName)}] = true
+			d.add(&Change{Kind: CHANGE_REMOVED, Element: ELEMENT_DEPENDENCY, Application: head.Name, Name: key, Source: edge.Dependency.Source})
+		}
+	}
+	for _, edge := range d.head.OutgoingEdges(head.Name) {
+		key := d.edgeKey(edge, head.Name)
+		if headEdges[key] != edge {
+			continue
+		}
+		baseEdge, found := baseEdges[key]
+		if !found || baseEdge.Relationship != edge.Relationship || baseEdge.Status != edge.Status {
+			d.changedEdges[[2]string{head.Name, edge.TargetName}] = true
+		}
+		if !found {
+			d.add(&Change{Kind: CHANGE_ADDED, Element: ELEMENT_DEPENDENCY, Application: head.Name, Name: key, Source: edge.Dependency.Source})
+			continue
+		}
+		d.compareAttribute(ELEMENT_DEPENDENCY, head.Name, key, "relationship", string(baseEdge.Relationship), string(edge.Relationship), edge.Dependency.Source)
+		d.compareAttribute(ELEMENT_DEPENDENCY, head.Name, key, "status", string(baseEdge.Status), string(edge.Status), edge.Dependency.Source)
+	}
+}
+
+func (d *ProjectDiff) compareAttribute(element string, application string, name string, attribute string, base string, head string, source core.SourcePosition) {
+	if base == head {
+		return
+	}
+	d.add(&Change{Kind: CHANGE_CHANGED, Element: element, Application: application, Name: name, Attribute: attribute, Base: base, Head: head, Source: source})
+}
+
+// edgesByKey - the first edge of every dependency of the application (duplicated declarations are compared once)
+func (d *ProjectDiff) edgesByKey(graph *core.Graph, applicationName string, headName string) map[string]*core.GraphEdge {
+	result := make(map[string]*core.GraphEdge)
+	for _, edge := range graph.OutgoingEdges(applicationName) {
+		if key := d.edgeKey(edge, headName); result[key] == nil {
+			result[key] = edge
+		}
+	}
+	return result
+}
+
+// edgeKey - the dependency like "app[service] -> reference" with the head names of renamed applications
+func (d *ProjectDiff) edgeKey(edge *core.GraphEdge, headName string) string {
+	from := headName
+	if edge.SourceService != "" {
+		from += "[" + edge.SourceService + "]"
+	}
+	reference := d.headName(edge.TargetName)
+	if edge.TargetService != "" {
+		reference += "." + edge.TargetService
+	}
+	return from + " -> " + reference
+}
+
+func (d *ProjectDiff) compareTeamCouplings() {
+	byTeam := func(application *core.Application) string {
+		return application.Team
+	}
+	baseCouplings := make(map[string]*core.ClusterRelation)
+	for _, relation := range d.base.ClusterRelations(byTeam, nil) {
+		baseCouplings[relation.FromCluster+" -> "+relation.ToCluster] = relation
+	}
+	headCouplings := make(map[string]*core.ClusterRelation)
+	for _, relation := range d.head.ClusterRelations(byTeam, nil) {
+		name := relation.FromCluster + " -> " + relation.ToCluster
+		headCouplings[name] = relation
+		if _, found := baseCouplings[name]; !found {
+			d.add(&Change{Kind: CHANGE_ADDED, Element: ELEMENT_TEAM_COUPLING, Name: name, Head: edgeNames(relation.Edges), Source: relation.Edges[0].Dependency.Source})
+		}
+	}
+	for _, relation := range d.base.ClusterRelations(byTeam, nil) {
+		name := relation.FromCluster + " -> " + relation.ToCluster
+		if _, found := headCouplings[name]; !found {
+			d.add(&Change{Kind: CHANGE_REMOVED, Element: ELEMENT_TEAM_COUPLING, Name: name, Base: edgeNames(relation.Edges), Source: relation.Edges[0].Dependency.Source})
+		}
+	}
+}
+
+func (d *ProjectDiff) compareCycles(base *core.Project, head *core.Project) {
+	analyzer := analyze.ProjectAnalyzer{}
+	baseCycles, _ := analyzer.FindCyclicDependencies(base)
+	headCycles, _ := analyzer.FindCyclicDependencies(head)
+	baseKeys := make(map[string]bool)
+	for _, cycle := range baseCycles {
+		baseKeys[d.cycleKey(cycle)] = true
+	}
+	headKeys := make(map[string]bool)
+	for _, cycle := range headCycles {
+		key := d.cycleKey(cycle)
+		headKeys[key] = true
+		if !baseKeys[key] {
+			d.add(&Change{Kind: CHANGE_ADDED, Element: ELEMENT_CYCLE, Name: cycle.String(), Source: cycleSource(cycle)})
+		}
+	}
+	for _, cycle := range baseCycles {
+		if !headKeys[d.cycleKey(cycle)] {
+			d.add(&Change{Kind: CHANGE_REMOVED, Element: ELEMENT_CYCLE, Name: cycle.String(), Source: cycleSource(cycle)})
+		}
+	}
+}
+
+// cycleKey - the (head) names of the applications in the cycle starting with the smallest name
+func (d *ProjectDiff) cycleKey(cycle *analyze.CyclicDependency) string {
+	var names []string
+	start := 0
+	for i, name := range cycle.Applications() {
+		names = append(names, d.headName(name))
+		if names[i] < names[start] {
+			start = i
+		}
+	}
+	return strings.Join(append(names[start:], names[:start]...), " -> ")
+}
+
+func cycleSource(cycle *analyze.CyclicDependency) core.SourcePosition {
+	for _, step := range cycle.Steps {
+		for _, dependency := range step.Dependencies {
+			return dependency.Source
+		}
+	}
+	return core.SourcePosition{}
+}
+
+// baseApplication - the base version of a head application (the renamed one if it was renamed)
+func (d *ProjectDiff) baseApplication(headName string) (*core.Application, bool) {
+	for baseName, renamedTo := range d.renamed {
+		if renamedTo == headName {
+			return d.base.Application(baseName)
+		}
+	}
+	return d.base.Application(headName)
+}
+
+// headName - the head name of a base application
+func (d *ProjectDiff) headName(baseName string) string {
+	if renamedTo, isRenamed := d.renamed[baseName]; isRenamed {
+		return renamedTo
+	}
+	return baseName
+}
+
+func (d *ProjectDiff) add(change *Change) {
+	d.Changes = append(d.Changes, change)
+}
+
+func edgeNames(edges []*core.GraphEdge) string {
+	var names []string
+	for _, edge := range edges {
+		names = append(names, edge.From.Name+" -> "+edge.Reference())
+	}
+	return strings.Join(names, ", ")
+}
+
+func valueOrNone(value string) string {
+	if value == "" {
+		return "(none)"
+	}
+	return value
+}
@@ -0,0 +1,115 @@
+package diff
+
+import (
+	"bytes"
+	"strings"
+	"testing"
+
+	"github.com/AOEpeople/vistecture/v2/model/core"
+)
+
+func testProjects() (*core.Project, *core.Project) {
+	base := &core.Project{
+		Applications: []*core.Application{
+			{
+				Name:             "shop",
+				Team:             "storefront",
+				ProvidedServices: []core.Service{{Name: "ui"}, {Name: "legacy-api"}},
+				Dependencies:     []core.Dependency{{Reference: "orders.api"}, {Reference: "search"}},
+			},
+			{
+				Name:             "orders",
+				Team:             "checkout",
+				ProvidedServices: []core.Service{{Name: "api"}, {Name: "events"}},
+				Dependencies:     []core.Dependency{{Reference: "payment", Relationship: core.RELATIONSHIP_CUSTOMER_SUPPLIER}},
+			},
+			{Name: "payment", Team: "checkout", ProvidedServices: []core.Service{{Name: "api"}}},
+			{Name: "search", Team: "storefront"},
+		},
+	}
+	head := &core.Project{
+		Applications: []*core.Application{
+			{
+				Name:             "shop",
+				Team:             "storefront",
+				Group:            "frontend",
+				ProvidedServices: []core.Service{{Name: "ui"}},
+				Dependencies:     []core.Dependency{{Reference: "order-service.api"}, {Reference: "payment"}},
+			},
+			{
+				Name:             "order-service",
+				Team:             "checkout",
+				ProvidedServices: []core.Service{{Name: "api"}, {Name: "events"}},
+				Dependencies:     []core.Dependency{{Reference: "payment", Relationship: core.RELATIONSHIP_CONFORMIST}},
+			},
+			{
+				Name:             "payment",
+				Team:             "payments",
+				ProvidedServices: []core.Service{{Name: "api"}},
+				Dependencies:     []core.Dependency{{Reference: "shop"}},
+			},
+		},
+	}
+	return base, head
+}
+
+func TestCreateProjectDiff(t *testing.T) {
+	diff := CreateProjectDiff(testProjects())
+	var lines []string
+	for _, change := range diff.Changes {
+		lines = append(lines, change.String())
+	}
+	expected := []string{
+		"removed application search",
+		"changed group of application shop: (none) -> frontend",
+		"removed service shop.legacy-api",
+		"removed dependency shop -> search",
+		"added dependency shop -> payment",
+		"renamed application orders to order-service",
+		"changed relationship of dependency order-service -> payment: customer-supplier -> conformist",
+		"changed team of application payment: checkout -> payments",
+		"added dependency payment -> shop",
+		"added team coupling checkout -> payments (order-service -> payment)",
+		"added team coupling payments -> storefront (payment -> shop)",
+		"added team coupling storefront -> payments (shop -> payment)",
+		"added cycle shop -> order-service.api -> payment -> shop",
+		"added cycle shop -> payment -> shop",
+	}
+	if strings.Join(lines, "\n") != strings.Join(expected, "\n") {
+		t.Errorf("Expected changes:\n%v\ngot:\n%v", strings.Join(expected, "\n"), strings.Join(lines, "\n"))
+	}
+}
+
+func TestProjectDiff_Write(t *testing.T) {
+	diff := CreateProjectDiff(testProjects())
+
+	var markdown bytes.Buffer
+	if err := diff.Write(&markdown, FORMAT_MARKDOWN); err != nil {
+		t.Fatal(err)
+	}
+	for _, expected := range []string{"### Applications\n", "- **renamed** `orders` to `order-service`\n", "- **changed** group of `shop`: _none_ → `frontend`\n", "### Cycles\n"} {
+		if !strings.Contains(markdown.String(), expected) {
+			t.Errorf("Expected %q in markdown:\n%v", expected, markdown.String())
+		}
+	}
+
+	var dot bytes.Buffer
+	if err := diff.Write(&dot, FORMAT_DOT); err != nil {
+		t.Fatal(err)
+	}
+	for _, expected := range []string{
+		`"search" [color="#c62828", fillcolor="#ffebee", style="rounded,filled,dashed"];`,
+		`"shop" -> "search" [color="#c62828", style=dashed, label="removed", fontcolor="#c62828"];`,
+		`"shop" -> "payment" [color="#2e7d32", penwidth=2, label="added", fontcolor="#2e7d32"];`,
+		`"shop" -> "order-service" [color="#9e9e9e"];`,
+		`"order-service" -> "payment" [color="#ef6c00", penwidth=2, label="changed", fontcolor="#ef6c00"];`,
+	} {
+		if !strings.Contains(dot.String(), expected) {
+			t.Errorf("Expected %q in dot:\n%v", expected, dot.String())
+		}
+	}
+
+	if err := diff.Write(&dot, "xml"); err == nil {
+		t.Error("Expected an error for an unknown format")
+	}
+}
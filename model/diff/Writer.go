@@ -0,0 +1,106 @@
+package diff
+
+import (
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+	"strings"
+)
+
+const (
+	FORMAT_TEXT     = "text"
+	FORMAT_MARKDOWN = "markdown"
+	FORMAT_JSON     = "json"
+	FORMAT_DOT      = "dot"
+)
+
+//sections - the element types in the order they are written with their headline
+var sections = []struct {
+	element  string
+	headline string
+}{
+	{ELEMENT_APPLICATION, "Applications"},
+	{ELEMENT_SERVICE, "Services"},
+	{ELEMENT_DEPENDENCY, "Dependencies"},
+	{ELEMENT_TEAM_COUPLING, "Team couplings"},
+	{ELEMENT_CYCLE, "Cycles"},
+}
+
+//Write - writes the changes in the given format (text, markdown, json or dot)
+func (d *ProjectDiff) Write(w io.Writer, format string) error {
+	switch format {
+	case FORMAT_TEXT, "":
+		return d.writeText(w)
+	case FORMAT_MARKDOWN:
+		return d.writeMarkdown(w)
+	case FORMAT_JSON:
+		encoder := json.NewEncoder(w)
+		encoder.SetIndent("", "  ")
+		return encoder.Encode(d)
+	case FORMAT_DOT:
+		_, err := io.WriteString(w, CreateDotDrawer(d).Draw())
+		return err
+	}
+	return errors.New(fmt.Sprintf("Unknown output format '%v' - use %v, %v, %v or %v", format, FORMAT_TEXT, FORMAT_MARKDOWN, FORMAT_JSON, FORMAT_DOT))
+}
+
+func (d *ProjectDiff) writeText(w io.Writer) error {
+	if !d.HasChanges() {
+		_, err := fmt.Fprintln(w, "No architecture changes")
+		return err
+	}
+	for _, change := range d.Changes {
+		line := change.String()
+		if change.Source.IsKnown() {
+			line += " (" + change.Source.String() + ")"
+		}
+		if _, err := fmt.Fprintln(w, line); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+// writeMarkdown - a section per element type, meant as merge request comment
+func (d *ProjectDiff) writeMarkdown(w io.Writer) error {
+	var b strings.Builder
+	b.WriteString("## Architecture changes\n\n")
+	if !d.HasChanges() {
+		b.WriteString("No architecture changes.\n")
+	}
+	for _, section := range sections {
+		changes := d.ChangesOf(section.element)
+		if len(changes) == 0 {
+			continue
+		}
+		b.WriteString(fmt.Sprintf("### %v\n\n", section.headline))
+		for _, change := range changes {
+			b.WriteString("- " + markdownLine(change) + "\n")
+		}
+		b.WriteString("\n")
+	}
+	_, err := io.WriteString(w, b.String())
+	return err
+}
+
+func markdownLine(change *Change) string {
+	switch change.Kind {
+	case CHANGE_RENAMED:
+		return fmt.Sprintf("**renamed** `%v` to `%v`", change.Base, change.Head)
+	case CHANGE_CHANGED:
+		return fmt.Sprintf("**changed** %v of `%v`: %v → %v", change.Attribute, change.QualifiedName(), markdownValue(change.Base), markdownValue(change.Head))
+	}
+	line := fmt.Sprintf("**%v** `%v`", change.Kind, change.QualifiedName())
+	if change.Element == ELEMENT_TEAM_COUPLING {
+		line += " (" + change.Head + change.Base + ")"
+	}
+	return line
+}
+
+func markdownValue(value string) string {
+	if value == "" {
+		return "_none_"
+	}
+	return "`" + value + "`"
+}
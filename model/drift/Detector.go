@@ -0,0 +1,192 @@
+package drift
+
+import (
+	"sort"
+	"strings"
+
+	"github.com/AOEpeople/vistecture/v2/model/core"
+)
+
+type (
+	//Detector - compares the declared dependencies of a project with the observed calls
+	Detector struct {
+		graph *core.Graph
+		//applicationsByService - the application of a service name of the traces
+		applicationsByService map[string]*core.Application
+	}
+
+	//Report - the drift between declared dependencies and observed calls
+	Report struct {
+		//Undeclared - calls between applications that have no declared dependency
+		Undeclared []*ObservedRelation `json:"undeclared"`
+		//Unobserved - pairs of applications with declared dependencies but without calls - only for calling applications and pairs with at least one not planned dependency (planned dependencies are not expected to be observed)
+		Unobserved []*RelationTraffic `json:"unobserved"`
+		//Relations - the traffic of every pair of applications with declared dependencies
+		Relations []*RelationTraffic `json:"relations"`
+		//UnknownServices - services of the traces that are not mapped to an application
+		UnknownServices []*ObservedService `json:"unknownServices"`
+		//Unobservable - applications without observed outgoing calls (e.g. not traced) - their dependencies are not reported as unobserved
+		Unobservable []string `json:"unobservable"`
+	}
+
+	//ObservedRelation - the calls from one application to another
+	ObservedRelation struct {
+		From  string `json:"from"`
+		To    string `json:"to"`
+		Count int    `json:"count"`
+	}
+
+	//RelationTraffic - the declared dependencies from one application to another with the number of observed calls between the two applications.
+	// Traces do not name the provided services - so the calls are counted per pair of applications, not per declared dependency
+	RelationTraffic struct {
+		From  string `json:"from"`
+		To    string `json:"to"`
+		Count int    `json:"count"`
+		//Dependencies - the declared dependencies from From to To (e.g. to different services of To)
+		Dependencies []*DeclaredDependency `json:"dependencies"`
+	}
+
+	//DeclaredDependency - a dependency of a RelationTraffic
+	DeclaredDependency struct {
+		//Service - the provided service that declares the dependency - empty if declared on application level
+		Service   string              `json:"service,omitempty"`
+		Reference string              `json:"reference"`
+		Status    core.Status         `json:"status,omitempty"`
+		Source    core.SourcePosition `json:"source"`
+	}
+
+	//ObservedService - a service of the traces with the number of calls it is involved in
+	ObservedService struct {
+		Name  string `json:"name"`
+		Count int    `json:"count"`
+	}
+)
+
+const (
+	//DEFAULT_MAPPING_PROPERTY - the application property with the service name(s) used in the traces
+	DEFAULT_MAPPING_PROPERTY = "service-name"
+)
+
+//CreateDetector - Factory. The service names of the traces are mapped to applications by the given property (comma separated for several names) or else by the application name
+func CreateDetector(project *core.Project, mappingProperty string) *Detector {
+	mappingProperty = strings.TrimPrefix(mappingProperty, "properties.")
+	if mappingProperty == "" {
+		mappingProperty = DEFAULT_MAPPING_PROPERTY
+	}
+	d := &Detector{
+		graph:                 project.Graph(),
+		applicationsByService: make(map[string]*core.Application),
+	}
+	for _, application := range d.graph.Applications() {
+		if _, exists := d.applicationsByService[application.Name]; !exists {
+			d.applicationsByService[application.Name] = application
+		}
+	}
+	// mapped names win over application names
+	for _, application := range d.graph.Applications() {
+		for _, serviceName := range strings.Split(application.Properties[mappingProperty], ",") {
+			if serviceName = strings.TrimSpace(serviceName); serviceName != "" {
+				d.applicationsByService[serviceName] = application
+			}
+		}
+	}
+	return d
+}
+
+//Application - returns the application a service name of the traces is mapped to
+func (d *Detector) Application(serviceName string) (*core.Application, bool) {
+	application, found := d.applicationsByService[serviceName]
+	return application, found
+}
+
+//Detect - compares the calls with the declared dependencies. Calls within an application are ignored
+func (d *Detector) Detect(calls []*Call) *Report {
+	report := &Report{}
+	observed := make(map[[2]string]int)
+	callingApplications := make(map[string]bool)
+	unknown := make(map[string]*ObservedService)
+	var relations []*ObservedRelation
+
+	for _, call := range calls {
+		from, fromFound := d.Application(call.Caller)
+		to, toFound := d.Application(call.Callee)
+		if !fromFound {
+			report.UnknownServices = addObservedService(report.UnknownServices, unknown, call.Caller, call.Count)
+		}
+		if !toFound {
+			report.UnknownServices = addObservedService(report.UnknownServices, unknown, call.Callee, call.Count)
+		}
+		if !fromFound || !toFound || from == to {
+			continue
+		}
+		callingApplications[from.Name] = true
+		key := [2]string{from.Name, to.Name}
+		if _, found := observed[key]; !found {
+			relations = append(relations, &ObservedRelation{From: from.Name, To: to.Name})
+		}
+		observed[key] += call.Count
+	}
+
+	for _, relation := range relations {
+		relation.Count = observed[[2]string{relation.From, relation.To}]
+		if len(d.graph.EdgesBetween(relation.From, relation.To)) == 0 {
+			report.Undeclared = append(report.Undeclared, relation)
+		}
+	}
+	sort.SliceStable(report.Undeclared, func(i, j int) bool {
+		return report.Undeclared[i].Count > report.Undeclared[j].Count
+	})
+	sort.SliceStable(report.UnknownServices, func(i, j int) bool {
+		return report.UnknownServices[i].Count > report.UnknownServices[j].Count
+	})
+
+	for _, application := range d.graph.Applications() {
+		if !callingApplications[application.Name] {
+			report.Unobservable = append(report.Unobservable, application.Name)
+		}
+		byTarget := make(map[string]*RelationTraffic)
+		var relations []*RelationTraffic
+		isExpected := make(map[*RelationTraffic]bool)
+		for _, edge := range d.graph.OutgoingEdges(application.Name) {
+			if edge.To == nil || edge.To == edge.From {
+				continue
+			}
+			traffic, found := byTarget[edge.To.Name]
+			if !found {
+				traffic = &RelationTraffic{From: edge.From.Name, To: edge.To.Name, Count: observed[[2]string{edge.From.Name, edge.To.Name}]}
+				byTarget[edge.To.Name] = traffic
+				relations = append(relations, traffic)
+			}
+			traffic.Dependencies = append(traffic.Dependencies, &DeclaredDependency{
+				Service:   edge.SourceService,
+				Reference: edge.Dependency.Reference,
+				Status:    edge.Status,
+				Source:    edge.Dependency.Source,
+			})
+			isExpected[traffic] = isExpected[traffic] || !edge.IsPlanned()
+		}
+		for _, traffic := range relations {
+			report.Relations = append(report.Relations, traffic)
+			if traffic.Count == 0 && callingApplications[application.Name] && isExpected[traffic] {
+				report.Unobserved = append(report.Unobserved, traffic)
+			}
+		}
+	}
+	return report
+}
+
+//HasDrift - true if undeclared calls were observed (or with includeUnobserved if declared dependencies were not observed)
+func (r *Report) HasDrift(includeUnobserved bool) bool {
+	return len(r.Undeclared) > 0 || (includeUnobserved && len(r.Unobserved) > 0)
+}
+
+func addObservedService(services []*ObservedService, byName map[string]*ObservedService, name string, count int) []*ObservedService {
+	service, found := byName[name]
+	if !found {
+		service = &ObservedService{Name: name}
+		byName[name] = service
+		services = append(services, service)
+	}
+	service.Count += count
+	return services
+}
@@ -0,0 +1,82 @@
+package drift
+
+import (
+	"reflect"
+	"strings"
+	"testing"
+
+	"github.com/AOEpeople/vistecture/v2/model/core"
+)
+
+func TestReadCalls(t *testing.T) {
+	for fileName, expected := range map[string][]*Call{
+		"fixtures/jaeger.json": {{Caller: "web-frontend", Callee: "checkout", Count: 1}, {Caller: "checkout", Callee: "mystery", Count: 1}},
+		"fixtures/zipkin.json": {{Caller: "checkout", Callee: "payment", Count: 2}},
+		"fixtures/otel.json":   {{Caller: "payment", Callee: "stock", Count: 1}},
+		"fixtures/calls.csv":   {{Caller: "web-frontend", Callee: "checkout", Count: 40}, {Caller: "checkout", Callee: "payment", Count: 2}},
+	} {
+		calls, err := ReadCalls(fileName)
+		if err != nil {
+			t.Fatal(err)
+		}
+		if !reflect.DeepEqual(calls, expected) {
+			t.Errorf("%v: expected %v, got %v", fileName, expected, calls)
+		}
+	}
+}
+
+func TestDetector_Detect(t *testing.T) {
+	project := &core.Project{
+		Applications: []*core.Application{
+			{
+				Name:       "frontend",
+				Properties: map[string]string{"service-name": "web-frontend"},
+				Dependencies: []core.Dependency{
+					{Reference: "checkout.api"},
+					{Reference: "checkout.ui"},
+					{Reference: "search", Status: core.STATUS_PLANNED},
+				},
+			},
+			{
+				Name:         "checkout",
+				Dependencies: []core.Dependency{{Reference: "payment"}, {Reference: "stock"}},
+			},
+			{Name: "payment"},
+			{Name: "stock"},
+			{Name: "search"},
+		},
+	}
+	calls, readErrors := ReadCallsFromPath("fixtures")
+	if len(readErrors) > 0 {
+		t.Fatal(readErrors)
+	}
+	report := CreateDetector(project, "properties.service-name").Detect(calls)
+
+	if !reflect.DeepEqual(report.Undeclared, []*ObservedRelation{{From: "payment", To: "stock", Count: 1}}) {
+		t.Errorf("Expected payment -> stock to be undeclared, got %v", report.Undeclared)
+	}
+	if len(report.Unobserved) != 1 || report.Unobserved[0].From != "checkout" || report.Unobserved[0].To != "stock" {
+		t.Errorf("Expected checkout -> stock to be unobserved (the planned dependency is not expected), got %v", report.Unobserved)
+	}
+	counts := make(map[string]int)
+	for _, traffic := range report.Relations {
+		var references []string
+		for _, dependency := range traffic.Dependencies {
+			references = append(references, dependency.Reference)
+		}
+		counts[traffic.From+" -> "+strings.Join(references, ",")] = traffic.Count
+	}
+	expectedCounts := map[string]int{"frontend -> checkout.api,checkout.ui": 41, "frontend -> search": 0, "checkout -> payment": 4, "checkout -> stock": 0}
+	if !reflect.DeepEqual(counts, expectedCounts) {
+		t.Errorf("Expected traffic %v, got %v", expectedCounts, counts)
+	}
+	if !reflect.DeepEqual(report.UnknownServices, []*ObservedService{{Name: "mystery", Count: 1}}) {
+		t.Errorf("Expected mystery to be unknown, got %v", report.UnknownServices)
+	}
+	if !reflect.DeepEqual(report.Unobservable, []string{"stock", "search"}) {
+		t.Errorf("Expected stock and search to be unobservable, got %v", report.Unobservable)
+	}
+	if !report.HasDrift(false) {
+		t.Error("Expected drift")
+	}
+}
@@ -0,0 +1,359 @@
+package drift
+
+import (
+	"bytes"
+	"encoding/csv"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+	"io/ioutil"
+	"os"
+	"path/filepath"
+	"strconv"
+	"strings"
+)
+
+type (
+	//Call - the number of observed calls from one service to another. The service names are the ones of the traces
+	Call struct {
+		Caller string `json:"caller"`
+		Callee string `json:"callee"`
+		Count  int    `json:"count"`
+	}
+
+	// span - the format independent part of a span that is needed to find the calls between services
+	span struct {
+		traceId  string
+		id       string
+		parentId string
+		service  string
+		isClient bool
+		//peerService - the called service for client spans (e.g. the peer.service tag) - used if the called service is not traced itself
+		peerService string
+	}
+
+	jaegerExport struct {
+		Data []struct {
+			TraceID string `json:"traceID"`
+			Spans   []struct {
+				TraceID    string `json:"traceID"`
+				SpanID     string `json:"spanID"`
+				ProcessID  string `json:"processID"`
+				References []struct {
+					RefType string `json:"refType"`
+					SpanID  string `json:"spanID"`
+				} `json:"references"`
+				Tags []struct {
+					Key   string      `json:"key"`
+					Value interface{} `json:"value"`
+				} `json:"tags"`
+			} `json:"spans"`
+			Processes map[string]struct {
+				ServiceName string `json:"serviceName"`
+			} `json:"processes"`
+		} `json:"data"`
+	}
+
+	zipkinSpan struct {
+		TraceId       string         `json:"traceId"`
+		Id            string         `json:"id"`
+		ParentId      string         `json:"parentId"`
+		Kind          string         `json:"kind"`
+		LocalEndpoint zipkinEndpoint `json:"localEndpoint"`
+		//RemoteEndpoint - the called service of CLIENT spans
+		RemoteEndpoint zipkinEndpoint `json:"remoteEndpoint"`
+	}
+
+	zipkinEndpoint struct {
+		ServiceName string `json:"serviceName"`
+	}
+
+	otelExport struct {
+		ResourceSpans []struct {
+			Resource struct {
+				Attributes []otelAttribute `json:"attributes"`
+			} `json:"resource"`
+			ScopeSpans []otelScopeSpans `json:"scopeSpans"`
+			//InstrumentationLibrarySpans - the name of scopeSpans before OTLP 0.15
+			InstrumentationLibrarySpans []otelScopeSpans `json:"instrumentationLibrarySpans"`
+		} `json:"resourceSpans"`
+	}
+
+	otelScopeSpans struct {
+		Spans []struct {
+			TraceId      string `json:"traceId"`
+			SpanId       string `json:"spanId"`
+			ParentSpanId string `json:"parentSpanId"`
+			//Kind - number (3 = client) or name (SPAN_KIND_CLIENT)
+			Kind       interface{}     `json:"kind"`
+			Attributes []otelAttribute `json:"attributes"`
+		} `json:"spans"`
+	}
+
+	otelAttribute struct {
+		Key   string `json:"key"`
+		Value struct {
+			StringValue string `json:"stringValue"`
+		} `json:"value"`
+	}
+)
+
+const (
+	//TAG_PEER_SERVICE - the span tag (attribute) naming the called service
+	TAG_PEER_SERVICE = "peer.service"
+	//ATTRIBUTE_SERVICE_NAME - the OpenTelemetry resource attribute naming the service
+	ATTRIBUTE_SERVICE_NAME = "service.name"
+)
+
+//ReadCallsFromPath - reads the calls of a file or of all .json and .csv files of a folder. Files that cannot be read are returned as errors, the calls of all other files are summed up
+func ReadCallsFromPath(path string) ([]*Call, []error) {
+	var calls []*Call
+	var readErrors []error
+	err := filepath.Walk(path, func(fileName string, info os.FileInfo, err error) error {
+		if err != nil {
+			return err
+		}
+		if info.IsDir() || !(strings.HasSuffix(fileName, ".json") || strings.HasSuffix(fileName, ".csv")) {
+			return nil
+		}
+		read, err := ReadCalls(fileName)
+		if err != nil {
+			readErrors = append(readErrors, err)
+		}
+		calls = append(calls, read...)
+		return nil
+	})
+	if err != nil {
+		readErrors = append(readErrors, err)
+	}
+	return SumCalls(calls), readErrors
+}
+
+//ReadCalls - reads the calls of a CSV file (caller,callee,count) or a Jaeger, Zipkin or OpenTelemetry (OTLP) JSON trace export
+func ReadCalls(fileName string) ([]*Call, error) {
+	content, err := ioutil.ReadFile(fileName)
+	if err != nil {
+		return nil, err
+	}
+	var calls []*Call
+	if strings.HasSuffix(fileName, ".csv") {
+		calls, err = readCsv(bytes.NewReader(content))
+	} else {
+		calls, err = readTraces(content)
+	}
+	if err != nil {
+		return nil, errors.New(fmt.Sprintf("%v: cannot read calls: %v", fileName, err))
+	}
+	return calls, nil
+}
+
+//SumCalls - sums up the counts of calls between the same services (in order of the first occurrence)
+func SumCalls(calls []*Call) []*Call {
+	var result []*Call
+	byServices := make(map[[2]string]*Call)
+	for _, call := range calls {
+		key := [2]string{call.Caller, call.Callee}
+		if summed, found := byServices[key]; found {
+			summed.Count += call.Count
+			continue
+		}
+		summed := &Call{Caller: call.Caller, Callee: call.Callee, Count: call.Count}
+		byServices[key] = summed
+		result = append(result, summed)
+	}
+	return result
+}
+
+// readCsv - one row per caller and callee. The count column is optional (default 1), a header row is skipped
+func readCsv(reader io.Reader) ([]*Call, error) {
+	csvReader := csv.NewReader(reader)
+	csvReader.FieldsPerRecord = -1
+	csvReader.TrimLeadingSpace = true
+	csvReader.Comment = '#'
+	rows, err := csvReader.ReadAll()
+	if err != nil {
+		return nil, err
+	}
+	var calls []*Call
+	for index, row := range rows {
+		if len(row) < 2 {
+			return nil, errors.New(fmt.Sprintf("row %v: expected caller,callee,count", index+1))
+		}
+		count := 1
+		if len(row) > 2 && row[2] != "" {
+			count, err = strconv.Atoi(row[2])
+			if err != nil {
+				if index == 0 {
+					continue
+				}
+				return nil, errors.New(fmt.Sprintf("row %v: invalid count %q", index+1, row[2]))
+			}
+		}
+		if index == 0 && strings.EqualFold(row[0], "caller") {
+			continue
+		}
+		calls = append(calls, &Call{Caller: row[0], Callee: row[1], Count: count})
+	}
+	return SumCalls(calls), nil
+}
+
+// readTraces - reads one or more (e.g. one per line like the OpenTelemetry collector file exporter writes) JSON documents
+func readTraces(content []byte) ([]*Call, error) {
+	var spans []*span
+	decoder := json.NewDecoder(bytes.NewReader(content))
+	for {
+		var document json.RawMessage
+		if err := decoder.Decode(&document); err == io.EOF {
+			break
+		} else if err != nil {
+			return nil, err
+		}
+		documentSpans, err := parseTraceDocument(document)
+		if err != nil {
+			return nil, err
+		}
+		spans = append(spans, documentSpans...)
+	}
+	return callsOfSpans(spans), nil
+}
+
+// parseTraceDocument - detects the format: Zipkin exports are lists of spans (or lists of traces), Jaeger exports have "data" and OpenTelemetry exports "resourceSpans"
+func parseTraceDocument(document json.RawMessage) ([]*span, error) {
+	trimmed := bytes.TrimSpace(document)
+	if len(trimmed) > 0 && trimmed[0] == '[' {
+		return parseZipkin(trimmed)
+	}
+	var keys map[string]json.RawMessage
+	if err := json.Unmarshal(trimmed, &keys); err != nil {
+		return nil, err
+	}
+	if _, isJaeger := keys["data"]; isJaeger {
+		return parseJaeger(trimmed)
+	}
+	if _, isOtel := keys["resourceSpans"]; isOtel {
+		return parseOtel(trimmed)
+	}
+	return nil, errors.New("unknown trace format - expected a Jaeger, Zipkin or OpenTelemetry JSON export")
+}
+
+func parseJaeger(document []byte) ([]*span, error) {
+	var export jaegerExport
+	if err := json.Unmarshal(document, &export); err != nil {
+		return nil, err
+	}
+	var spans []*span
+	for _, trace := range export.Data {
+		for _, jaegerSpan := range trace.Spans {
+			parsed := &span{
+				traceId: jaegerSpan.TraceID,
+				id:      jaegerSpan.SpanID,
+				service: trace.Processes[jaegerSpan.ProcessID].ServiceName,
+			}
+			if parsed.traceId == "" {
+				parsed.traceId = trace.TraceID
+			}
+			for _, reference := range jaegerSpan.References {
+				if reference.RefType == "CHILD_OF" || parsed.parentId == "" {
+					parsed.parentId = reference.SpanID
+				}
+			}
+			for _, tag := range jaegerSpan.Tags {
+				value := fmt.Sprintf("%v", tag.Value)
+				switch tag.Key {
+				case "span.kind":
+					parsed.isClient = value == "client" || value == "producer"
+				case TAG_PEER_SERVICE:
+					parsed.peerService = value
+				}
+			}
+			spans = append(spans, parsed)
+		}
+	}
+	return spans, nil
+}
+
+func parseZipkin(document []byte) ([]*span, error) {
+	var zipkinSpans []zipkinSpan
+	if err := json.Unmarshal(document, &zipkinSpans); err != nil {
+		// the Zipkin UI exports a list of traces
+		var traces [][]zipkinSpan
+		if json.Unmarshal(document, &traces) != nil {
+			return nil, err
+		}
+		for _, trace := range traces {
+			zipkinSpans = append(zipkinSpans, trace...)
+		}
+	}
+	var spans []*span
+	for _, zipkinSpan := range zipkinSpans {
+		spans = append(spans, &span{
+			traceId:     zipkinSpan.TraceId,
+			id:          zipkinSpan.Id,
+			parentId:    zipkinSpan.ParentId,
+			service:     zipkinSpan.LocalEndpoint.ServiceName,
+			isClient:    zipkinSpan.Kind == "CLIENT" || zipkinSpan.Kind == "PRODUCER",
+			peerService: zipkinSpan.RemoteEndpoint.ServiceName,
+		})
+	}
+	return spans, nil
+}
+
+func parseOtel(document []byte) ([]*span, error) {
+	var export otelExport
+	if err := json.Unmarshal(document, &export); err != nil {
+		return nil, err
+	}
+	var spans []*span
+	for _, resourceSpans := range export.ResourceSpans {
+		service := attributeValue(resourceSpans.Resource.Attributes, ATTRIBUTE_SERVICE_NAME)
+		for _, scopeSpans := range append(resourceSpans.ScopeSpans, resourceSpans.InstrumentationLibrarySpans...) {
+			for _, otelSpan := range scopeSpans.Spans {
+				kind := fmt.Sprintf("%v", otelSpan.Kind)
+				spans = append(spans, &span{
+					traceId:     otelSpan.TraceId,
+					id:          otelSpan.SpanId,
+					parentId:    otelSpan.ParentSpanId,
+					service:     service,
+					isClient:    kind == "3" || kind == "4" || kind == "SPAN_KIND_CLIENT" || kind == "SPAN_KIND_PRODUCER",
+					peerService: attributeValue(otelSpan.Attributes, TAG_PEER_SERVICE),
+				})
+			}
+		}
+	}
+	return spans, nil
+}
+
+func attributeValue(attributes []otelAttribute, key string) string {
+	for _, attribute := range attributes {
+		if attribute.Key == key {
+			return attribute.Value.StringValue
+		}
+	}
+	return ""
+}
+
+// callsOfSpans - every span whose parent span belongs to another service is a call from the parent service.
+// Client spans without such a child span are calls to their peer service (e.g. a service that is not traced itself)
+func callsOfSpans(spans []*span) []*Call {
+	byId := make(map[[2]string]*span)
+	for _, s := range spans {
+		byId[[2]string{s.traceId, s.id}] = s
+	}
+	hasRemoteChild := make(map[*span]bool)
+	var calls []*Call
+	for _, s := range spans {
+		parent, found := byId[[2]string{s.traceId, s.parentId}]
+		if s.parentId == "" || !found || parent.service == s.service || parent.service == "" || s.service == "" {
+			continue
+		}
+		hasRemoteChild[parent] = true
+		calls = append(calls, &Call{Caller: parent.service, Callee: s.service, Count: 1})
+	}
+	for _, s := range spans {
+		if s.isClient && !hasRemoteChild[s] && s.peerService != "" && s.peerService != s.service && s.service != "" {
+			calls = append(calls, &Call{Caller: s.service, Callee: s.peerService, Count: 1})
+		}
+	}
+	return SumCalls(calls)
+}
@@ -2,6 +2,7 @@ package graphviz
 
 import (
 	"os"
+	"strconv"
 	"strings"
 
 	model "github.com/AOEpeople/vistecture/v2/model/core"
@@ -61,11 +62,11 @@ func (ComponentDrawer ApplicationDrawer) Draw(hidePlanned bool) string {
 		}
 		var color string
 		switch service.Type {
-		case "api":
+		case model.SERVICE_TYPE_API:
 			color = "#A3C7D4"
-		case "gui":
+		case model.SERVICE_TYPE_GUI:
 			color = "#D4C1E0"
-		case "exchange", "topic":
+		case model.SERVICE_TYPE_EXCHANGE, model.SERVICE_TYPE_TOPIC:
 			color = "#BEE8D2"
 		default:
 			color = "#CFCFCF"
@@ -75,7 +76,7 @@ func (ComponentDrawer ApplicationDrawer) Draw(hidePlanned bool) string {
 		}
 
 		result += "<TR><TD COLSPAN=\"2\"  align=\"CENTER\" PORT=\"" + escape(service.Name) + "\" BGCOLOR=\"" + color + "\">"
-		result += "<FONT POINT-SIZE=\"10\">" + service.Type + ":" + escape(service.Name) + "</FONT>"
+		result += "<FONT POINT-SIZE=\"10\">" + string(service.Type) + ":" + escape(service.Name) + "</FONT>"
 		if service.IsOpenHost {
 			result += " <FONT COLOR=\"#33911a\">♡</FONT>"
 		}
@@ -86,6 +87,13 @@ func (ComponentDrawer ApplicationDrawer) Draw(hidePlanned bool) string {
 
 }
 
+// DrawCollapsed - draws the application as small stub without services, hiddenNeighbours is the number of its neighbours that are not drawn
+func (ComponentDrawer ApplicationDrawer) DrawCollapsed(hiddenNeighbours int) string {
+	Component := ComponentDrawer.originalComponent
+	label := strings.ToTitle(Component.Name) + "\\n+" + strconv.Itoa(hiddenNeighbours) + " more"
+	return "\"" + Component.Name + "\" [shape=box, style=\"dashed,rounded\", color=\"#1B4E5E\", fontcolor=\"#1B4E5E\", fontsize=10, label=\"" + label + "\"];\n"
+}
+
 func escape(value string) string {
 
 	value = strings.Replace(value, "&", "&amp;", -1)
@@ -0,0 +1,97 @@
+package graphviz
+
+import (
+	"fmt"
+	"strings"
+
+	"github.com/AOEpeople/vistecture/v2/model/analyze"
+)
+
+type (
+	//PathDrawer - draws the applications and dependencies of all found paths, the shortest path is highlighted
+	PathDrawer struct {
+		report *analyze.PathReport
+	}
+)
+
+//CreatePathDrawer - Factory
+func CreatePathDrawer(report *analyze.PathReport) *PathDrawer {
+	return &PathDrawer{report: report}
+}
+
+//Draw - returns the graph in DOT format. Every hop is labeled with the used services and relationships
+func (d *PathDrawer) Draw() string {
+	var b strings.Builder
+	b.WriteString("digraph paths {\n")
+	b.WriteString("  rankdir=LR;\n")
+	b.WriteString("  node [shape=box, style=\"rounded,filled\", fillcolor=white, fontname=Helvetica];\n")
+	b.WriteString("  edge [fontname=Helvetica, fontsize=10, color=\"#9e9e9e\", fontcolor=\"#757575\"];\n")
+
+	onShortestPath := make(map[string]bool)
+	if d.report.Shortest != nil {
+		for _, hop := range d.report.Shortest.Hops {
+			onShortestPath[hop.From] = true
+			onShortestPath[hop.To] = true
+			onShortestPath[hop.From+" -> "+hop.To] = true
+		}
+	}
+	for _, name := range []string{d.report.From, d.report.To} {
+		b.WriteString(fmt.Sprintf("  %q [fillcolor=\"#ffcdd2\", color=\"#c62828\", penwidth=3];\n", name))
+	}
+	drawn := map[string]bool{d.report.From: true, d.report.To: true}
+	for _, path := range d.report.Paths {
+		for _, hop := range path.Hops {
+			for _, name := range []string{hop.From, hop.To} {
+				if drawn[name] {
+					continue
+				}
+				drawn[name] = true
+				if onShortestPath[name] {
+					b.WriteString(fmt.Sprintf("  %q [fillcolor=\"#ffebee\", color=\"#c62828\", penwidth=2];\n", name))
+				} else {
+					b.WriteString(fmt.Sprintf("  %q;\n", name))
+				}
+			}
+		}
+	}
+	for _, path := range d.report.Paths {
+		for _, hop := range path.Hops {
+			key := hop.From + " -> " + hop.To
+			if drawn[key] {
+				continue
+			}
+			drawn[key] = true
+			if onShortestPath[key] {
+				b.WriteString(fmt.Sprintf("  %q -> %q [label=%q, color=\"#c62828\", fontcolor=\"#c62828\", penwidth=3];\n", hop.From, hop.To, hopLabel(hop)))
+			} else {
+				b.WriteString(fmt.Sprintf("  %q -> %q [label=%q];\n", hop.From, hop.To, hopLabel(hop)))
+			}
+		}
+	}
+	b.WriteString("}\n")
+	return b.String()
+}
+
+// hopLabel - one line per dependency with the used service and the relationship (empty for dependencies on application level without relationship)
+func hopLabel(hop *analyze.PathHop) string {
+	var lines []string
+	for _, dependency := range hop.Dependencies {
+		line := dependency.Service
+		if dependency.Relationship != "" {
+			line = strings.TrimSpace(line + " (" + string(dependency.Relationship) + ")")
+		}
+		if line != "" && !inStringSlice(line, lines) {
+			lines = append(lines, line)
+		}
+	}
+	return strings.Join(lines, "\n")
+}
+
+func inStringSlice(search string, in []string) bool {
+	for _, v := range in {
+		if v == search {
+			return true
+		}
+	}
+	return false
+}
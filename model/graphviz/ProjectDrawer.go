@@ -11,6 +11,7 @@ import (
 type ProjectDrawer struct {
 	//inherit
 	originalProject *model.Project
+	graph           *model.Graph
 	iconPath        string
 }
 
@@ -63,25 +64,16 @@ func (ProjectDrawer *ProjectDrawer) DrawComponent(Component *model.Application)
 
 	// Draw outgoing:
 	result = result + ProjectDrawer.drawComponentOutgoingRelations(Component, false)
-	allRelatedComponents, _ := Component.GetAllDependencyApplications(ProjectDrawer.originalProject)
-	for _, relatedComponent := range allRelatedComponents {
+	for _, relatedComponent := range ProjectDrawer.graph.Dependencies(Component.Name) {
 		drawer := ApplicationDrawer{originalComponent: relatedComponent, iconPath: ProjectDrawer.iconPath}
 		result = result + drawer.Draw(false)
 	}
 	//Draw incoming
-
-	allDependendComponents := ProjectDrawer.originalProject.FindApplicationThatReferenceTo(Component, false)
-	for _, relatedComponent := range allDependendComponents {
+	for _, relatedComponent := range ProjectDrawer.graph.Dependents(Component.Name) {
 		drawer := ApplicationDrawer{originalComponent: relatedComponent, iconPath: ProjectDrawer.iconPath}
 		result = result + drawer.Draw(false)
-		dependencies, e := relatedComponent.GetDependenciesTo(Component.Name)
-		if e != nil {
-			continue
-		}
-		for _, dependency := range dependencies {
-			if e == nil {
-				result += "\"" + relatedComponent.Name + "\" ->" + getGraphVizReference(dependency) + getEdgeLayoutFromDependency(dependency, relatedComponent.Display) + "\n"
-			}
+		for _, edge := range ProjectDrawer.graph.EdgesBetween(relatedComponent.Name, Component.Name) {
+			result += "\"" + relatedComponent.Name + "\" ->" + getGraphVizReference(edge.Dependency) + getEdgeLayoutFromDependency(edge.Dependency, relatedComponent.Display) + "\n"
 		}
 	}
 
@@ -94,30 +86,130 @@ func (ProjectDrawer *ProjectDrawer) DrawComponent(Component *model.Application)
 	return result
 }
 
-func (ProjectDrawer *ProjectDrawer) drawComponentOutgoingRelations(Component *model.Application, hidePlanned bool) string {
-	result := ""
-	// Relation from components
-	for _, dependency := range Component.Dependencies {
-		if dependency.Status == model.STATUS_PLANNED && hidePlanned {
+//DrawNeighbourhood - Draws the component with the applications up to upstreamDepth hops using it and up to downstreamDepth hops used by it and all edges among them.
+// Applications at the boundary with further (not drawn) neighbours are drawn as collapsed stubs, infrastructure dependencies are drawn for all other applications
+func (ProjectDrawer *ProjectDrawer) DrawNeighbourhood(Component *model.Application, upstreamDepth int, downstreamDepth int, hidePlanned bool) string {
+	var filter model.EdgeFilter
+	if hidePlanned {
+		filter = func(edge *model.GraphEdge) bool { return !edge.IsPlanned() }
+	}
+	walks := []struct {
+		direction model.GraphDirection
+		depth     int
+	}{{model.UPSTREAM, upstreamDepth}, {model.DOWNSTREAM, downstreamDepth}}
+
+	included := map[string]bool{Component.Name: true}
+	for _, walk := range walks {
+		if walk.depth <= 0 {
 			continue
 		}
-		dependencyComponent, err := dependency.GetApplication(ProjectDrawer.originalProject)
-		if err == nil && dependencyComponent.Status == model.STATUS_PLANNED && hidePlanned {
+		for _, reached := range ProjectDrawer.graph.Walk(Component.Name, walk.direction, walk.depth, filter) {
+			included[reached.Application.Name] = true
+		}
+	}
+	// applications at the boundary with the number of their neighbours that are not drawn
+	collapsed := make(map[string]int)
+	for _, walk := range walks {
+		if walk.depth <= 0 {
 			continue
 		}
-		result += "\"" + Component.Name + "\" ->" + getGraphVizReference(dependency) + getEdgeLayoutFromDependency(dependency, Component.Display) + "\n"
+		for _, reached := range ProjectDrawer.graph.Walk(Component.Name, walk.direction, walk.depth, filter) {
+			if reached.Depth == walk.depth {
+				collapsed[reached.Application.Name] += ProjectDrawer.countHiddenNeighbours(reached.Application.Name, walk.direction, filter, included)
+			}
+		}
 	}
-	// Relation from components/interfaces
-	for _, providedInterface := range Component.ProvidedServices {
-		for _, dependency := range providedInterface.Dependencies {
-			if dependency.Status == model.STATUS_PLANNED && hidePlanned {
+
+	var applications []*model.Application
+	for _, application := range ProjectDrawer.originalProject.Applications {
+		if first, _ := ProjectDrawer.graph.Application(application.Name); first == application && included[application.Name] {
+			applications = append(applications, application)
+		}
+	}
+	result := "digraph { graph [] \n"
+	for _, application := range applications {
+		drawer := ApplicationDrawer{originalComponent: application, iconPath: ProjectDrawer.iconPath}
+		if hidden := collapsed[application.Name]; hidden > 0 {
+			result += drawer.DrawCollapsed(hidden)
+		} else {
+			result += drawer.Draw(hidePlanned)
+		}
+	}
+	for _, application := range applications {
+		for _, edge := range ProjectDrawer.graph.OutgoingEdges(application.Name) {
+			if edge.To == nil || !included[edge.To.Name] || (filter != nil && !filter(edge)) {
 				continue
 			}
-			dependencyComponent, err := dependency.GetApplication(ProjectDrawer.originalProject)
-			if err == nil && dependencyComponent.Status == model.STATUS_PLANNED && hidePlanned {
-				continue
+			result += drawEdge(edge, collapsed[edge.From.Name] > 0, collapsed[edge.To.Name] > 0)
+		}
+	}
+	drawnInfrastructure := make(map[string]bool)
+	for _, application := range applications {
+		if collapsed[application.Name] > 0 {
+			continue
+		}
+		for _, infrastructureDependency := range application.InfrastructureDependencies {
+			if !drawnInfrastructure[infrastructureDependency.Type] {
+				drawnInfrastructure[infrastructureDependency.Type] = true
+				result = result + "\n\"" + infrastructureDependency.Type + "\"[shape=box, color=\"#576f96\"] \n"
 			}
-			result += "\"" + Component.Name + "\":\"" + providedInterface.Name + "\"->" + getGraphVizReference(dependency) + getEdgeLayoutFromDependency(dependency, Component.Display) + "\n"
+			result = result + "\n\"" + infrastructureDependency.Type + "\"->\"" + application.Name + "\"[color=\"#576f96\",arrowhead=none] \n"
+		}
+	}
+	result = result + "\n}"
+	return result
+}
+
+// countHiddenNeighbours - number of distinct neighbours in the direction that are not included
+func (ProjectDrawer *ProjectDrawer) countHiddenNeighbours(applicationName string, direction model.GraphDirection, filter model.EdgeFilter, included map[string]bool) int {
+	edges := ProjectDrawer.graph.OutgoingEdges(applicationName)
+	if direction == model.UPSTREAM {
+		edges = ProjectDrawer.graph.IncomingEdges(applicationName)
+	}
+	hidden := make(map[string]bool)
+	for _, edge := range edges {
+		if edge.To == nil || (filter != nil && !filter(edge)) {
+			continue
+		}
+		neighbour := edge.To.Name
+		if direction == model.UPSTREAM {
+			neighbour = edge.From.Name
+		}
+		if !included[neighbour] {
+			hidden[neighbour] = true
+		}
+	}
+	return len(hidden)
+}
+
+// drawEdge - draws the dependency, ports (services) are only referenced on applications that are not collapsed
+func drawEdge(edge *model.GraphEdge, fromCollapsed bool, toCollapsed bool) string {
+	from := "\"" + edge.From.Name + "\""
+	if edge.SourceService != "" && !fromCollapsed {
+		from += ":\"" + edge.SourceService + "\""
+	}
+	to := getGraphVizReference(edge.Dependency)
+	if toCollapsed {
+		to = "\"" + edge.TargetName + "\""
+	}
+	return from + "->" + to + getEdgeLayoutFromDependency(edge.Dependency, edge.From.Display) + "\n"
+}
+
+func (ProjectDrawer *ProjectDrawer) drawComponentOutgoingRelations(Component *model.Application, hidePlanned bool) string {
+	result := ""
+	for _, edge := range ProjectDrawer.graph.OutgoingEdges(Component.Name) {
+		if edge.Status == model.STATUS_PLANNED && hidePlanned {
+			continue
+		}
+		if edge.To != nil && edge.To.Status == model.STATUS_PLANNED && hidePlanned {
+			continue
+		}
+		if edge.SourceService == "" {
+			// Relation from components
+			result += "\"" + Component.Name + "\" ->" + getGraphVizReference(edge.Dependency) + getEdgeLayoutFromDependency(edge.Dependency, Component.Display) + "\n"
+		} else {
+			// Relation from components/interfaces
+			result += "\"" + Component.Name + "\":\"" + edge.SourceService + "\"->" + getGraphVizReference(edge.Dependency) + getEdgeLayoutFromDependency(edge.Dependency, Component.Display) + "\n"
 		}
 	}
 	return result
@@ -142,15 +234,15 @@ func getEdgeLayoutFromDependency(dependency model.Dependency, display model.Appl
 		edgeLayout += "color=\"#333333\""
 	}
 	edgeLayout += ", fontsize=\"10\", fontcolor=\"#555555\" "
-	if dependency.Relationship == "acl" {
+	if dependency.Relationship == model.RELATIONSHIP_ACL {
 		edgeLayout += ", dir=both, arrowtail=\"box\", taillabel=<<font color=\"red\" ><b>acl</b></font>>"
 	} else {
-		edgeLayout += ", label=\"" + dependency.Relationship + "\""
+		edgeLayout += ", label=\"" + string(dependency.Relationship) + "\""
 	}
-	if dependency.Relationship == "customer-supplier" {
+	if dependency.Relationship == model.RELATIONSHIP_CUSTOMER_SUPPLIER {
 		edgeLayout += ", weight=2"
 	}
-	if dependency.Relationship == "conformist" || dependency.Relationship == "partnership" {
+	if dependency.Relationship == model.RELATIONSHIP_CONFORMIST || dependency.Relationship == model.RELATIONSHIP_PARTNERSHIP {
 		edgeLayout += ", weight=3"
 	}
 
@@ -170,6 +262,7 @@ func getEdgeLayoutFromDependency(dependency model.Dependency, display model.Appl
 func CreateProjectDrawer(Project *model.Project, iconPath string) *ProjectDrawer {
 	var Drawer ProjectDrawer
 	Drawer.originalProject = Project
+	Drawer.graph = Project.Graph()
 	Drawer.iconPath = iconPath
 	return &Drawer
 }
@@ -5,6 +5,7 @@ import (
 
 	"strings"
 
+	"github.com/AOEpeople/vistecture/v2/model/analyze"
 	"github.com/AOEpeople/vistecture/v2/model/core"
 )
 
@@ -43,3 +44,58 @@ func TestApplicationDrawer_Draw(t *testing.T) {
 		t.Error("Graph contains no core app3", graph)
 	}
 }
+
+func TestPathDrawer_Draw(t *testing.T) {
+	project := core.Project{
+		Applications: []*core.Application{
+			{Name: "app1", Dependencies: []core.Dependency{{Reference: "app2.api", Relationship: core.RELATIONSHIP_ACL}, {Reference: "app3"}}},
+			{Name: "app2", ProvidedServices: []core.Service{{Name: "api"}}, Dependencies: []core.Dependency{{Reference: "app3"}}},
+			{Name: "app3"},
+		},
+	}
+	var analyzer analyze.ProjectAnalyzer
+	report, err := analyzer.FindPaths(&project, "app1", "app3", 0)
+	if err != nil {
+		t.Fatal(err)
+	}
+	graph := CreatePathDrawer(report).Draw()
+	if !strings.Contains(graph, `"app1" -> "app3" [label="", color="#c62828", fontcolor="#c62828", penwidth=3];`) {
+		t.Error("Expected the shortest path to be highlighted", graph)
+	}
+	if !strings.Contains(graph, `"app1" -> "app2" [label="api (acl)"];`) {
+		t.Error("Expected the hop labeled with service and relationship", graph)
+	}
+}
+
+func TestProjectDrawer_DrawNeighbourhood(t *testing.T) {
+	project := core.Project{
+		Applications: []*core.Application{
+			{Name: "frontend", Dependencies: []core.Dependency{{Reference: "shop"}}},
+			{Name: "shop", Dependencies: []core.Dependency{{Reference: "orders.api"}, {Reference: "search"}}},
+			{Name: "orders", ProvidedServices: []core.Service{{Name: "api"}}, Dependencies: []core.Dependency{{Reference: "search"}, {Reference: "payment"}}},
+			{Name: "search", InfrastructureDependencies: []core.InfrastructureDependency{{Type: "elasticsearch"}}},
+			{Name: "payment", Dependencies: []core.Dependency{{Reference: "psp"}, {Reference: "fraud"}}},
+			{Name: "psp"},
+			{Name: "fraud"},
+		},
+	}
+	drawer := CreateProjectDrawer(&project, "")
+	graph := drawer.DrawNeighbourhood(project.Applications[1], 0, 2, false)
+
+	for _, expected := range []string{
+		"\"shop\"->\"orders\":\"api\"",
+		"\"orders\"->\"search\"",
+		"\"payment\" [shape=box, style=\"dashed,rounded\"",
+		"PAYMENT\\n+2 more",
+		"\"elasticsearch\"->\"search\"",
+	} {
+		if !strings.Contains(graph, expected) {
+			t.Errorf("Expected %q in graph:\n%v", expected, graph)
+		}
+	}
+	for _, unexpected := range []string{"\"frontend\"", "\"psp\"", "\"fraud\""} {
+		if strings.Contains(graph, unexpected) {
+			t.Errorf("Expected no %v in graph:\n%v", unexpected, graph)
+		}
+	}
+}
@@ -0,0 +1,44 @@
+package graphviz
+
+import (
+	"bytes"
+	"context"
+	"errors"
+	"fmt"
+	"os/exec"
+	"strings"
+)
+
+const (
+	//DOT_COMMAND - the graphviz command that renders the graphs
+	DOT_COMMAND = "dot"
+
+	FORMAT_DOT = "dot"
+	FORMAT_SVG = "svg"
+	FORMAT_PNG = "png"
+)
+
+//IsRendererAvailable - true if the dot command is installed
+func IsRendererAvailable() bool {
+	_, err := exec.LookPath(DOT_COMMAND)
+	return err == nil
+}
+
+//Render - renders the graph (DOT format) with the dot command as svg or png. The dot process is killed when the context is done
+func Render(ctx context.Context, graph string, format string) ([]byte, error) {
+	if format != FORMAT_SVG && format != FORMAT_PNG {
+		return nil, errors.New(fmt.Sprintf("Cannot render format '%v' - use %v or %v", format, FORMAT_SVG, FORMAT_PNG))
+	}
+	dot := exec.CommandContext(ctx, DOT_COMMAND, "-T"+format)
+	var stdout, stderr bytes.Buffer
+	dot.Stdin = strings.NewReader(graph)
+	dot.Stdout = &stdout
+	dot.Stderr = &stderr
+	if err := dot.Run(); err != nil {
+		if ctx.Err() != nil {
+			return nil, errors.New(fmt.Sprintf("Rendering with %v aborted: %v", DOT_COMMAND, ctx.Err()))
+		}
+		return nil, errors.New(fmt.Sprintf("Rendering with %v failed: %v %v", DOT_COMMAND, err, strings.TrimSpace(stderr.String())))
+	}
+	return stdout.Bytes(), nil
+}
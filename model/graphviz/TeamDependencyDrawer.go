@@ -14,7 +14,7 @@ type (
 		summaryRelationOnly bool
 	}
 	OutgoingTeamRelation struct {
-		Relationship   string
+		Relationship   model.Relationship
 		ToTeam         string
 		ForApplication string
 	}
@@ -35,25 +35,25 @@ func (d *TeamDependencyDrawer) DrawComplete() string {
 	teamOutgoing := make(map[string][]OutgoingTeamRelation)
 
 	// Build Graph Infos
+	graph := d.project.Graph()
 	for _, application := range d.project.Applications {
 		if application.Team == "" {
 			continue
 		}
 		teams[application.Team] = append(teams[application.Team], application)
-		dependencies := application.GetAllDependencies()
-		for _, dependency := range dependencies {
-			dependencyApplication, e := dependency.GetApplication(d.project)
-			if e != nil {
+		for _, edge := range graph.OutgoingEdges(application.Name) {
+			dependencyApplication := edge.To
+			if dependencyApplication == nil {
 				continue
 			}
 			if dependencyApplication.Team == application.Team {
 				continue
 			}
 			if dependencyApplication.Team != "" {
-				relationShip := dependency.Relationship
+				relationShip := edge.Relationship
 				if relationShip == "" {
 					if dependencyApplication.IsOpenHostApp() {
-						relationShip = "open-host"
+						relationShip = model.RELATIONSHIP_OPEN_HOST
 					}
 				}
 				referenceToApplicationAlreadyPResent := false
@@ -91,7 +91,7 @@ func (d *TeamDependencyDrawer) DrawComplete() string {
 		result = result + d.DrawTeam(team, applications, color) + "\n"
 		if d.summaryRelationOnly {
 			//Draw relation to team only
-			strongestToTeam := make(map[string]string)
+			strongestToTeam := make(map[string]model.Relationship)
 			for _, relation := range teamOutgoing[team] {
 				if currentRelation, ok := strongestToTeam[relation.ToTeam]; ok {
 					if isStrongerRelation(currentRelation, relation.Relationship) {
@@ -104,7 +104,7 @@ func (d *TeamDependencyDrawer) DrawComplete() string {
 			//Draw relation to every application
 			for toTeam, relationshipType := range strongestToTeam {
 				edgeLayout := edgeLayout(relationshipType)
-				edgeLayout += ", label=\"" + relationshipType + "\""
+				edgeLayout += ", label=\"" + string(relationshipType) + "\""
 				result = result + "\"" + team + "\"->\"" + toTeam + "\"[color=\"" + color + "\" " + edgeLayout + "]\n"
 			}
 
@@ -122,18 +122,18 @@ func (d *TeamDependencyDrawer) DrawComplete() string {
 	result = result + "}"
 	return result
 }
-func edgeLayout(relationShipType string) string {
+func edgeLayout(relationShipType model.Relationship) string {
 	edgeLayout := ""
-	if relationShipType == "acl" {
+	if relationShipType == model.RELATIONSHIP_ACL {
 		edgeLayout += ", style=\"dashed\""
 	}
-	if relationShipType == "open-host" {
+	if relationShipType == model.RELATIONSHIP_OPEN_HOST {
 		edgeLayout += ", style=\"dashed\""
 	}
-	if relationShipType == "customer-supplier" {
+	if relationShipType == model.RELATIONSHIP_CUSTOMER_SUPPLIER {
 		edgeLayout += ", weight=2, style=\"bold\""
 	}
-	if relationShipType == "conformist" || relationShipType == "partnership" {
+	if relationShipType == model.RELATIONSHIP_CONFORMIST || relationShipType == model.RELATIONSHIP_PARTNERSHIP {
 		edgeLayout += ", weight=3, style=\"bold\""
 	}
 
@@ -167,27 +167,6 @@ func (d *TeamDependencyDrawer) DrawTeam(team string, applications []*model.Appli
 	return result
 }
 
-func isStrongerRelation(current string, toCheck string) bool {
-	if relationTypeWeight(current) < relationTypeWeight(toCheck) {
-		return true
-	}
-	return false
-}
-
-func relationTypeWeight(current string) int {
-	if current == "acl" {
-		return 0
-	}
-	if current == "customer-supplier" {
-		return 2
-	}
-
-	if current == "conformist" {
-		return 3
-	}
-
-	if current == "partnership" {
-		return 4
-	}
-	return 1
+func isStrongerRelation(current model.Relationship, toCheck model.Relationship) bool {
+	return current.Weight() < toCheck.Weight()
 }
@@ -15,7 +15,7 @@ type (
 		summaryRelationOnly bool
 	}
 	groupRelation struct {
-		Relationship    string
+		Relationship    model.Relationship
 		ToGroup         string
 		ForApplication  string
 		FromApplication string
@@ -36,6 +36,7 @@ func (d *GroupDrawer) DrawComplete() string {
 	groupOutgoing := make(map[string][]groupRelation)
 
 	// Build Graph Infos
+	graph := d.project.Graph()
 	for _, application := range d.project.Applications {
 		groupName := application.GetMainGroup()
 		if groupName == "" {
@@ -43,10 +44,9 @@ func (d *GroupDrawer) DrawComplete() string {
 		}
 
 		groups[groupName] = append(groups[groupName], application)
-		dependencies := application.GetAllDependencies()
-		for _, dependency := range dependencies {
-			dependencyApplication, e := dependency.GetApplication(d.project)
-			if e != nil {
+		for _, edge := range graph.OutgoingEdges(application.Name) {
+			dependencyApplication := edge.To
+			if dependencyApplication == nil {
 				continue
 			}
 			depGroupName := dependencyApplication.GetMainGroup()
@@ -57,10 +57,10 @@ func (d *GroupDrawer) DrawComplete() string {
 				continue
 			}
 
-			relationShip := dependency.Relationship
+			relationShip := edge.Relationship
 			if relationShip == "" {
 				if dependencyApplication.IsOpenHostApp() {
-					relationShip = "open-host"
+					relationShip = model.RELATIONSHIP_OPEN_HOST
 				}
 			}
 			referenceToApplicationAlreadyPresent := false
@@ -99,7 +99,7 @@ func (d *GroupDrawer) DrawComplete() string {
 		result = result + d.DrawGroup(group, applications, color) + "\n"
 		if d.summaryRelationOnly {
 			//Draw relation to team only
-			strongestToGroup := make(map[string]string)
+			strongestToGroup := make(map[string]model.Relationship)
 			for _, relation := range groupOutgoing[group] {
 				if currentRelation, ok := strongestToGroup[relation.ToGroup]; ok {
 					if isStrongerRelation(currentRelation, relation.Relationship) {
@@ -112,7 +112,7 @@ func (d *GroupDrawer) DrawComplete() string {
 			//Draw relation to every application
 			for toGroup, relationshipType := range strongestToGroup {
 				edgeLayout := edgeLayout(relationshipType)
-				edgeLayout += ", label=\"" + relationshipType + "\""
+				edgeLayout += ", label=\"" + string(relationshipType) + "\""
 				result = result + "\"" + group + "\"->\"" + toGroup + "\"[color=\"" + color + "\" " + edgeLayout + "]\n"
 			}
 
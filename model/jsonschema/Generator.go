@@ -0,0 +1,125 @@
+package jsonschema
+
+import (
+	"reflect"
+	"strings"
+)
+
+type (
+	//Generator - creates schemas from go structs. The property names are taken from the yaml tags, because the definition files are yaml files
+	Generator struct {
+		//Enums - allowed values of string fields. The key is "<struct name>.<property name>", e.g. "Dependency.relationship"
+		Enums map[string][]string
+		//Required - required properties per struct name
+		Required map[string][]string
+		//definitions - schemas of all structs that have been referenced so far
+		definitions map[string]*Schema
+	}
+)
+
+//CreateGenerator - factory for a generator with the given enums and required properties (both may be nil)
+func CreateGenerator(enums map[string][]string, required map[string][]string) *Generator {
+	return &Generator{
+		Enums:       enums,
+		Required:    required,
+		definitions: make(map[string]*Schema),
+	}
+}
+
+//Reference - returns a "$ref" to the definition of the struct of the given value (the definition is generated if needed)
+func (g *Generator) Reference(value interface{}) *Schema {
+	return g.schemaForType(reflect.TypeOf(value))
+}
+
+//Document - turns the given schema into a standalone schema document that contains all definitions referenced so far
+func (g *Generator) Document(title string, root *Schema) *Schema {
+	//keywords next to a "$ref" are ignored - so a referenced root definition is copied into the document
+	if definition, found := g.definitions[strings.TrimPrefix(root.Ref, definitionsPrefix)]; root.Ref != "" && found {
+		root = definition
+	}
+	document := *root
+	document.Schema = DRAFT_07
+	document.Title = title
+	document.Definitions = make(map[string]*Schema)
+	for name, definition := range g.definitions {
+		document.Definitions[name] = definition
+	}
+	return &document
+}
+
+func (g *Generator) schemaForType(t reflect.Type) *Schema {
+	for t.Kind() == reflect.Ptr {
+		t = t.Elem()
+	}
+	switch t.Kind() {
+	case reflect.String:
+		return &Schema{Type: TYPE_STRING}
+	case reflect.Bool:
+		return &Schema{Type: TYPE_BOOLEAN}
+	case reflect.Int, reflect.Int8, reflect.Int16, reflect.Int32, reflect.Int64, reflect.Uint, reflect.Uint8, reflect.Uint16, reflect.Uint32, reflect.Uint64:
+		return &Schema{Type: TYPE_INTEGER}
+	case reflect.Float32, reflect.Float64:
+		return &Schema{Type: TYPE_NUMBER}
+	case reflect.Slice, reflect.Array:
+		return &Schema{Type: TYPE_ARRAY, Items: g.schemaForType(t.Elem())}
+	case reflect.Map:
+		return &Schema{Type: TYPE_OBJECT, AdditionalProperties: g.schemaForType(t.Elem())}
+	case reflect.Struct:
+		if _, found := g.definitions[t.Name()]; !found {
+			//register first, so that recursive structs end up in a reference
+			g.definitions[t.Name()] = &Schema{}
+			*g.definitions[t.Name()] = *g.structSchema(t)
+		}
+		return &Schema{Ref: definitionsPrefix + t.Name()}
+	}
+	//interfaces and everything else: any value
+	return &Schema{}
+}
+
+func (g *Generator) structSchema(t reflect.Type) *Schema {
+	schema := &Schema{
+		Type:                 TYPE_OBJECT,
+		Properties:           make(map[string]*Schema),
+		AdditionalProperties: false,
+		Required:             g.Required[t.Name()],
+	}
+	for i := 0; i < t.NumField(); i++ {
+		field := t.Field(i)
+		if field.PkgPath != "" {
+			continue
+		}
+		name, inline := yamlName(field)
+		if name == "-" {
+			continue
+		}
+		if inline {
+			inlined := g.structSchema(field.Type)
+			for key, property := range inlined.Properties {
+				schema.Properties[key] = property
+			}
+			continue
+		}
+		property := g.schemaForType(field.Type)
+		if enum, found := g.Enums[t.Name()+"."+name]; found {
+			property.Enum = enum
+		}
+		schema.Properties[name] = property
+	}
+	return schema
+}
+
+// yamlName - the key yaml uses for the field (same rules as gopkg.in/yaml)
+func yamlName(field reflect.StructField) (string, bool) {
+	tag := field.Tag.Get("yaml")
+	parts := strings.Split(tag, ",")
+	inline := false
+	for _, flag := range parts[1:] {
+		if flag == "inline" {
+			inline = true
+		}
+	}
+	if parts[0] != "" {
+		return parts[0], inline
+	}
+	return strings.ToLower(field.Name), inline
+}
@@ -0,0 +1,56 @@
+package jsonschema
+
+import (
+	"encoding/json"
+	"io"
+	"strings"
+)
+
+type (
+	//Schema - the subset of JSON Schema (draft-07) that is needed to describe the definition files
+	Schema struct {
+		Schema      string `json:"$schema,omitempty"`
+		Ref         string `json:"$ref,omitempty"`
+		Title       string `json:"title,omitempty"`
+		Description string `json:"description,omitempty"`
+		Type        string `json:"type,omitempty"`
+		//Properties - the known keys of an object
+		Properties map[string]*Schema `json:"properties,omitempty"`
+		//AdditionalProperties - false or the schema of the values of unknown keys
+		AdditionalProperties interface{} `json:"additionalProperties,omitempty"`
+		Required             []string    `json:"required,omitempty"`
+		Items                *Schema     `json:"items,omitempty"`
+		Enum                 []string    `json:"enum,omitempty"`
+		AnyOf                []*Schema   `json:"anyOf,omitempty"`
+		//Definitions - the named schemas referenced with "#/definitions/<name>" (only set on the document)
+		Definitions map[string]*Schema `json:"definitions,omitempty"`
+	}
+)
+
+const (
+	TYPE_OBJECT  = "object"
+	TYPE_ARRAY   = "array"
+	TYPE_STRING  = "string"
+	TYPE_BOOLEAN = "boolean"
+	TYPE_INTEGER = "integer"
+	TYPE_NUMBER  = "number"
+
+	DRAFT_07 = "http://json-schema.org/draft-07/schema#"
+
+	definitionsPrefix = "#/definitions/"
+)
+
+//Write - writes the schema as indented JSON
+func (s *Schema) Write(w io.Writer) error {
+	encoder := json.NewEncoder(w)
+	encoder.SetIndent("", "  ")
+	return encoder.Encode(s)
+}
+
+//Definition - returns the definition a "$ref" points to (nil if unknown)
+func (s *Schema) Definition(ref string) *Schema {
+	if !strings.HasPrefix(ref, definitionsPrefix) {
+		return nil
+	}
+	return s.Definitions[strings.TrimPrefix(ref, definitionsPrefix)]
+}
@@ -0,0 +1,79 @@
+package jsonschema
+
+import (
+	"strings"
+	"testing"
+
+	yamlv3 "gopkg.in/yaml.v3"
+)
+
+type (
+	testApplication struct {
+		Name         string            `yaml:"name"`
+		Status       string            `yaml:"status,omitempty"`
+		IsPublic     bool              `yaml:"isPublic"`
+		Dependencies []testDependency  `yaml:"dependencies"`
+		Properties   map[string]string `yaml:"properties"`
+		Internal     string            `yaml:"-"`
+	}
+
+	testDependency struct {
+		Reference string `yaml:"reference"`
+	}
+)
+
+func TestGenerator_Reference(t *testing.T) {
+	generator := CreateGenerator(map[string][]string{"testApplication.status": {"planned"}}, map[string][]string{"testApplication": {"name"}})
+	schema := generator.Document("test", generator.Reference(testApplication{}))
+
+	if schema.Type != TYPE_OBJECT || schema.Schema != DRAFT_07 {
+		t.Error("Expected the referenced root definition to be copied into the document", schema)
+	}
+	if _, found := schema.Properties["Internal"]; found {
+		t.Error("Expected fields with yaml tag '-' to be skipped")
+	}
+	if schema.Properties["status"].Enum[0] != "planned" {
+		t.Error("Expected enum for status", schema.Properties["status"])
+	}
+	if schema.Properties["dependencies"].Items.Ref != "#/definitions/testDependency" || schema.Definition("#/definitions/testDependency") == nil {
+		t.Error("Expected dependencies to reference the testDependency definition", schema.Properties["dependencies"])
+	}
+}
+
+func TestSchema_Validate(t *testing.T) {
+	generator := CreateGenerator(map[string][]string{"testApplication.status": {"planned"}}, map[string][]string{"testApplication": {"name"}})
+	schema := generator.Document("test", generator.Reference(testApplication{}))
+
+	var valid yamlv3.Node
+	if err := yamlv3.Unmarshal([]byte("name: app1\nstatus: planned\nisPublic: true\ndependencies:\n- reference: app2\nproperties:\n  key: value\n  port: 8080\n  enabled: true\n  version: 1.8\n"), &valid); err != nil {
+		t.Fatal(err)
+	}
+	if violations := schema.Validate(&valid); len(violations) != 0 {
+		t.Error("Expected no violations", violations)
+	}
+
+	var invalid yamlv3.Node
+	if err := yamlv3.Unmarshal([]byte("status: unknown\nisPublic: yes please\ndependencies:\n- reference: app2\n  relationship: acl\nproperties:\n  key: [value]\n"), &invalid); err != nil {
+		t.Fatal(err)
+	}
+	violations := schema.Validate(&invalid)
+	var messages []string
+	for _, violation := range violations {
+		messages = append(messages, violation.Error())
+	}
+	if len(violations) != 5 {
+		t.Fatal("Expected 5 violations", messages)
+	}
+	if violations[0].Line != 1 || !strings.Contains(messages[0], "status: value 'unknown' is not allowed") {
+		t.Error("Expected invalid status in line 1", messages[0])
+	}
+	if violations[2].Line != 5 || messages[2] != "dependencies[0].relationship: unknown property" {
+		t.Error("Expected unknown property in line 5", messages[2])
+	}
+	if messages[3] != "properties.key: expected a string" {
+		t.Error("Expected a list to be no string", messages[3])
+	}
+	if !strings.Contains(messages[4], "missing required property 'name'") {
+		t.Error("Expected missing name", messages[4])
+	}
+}
@@ -0,0 +1,164 @@
+package jsonschema
+
+import (
+	"fmt"
+	"strings"
+
+	yamlv3 "gopkg.in/yaml.v3"
+)
+
+type (
+	//Violation - a place in a yaml document that does not match the schema
+	Violation struct {
+		//Path - the location in the document, e.g. "provided-services[0].type"
+		Path    string
+		Line    int
+		Message string
+	}
+
+	validator struct {
+		document   *Schema
+		violations []*Violation
+	}
+)
+
+//Error - the message prefixed with the path
+func (v *Violation) Error() string {
+	if v.Path == "" {
+		return v.Message
+	}
+	return v.Path + ": " + v.Message
+}
+
+//Validate - validates the parsed yaml document (or its root node) against the schema document
+func (s *Schema) Validate(node *yamlv3.Node) []*Violation {
+	if node != nil && node.Kind == yamlv3.DocumentNode {
+		if len(node.Content) == 0 {
+			return nil
+		}
+		node = node.Content[0]
+	}
+	v := &validator{document: s}
+	v.validate(s, node, "")
+	return v.violations
+}
+
+func (v *validator) validate(schema *Schema, node *yamlv3.Node, path string) {
+	if schema == nil || node == nil {
+		return
+	}
+	if node.Kind == yamlv3.AliasNode && node.Alias != nil {
+		node = node.Alias
+	}
+	//unset values are not validated - the loader accepts them for every field
+	if node.Kind == yamlv3.ScalarNode && node.Tag == "!!null" {
+		return
+	}
+	if schema.Ref != "" {
+		definition := v.document.Definition(schema.Ref)
+		if definition == nil {
+			v.add(node, path, fmt.Sprintf("unknown schema reference %v", schema.Ref))
+			return
+		}
+		v.validate(definition, node, path)
+		return
+	}
+	if len(schema.AnyOf) > 0 {
+		v.validateAnyOf(schema.AnyOf, node, path)
+	}
+
+	switch schema.Type {
+	case TYPE_OBJECT:
+		v.validateObject(schema, node, path)
+	case TYPE_ARRAY:
+		if node.Kind != yamlv3.SequenceNode {
+			v.add(node, path, "expected a list")
+			return
+		}
+		for i, item := range node.Content {
+			v.validate(schema.Items, item, fmt.Sprintf("%v[%d]", path, i))
+		}
+	case TYPE_STRING:
+		//the loader (yaml.v2) reads every scalar into strings, e.g. "technology: 1.8" or "port: 8080"
+		v.validateScalar(node, path, "a string", "!!str", "!!int", "!!float", "!!bool", "!!timestamp")
+	case TYPE_BOOLEAN:
+		v.validateScalar(node, path, "a boolean", "!!bool")
+	case TYPE_INTEGER:
+		v.validateScalar(node, path, "an integer", "!!int")
+	case TYPE_NUMBER:
+		v.validateScalar(node, path, "a number", "!!int", "!!float")
+	}
+
+	if len(schema.Enum) > 0 && node.Kind == yamlv3.ScalarNode && !contains(schema.Enum, node.Value) {
+		v.add(node, path, fmt.Sprintf("value '%v' is not allowed - use one of: %v", node.Value, strings.Join(schema.Enum, ", ")))
+	}
+}
+
+// validateAnyOf - valid if one of the schemas matches. Otherwise the violations of the best matching schema are reported
+func (v *validator) validateAnyOf(schemas []*Schema, node *yamlv3.Node, path string) {
+	var best []*Violation
+	for i, schema := range schemas {
+		candidate := &validator{document: v.document}
+		candidate.validate(schema, node, path)
+		if len(candidate.violations) == 0 {
+			return
+		}
+		if i == 0 || len(candidate.violations) < len(best) {
+			best = candidate.violations
+		}
+	}
+	v.violations = append(v.violations, best...)
+}
+
+func (v *validator) validateObject(schema *Schema, node *yamlv3.Node, path string) {
+	if node.Kind != yamlv3.MappingNode {
+		v.add(node, path, "expected an object")
+		return
+	}
+	found := make(map[string]bool)
+	for i := 0; i+1 < len(node.Content); i += 2 {
+		key := node.Content[i]
+		value := node.Content[i+1]
+		found[key.Value] = true
+		propertyPath := key.Value
+		if path != "" {
+			propertyPath = path + "." + key.Value
+		}
+		if property, known := schema.Properties[key.Value]; known {
+			v.validate(property, value, propertyPath)
+			continue
+		}
+		switch additional := schema.AdditionalProperties.(type) {
+		case *Schema:
+			v.validate(additional, value, propertyPath)
+		case bool:
+			if !additional {
+				v.add(key, propertyPath, "unknown property")
+			}
+		}
+	}
+	for _, required := range schema.Required {
+		if !found[required] {
+			v.add(node, path, fmt.Sprintf("missing required property '%v'", required))
+		}
+	}
+}
+
+func (v *validator) validateScalar(node *yamlv3.Node, path string, expected string, tags ...string) {
+	if node.Kind != yamlv3.ScalarNode || !contains(tags, node.Tag) {
+		v.add(node, path, "expected "+expected)
+	}
+}
+
+func (v *validator) add(node *yamlv3.Node, path string, message string) {
+	v.violations = append(v.violations, &Violation{Path: path, Line: node.Line, Message: message})
+}
+
+func contains(values []string, value string) bool {
+	for _, v := range values {
+		if v == value {
+			return true
+		}
+	}
+	return false
+}
@@ -0,0 +1,277 @@
+package kubernetes
+
+import (
+	"fmt"
+	"strings"
+	"unicode"
+
+	"github.com/AOEpeople/vistecture/v2/model/core"
+)
+
+type (
+	//Importer - proposes applications for the workloads (Deployments, StatefulSets and CronJobs) of Kubernetes manifests.
+	//Services and Ingresses selecting a workload become its provided services, workloads and containers running known infrastructure images become infrastructure dependencies
+	Importer struct {
+		objects []*Object
+	}
+)
+
+const (
+	PROPERTY_KIND      = "kubernetes-kind"
+	PROPERTY_NAMESPACE = "kubernetes-namespace"
+)
+
+//ignoredAnnotations - annotations maintained by tools that are not useful as properties
+var ignoredAnnotations = []string{"kubectl.kubernetes.io/last-applied-configuration", "deployment.kubernetes.io/revision"}
+
+//CreateImporter - Factory
+func CreateImporter(objects []*Object) *Importer {
+	return &Importer{objects: objects}
+}
+
+//Applications - returns an application per workload that does not run a known infrastructure image.
+//Workloads with the same name in different namespaces (or of different kinds) are named "<name>-<namespace>" (or "<name>-<kind>")
+func (i *Importer) Applications() []*core.Application {
+	// hosts under which the infrastructure workloads are reachable (workload and service names)
+	infrastructureHosts := make(map[string]string)
+	for _, workload := range i.workloads() {
+		if infrastructureType := core.InfrastructureTypeOfImage(mainImage(workload)); infrastructureType != "" {
+			infrastructureHosts[workload.Metadata.Name] = infrastructureType
+			for _, service := range i.servicesSelecting(workload) {
+				infrastructureHosts[service.Metadata.Name] = infrastructureType
+			}
+		}
+	}
+
+	var workloads []*Object
+	for _, workload := range i.workloads() {
+		if core.InfrastructureTypeOfImage(mainImage(workload)) == "" {
+			workloads = append(workloads, workload)
+		}
+	}
+	names := applicationNames(workloads)
+	var applications []*core.Application
+	for _, workload := range workloads {
+		applications = append(applications, i.application(names[workload], workload, infrastructureHosts))
+	}
+	return applications
+}
+
+func (i *Importer) application(name string, workload *Object, infrastructureHosts map[string]string) *core.Application {
+	application := &core.Application{
+		Name:       name,
+		Properties: properties(workload),
+	}
+
+	for _, service := range i.servicesSelecting(workload) {
+		for _, port := range service.Spec.Ports {
+			name := service.Metadata.Name
+			if len(service.Spec.Ports) > 1 {
+				name += "-" + portName(port)
+			}
+			application.AddProvidedService(core.Service{
+				Name:    name,
+				Type:    core.SERVICE_TYPE_API,
+				Summary: fmt.Sprintf("Kubernetes service %v port %v", service.Metadata.Name, port.Port),
+			})
+		}
+		for _, ingress := range i.ingressesRoutingTo(service) {
+			for _, rule := range ingress.Spec.Rules {
+				for _, path := range rule.Http.Paths {
+					if backendName(path.Backend.Service.Name, path.Backend.ServiceName) != service.Metadata.Name {
+						continue
+					}
+					application.AddProvidedService(ingressService(ingress, rule.Host, path.Path))
+				}
+			}
+		}
+	}
+
+	template := workload.PodTemplate()
+	containers := append(append([]Container(nil), template.Spec.Containers...), template.Spec.InitContainers...)
+	for index, container := range containers {
+		// the first container is the application itself - all others may be infrastructure sidecars
+		if index > 0 {
+			application.AddInfrastructureDependency(core.InfrastructureTypeOfImage(container.Image))
+		}
+		for _, env := range container.Env {
+			for _, host := range hostsIn(env.Value) {
+				application.AddInfrastructureDependency(infrastructureHosts[host])
+			}
+		}
+	}
+	return application
+}
+
+// applicationNames - the names of the workloads, qualified with the namespace and the kind if other workloads have the same name
+func applicationNames(workloads []*Object) map[*Object]string {
+	byName := make(map[string][]*Object)
+	for _, workload := range workloads {
+		byName[workload.Metadata.Name] = append(byName[workload.Metadata.Name], workload)
+	}
+	names := make(map[*Object]string)
+	for _, workload := range workloads {
+		name := workload.Metadata.Name
+		otherNamespace, sameNamespace := false, false
+		for _, other := range byName[workload.Metadata.Name] {
+			if other == workload {
+				continue
+			}
+			if other.Metadata.Namespace != workload.Metadata.Namespace {
+				otherNamespace = true
+			} else {
+				sameNamespace = true
+			}
+		}
+		if otherNamespace {
+			name += "-" + namespace(workload)
+		}
+		if sameNamespace {
+			name += "-" + strings.ToLower(workload.Kind)
+		}
+		names[workload] = name
+	}
+	return names
+}
+
+// namespace - the namespace of the object, objects without namespace are applied to the default namespace
+func namespace(object *Object) string {
+	if object.Metadata.Namespace == "" {
+		return "default"
+	}
+	return object.Metadata.Namespace
+}
+
+func (i *Importer) workloads() []*Object {
+	var workloads []*Object
+	for _, object := range i.objects {
+		if object.PodTemplate() != nil {
+			workloads = append(workloads, object)
+		}
+	}
+	return workloads
+}
+
+// servicesSelecting - the Services of the namespace whose selector matches the pod labels of the workload
+func (i *Importer) servicesSelecting(workload *Object) []*Object {
+	var services []*Object
+	podLabels := workload.PodTemplate().Metadata.Labels
+	for _, object := range i.objects {
+		if object.Kind != KIND_SERVICE || object.Metadata.Namespace != workload.Metadata.Namespace {
+			continue
+		}
+		selector := object.SelectorLabels()
+		if len(selector) == 0 {
+			continue
+		}
+		matches := true
+		for key, value := range selector {
+			if podLabels[key] != value {
+				matches = false
+			}
+		}
+		if matches {
+			services = append(services, object)
+		}
+	}
+	return services
+}
+
+func (i *Importer) ingressesRoutingTo(service *Object) []*Object {
+	var ingresses []*Object
+	for _, object := range i.objects {
+		if object.Kind != KIND_INGRESS || object.Metadata.Namespace != service.Metadata.Namespace {
+			continue
+		}
+	rules:
+		for _, rule := range object.Spec.Rules {
+			for _, path := range rule.Http.Paths {
+				if backendName(path.Backend.Service.Name, path.Backend.ServiceName) == service.Metadata.Name {
+					ingresses = append(ingresses, object)
+					break rules
+				}
+			}
+		}
+	}
+	return ingresses
+}
+
+// ingressService - a public service named by host and path (dots are not allowed in service names). Hosts or paths starting with "api" are APIs, all others GUIs
+func ingressService(ingress *Object, host string, path string) core.Service {
+	url := host
+	if url == "" {
+		url = ingress.Metadata.Name
+	}
+	if path != "" && path != "/" {
+		url += strings.TrimSuffix(path, "/")
+	}
+	serviceType := core.SERVICE_TYPE_GUI
+	if strings.HasPrefix(host, "api.") || strings.HasPrefix(host, "api-") || strings.HasPrefix(path, "/api") {
+		serviceType = core.SERVICE_TYPE_API
+	}
+	return core.Service{
+		Name:     strings.NewReplacer(".", "-", "/", "-").Replace(url),
+		Type:     serviceType,
+		IsPublic: true,
+		Summary:  fmt.Sprintf("Kubernetes ingress %v: %v", ingress.Metadata.Name, url),
+	}
+}
+
+// properties - the labels and annotations of the workload and its pod template (annotations win)
+func properties(workload *Object) map[string]string {
+	result := map[string]string{PROPERTY_KIND: workload.Kind}
+	if workload.Metadata.Namespace != "" {
+		result[PROPERTY_NAMESPACE] = workload.Metadata.Namespace
+	}
+	for _, meta := range []ObjectMeta{workload.PodTemplate().Metadata, workload.Metadata} {
+		for key, value := range meta.Labels {
+			result[key] = value
+		}
+	}
+	for _, meta := range []ObjectMeta{workload.PodTemplate().Metadata, workload.Metadata} {
+		for key, value := range meta.Annotations {
+			if !inSlice(key, ignoredAnnotations) {
+				result[key] = value
+			}
+		}
+	}
+	return result
+}
+
+func mainImage(workload *Object) string {
+	containers := workload.PodTemplate().Spec.Containers
+	if len(containers) == 0 {
+		return ""
+	}
+	return containers[0].Image
+}
+
+// hostsIn - the host like tokens of a value, e.g. "orders-db" for "postgres://orders-db.shop.svc:5432/orders"
+func hostsIn(value string) []string {
+	return strings.FieldsFunc(value, func(r rune) bool {
+		return !(unicode.IsLetter(r) || unicode.IsDigit(r) || r == '-' || r == '_')
+	})
+}
+
+func backendName(name string, legacyName string) string {
+	if name != "" {
+		return name
+	}
+	return legacyName
+}
+
+func portName(port ServicePort) string {
+	if port.Name != "" {
+		return port.Name
+	}
+	return fmt.Sprintf("%v", port.Port)
+}
+
+func inSlice(value string, values []string) bool {
+	for _, v := range values {
+		if v == value {
+			return true
+		}
+	}
+	return false
+}
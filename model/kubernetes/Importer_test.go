@@ -0,0 +1,111 @@
+package kubernetes
+
+import (
+	"reflect"
+	"strings"
+	"testing"
+
+	"github.com/AOEpeople/vistecture/v2/model/core"
+)
+
+func TestImporter_Applications(t *testing.T) {
+	objects, readErrors := ReadObjectsFromPath("fixtures")
+	if len(readErrors) > 0 {
+		t.Fatal(readErrors)
+	}
+	applications := CreateImporter(objects).Applications()
+	if len(applications) != 2 {
+		t.Fatalf("Expected orders and invoice-export (postgres is infrastructure), got %v", applications)
+	}
+
+	orders := applications[0]
+	if orders.Name != "orders" {
+		t.Fatalf("Expected orders, got %v", orders.Name)
+	}
+	expectedServices := []core.Service{
+		{Name: "orders", Type: core.SERVICE_TYPE_API, Summary: "Kubernetes service orders port 80"},
+		{Name: "shop-example-com-orders", Type: core.SERVICE_TYPE_GUI, IsPublic: true, Summary: "Kubernetes ingress shop: shop.example.com/orders"},
+		{Name: "shop-example-com-api-orders", Type: core.SERVICE_TYPE_API, IsPublic: true, Summary: "Kubernetes ingress shop: shop.example.com/api/orders"},
+	}
+	if !reflect.DeepEqual(orders.ProvidedServices, expectedServices) {
+		t.Errorf("Expected services %v, got %v", expectedServices, orders.ProvidedServices)
+	}
+	expectedInfrastructure := []core.InfrastructureDependency{{Type: "postgres"}, {Type: "redis"}}
+	if !reflect.DeepEqual(orders.InfrastructureDependencies, expectedInfrastructure) {
+		t.Errorf("Expected infrastructure %v, got %v", expectedInfrastructure, orders.InfrastructureDependencies)
+	}
+	expectedProperties := map[string]string{
+		"app":                    "orders",
+		"app.kubernetes.io/name": "orders",
+		"team":                   "checkout",
+		PROPERTY_KIND:            KIND_DEPLOYMENT,
+		PROPERTY_NAMESPACE:       "shop",
+	}
+	if !reflect.DeepEqual(orders.Properties, expectedProperties) {
+		t.Errorf("Expected properties %v, got %v", expectedProperties, orders.Properties)
+	}
+
+	if applications[1].Name != "invoice-export" || applications[1].Properties[PROPERTY_KIND] != KIND_CRONJOB {
+		t.Errorf("Expected the cron job from the list, got %#v", applications[1])
+	}
+}
+
+func TestImporter_ApplicationsWithSameName(t *testing.T) {
+	objects, err := ReadObjects(strings.NewReader(`
+apiVersion: apps/v1
+kind: Deployment
+metadata:
+  name: orders
+  namespace: shop
+spec:
+  template:
+    spec:
+      containers:
+      - image: shop/orders
+---
+apiVersion: apps/v1
+kind: Deployment
+metadata:
+  name: orders
+  namespace: staging
+spec:
+  template:
+    spec:
+      containers:
+      - image: shop/orders
+---
+apiVersion: batch/v1
+kind: CronJob
+metadata:
+  name: orders
+  namespace: staging
+spec:
+  jobTemplate:
+    spec:
+      template:
+        spec:
+          containers:
+          - image: shop/orders-cleanup
+---
+apiVersion: apps/v1
+kind: Deployment
+metadata:
+  name: invoices
+spec:
+  template:
+    spec:
+      containers:
+      - image: shop/invoices
+`))
+	if err != nil {
+		t.Fatal(err)
+	}
+	var names []string
+	for _, application := range CreateImporter(objects).Applications() {
+		names = append(names, application.Name)
+	}
+	expected := []string{"orders-shop", "orders-staging-deployment", "orders-staging-cronjob", "invoices"}
+	if !reflect.DeepEqual(names, expected) {
+		t.Errorf("Expected names %v, got %v", expected, names)
+	}
+}
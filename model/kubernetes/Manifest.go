@@ -0,0 +1,171 @@
+package kubernetes
+
+import (
+	"bytes"
+	"errors"
+	"fmt"
+	"io"
+	"io/ioutil"
+	"os"
+	"path/filepath"
+	"strings"
+
+	"gopkg.in/yaml.v2"
+)
+
+type (
+	//Object - a Kubernetes object from a manifest (only the fields used by the importer of the supported kinds)
+	Object struct {
+		ApiVersion string     `yaml:"apiVersion"`
+		Kind       string     `yaml:"kind"`
+		Metadata   ObjectMeta `yaml:"metadata"`
+		Spec       ObjectSpec `yaml:"spec"`
+		//Items - the objects of a List
+		Items []*Object `yaml:"items"`
+	}
+
+	ObjectMeta struct {
+		Name        string            `yaml:"name"`
+		Namespace   string            `yaml:"namespace"`
+		Labels      map[string]string `yaml:"labels"`
+		Annotations map[string]string `yaml:"annotations"`
+	}
+
+	//ObjectSpec - union of the specs of Deployments, StatefulSets, CronJobs, Services and Ingresses
+	ObjectSpec struct {
+		//Template - pod template of Deployments and StatefulSets
+		Template *PodTemplate `yaml:"template"`
+		//JobTemplate - job template of CronJobs
+		JobTemplate *struct {
+			Spec struct {
+				Template *PodTemplate `yaml:"template"`
+			} `yaml:"spec"`
+		} `yaml:"jobTemplate"`
+		//Selector - label selector of Services (the selector of workloads is not needed)
+		Selector interface{}   `yaml:"selector"`
+		Ports    []ServicePort `yaml:"ports"`
+		Rules    []IngressRule `yaml:"rules"`
+	}
+
+	PodTemplate struct {
+		Metadata ObjectMeta `yaml:"metadata"`
+		Spec     struct {
+			Containers     []Container `yaml:"containers"`
+			InitContainers []Container `yaml:"initContainers"`
+		} `yaml:"spec"`
+	}
+
+	Container struct {
+		Name  string `yaml:"name"`
+		Image string `yaml:"image"`
+		Env   []struct {
+			Name  string `yaml:"name"`
+			Value string `yaml:"value"`
+		} `yaml:"env"`
+	}
+
+	ServicePort struct {
+		Name string `yaml:"name"`
+		Port int    `yaml:"port"`
+	}
+
+	IngressRule struct {
+		Host string `yaml:"host"`
+		Http struct {
+			Paths []struct {
+				Path    string `yaml:"path"`
+				Backend struct {
+					//Service - backend of networking.k8s.io/v1
+					Service struct {
+						Name string `yaml:"name"`
+					} `yaml:"service"`
+					//ServiceName - backend of extensions/v1beta1 and networking.k8s.io/v1beta1
+					ServiceName string `yaml:"serviceName"`
+				} `yaml:"backend"`
+			} `yaml:"paths"`
+		} `yaml:"http"`
+	}
+)
+
+const (
+	KIND_DEPLOYMENT  = "Deployment"
+	KIND_STATEFULSET = "StatefulSet"
+	KIND_CRONJOB     = "CronJob"
+	KIND_SERVICE     = "Service"
+	KIND_INGRESS     = "Ingress"
+	KIND_LIST        = "List"
+)
+
+//ReadObjects - reads the objects of a (multi document) manifest. Lists are flattened and documents without kind are skipped
+func ReadObjects(r io.Reader) ([]*Object, error) {
+	var objects []*Object
+	decoder := yaml.NewDecoder(r)
+	for {
+		var object Object
+		err := decoder.Decode(&object)
+		if err == io.EOF {
+			return objects, nil
+		}
+		if err != nil {
+			return objects, err
+		}
+		if object.Kind == KIND_LIST {
+			objects = append(objects, object.Items...)
+		} else if object.Kind != "" {
+			objects = append(objects, &object)
+		}
+	}
+}
+
+//ReadObjectsFromPath - reads the objects of a manifest file or all YAML files in a folder (recursive).
+//Files that can not be parsed (e.g. unrendered Helm templates) are reported as errors but do not stop the reading
+func ReadObjectsFromPath(path string) ([]*Object, []error) {
+	var objects []*Object
+	var readErrors []error
+	err := filepath.Walk(path, func(fileName string, info os.FileInfo, err error) error {
+		if err != nil {
+			return err
+		}
+		if info.IsDir() || !(strings.HasSuffix(fileName, ".yml") || strings.HasSuffix(fileName, ".yaml")) {
+			return nil
+		}
+		content, err := ioutil.ReadFile(fileName)
+		if err != nil {
+			return err
+		}
+		read, err := ReadObjects(bytes.NewReader(content))
+		if err != nil {
+			readErrors = append(readErrors, errors.New(fmt.Sprintf("%v: cannot parse manifest: %v", fileName, err)))
+		}
+		objects = append(objects, read...)
+		return nil
+	})
+	if err != nil {
+		readErrors = append(readErrors, err)
+	}
+	return objects, readErrors
+}
+
+//PodTemplate - the pod template of Deployments, StatefulSets and CronJobs (nil for other kinds)
+func (o *Object) PodTemplate() *PodTemplate {
+	switch o.Kind {
+	case KIND_DEPLOYMENT, KIND_STATEFULSET:
+		return o.Spec.Template
+	case KIND_CRONJOB:
+		if o.Spec.JobTemplate != nil {
+			return o.Spec.JobTemplate.Spec.Template
+		}
+	}
+	return nil
+}
+
+//SelectorLabels - the label selector of a Service
+func (o *Object) SelectorLabels() map[string]string {
+	labels := make(map[string]string)
+	if selector, ok := o.Spec.Selector.(map[interface{}]interface{}); ok {
+		for key, value := range selector {
+			labels[fmt.Sprintf("%v", key)] = fmt.Sprintf("%v", value)
+		}
+	}
+	return labels
+}
@@ -0,0 +1,59 @@
+package mermaid
+
+import (
+	"github.com/AOEpeople/vistecture/v2/model/core"
+)
+
+type (
+	//ClusterDrawer - draws the applications clustered by team or main group as Mermaid subgraphs and the links between the clusters
+	ClusterDrawer struct {
+		project             *core.Project
+		clustering          *core.Clustering
+		summaryRelationOnly bool
+	}
+)
+
+//CreateGroupDrawer - Factory for the flowchart of the main groups
+func CreateGroupDrawer(project *core.Project, summaryRelation bool, hidePlanned bool) *ClusterDrawer {
+	return &ClusterDrawer{project: project, clustering: core.GroupClustering(hidePlanned), summaryRelationOnly: summaryRelation}
+}
+
+//CreateTeamDependencyDrawer - Factory for the flowchart of the teams
+func CreateTeamDependencyDrawer(project *core.Project, summaryRelation bool, hidePlanned bool) *ClusterDrawer {
+	return &ClusterDrawer{project: project, clustering: core.TeamClustering(hidePlanned), summaryRelationOnly: summaryRelation}
+}
+
+//DrawComplete - draws a subgraph per cluster with its applications. The links are drawn between the applications or - with summaryRelation - once per pair of clusters with the strongest relationship
+func (d *ClusterDrawer) DrawComplete() string {
+	graph := d.project.Graph()
+	clusters := d.clustering.Clusters(graph)
+	prefix := d.clustering.Prefix
+
+	chart := newFlowchart()
+	for _, cluster := range clusters {
+		chart.add("", "subgraph "+clusterId(prefix, cluster.Name)+"[\""+escape(cluster.Name)+"\"]")
+		for _, application := range cluster.Applications {
+			chart.node("  ", application, false)
+		}
+		chart.add("", "end")
+	}
+
+	for _, relation := range d.clustering.Relations(graph) {
+		color := clusters.Color(relation.FromCluster)
+		if d.summaryRelationOnly {
+			chart.link(clusterId(prefix, relation.FromCluster), clusterId(prefix, relation.ToCluster), core.Dependency{Relationship: relation.StrongestRelationship()}, color)
+			continue
+		}
+		drawn := make(map[[2]string]bool)
+		for _, edge := range relation.Edges {
+			if drawn[[2]string{edge.From.Name, edge.To.Name}] {
+				continue
+			}
+			drawn[[2]string{edge.From.Name, edge.To.Name}] = true
+			dependency := edge.Dependency
+			dependency.Relationship = edge.EffectiveRelationship()
+			chart.link(applicationId(edge.From.Name), applicationId(edge.To.Name), dependency, color)
+		}
+	}
+	return chart.String()
+}
@@ -0,0 +1,92 @@
+package mermaid
+
+import (
+	"github.com/AOEpeople/vistecture/v2/model/core"
+)
+
+type (
+	//ProjectDrawer - draws the applications of a project as Mermaid flowchart (same views as graphviz.ProjectDrawer)
+	ProjectDrawer struct {
+		project *core.Project
+		graph   *core.Graph
+	}
+)
+
+//CreateProjectDrawer - Factory
+func CreateProjectDrawer(project *core.Project) *ProjectDrawer {
+	return &ProjectDrawer{
+		project: project,
+		graph:   project.Graph(),
+	}
+}
+
+//DrawComplete - draws all applications in nested subgraphs per group and all dependencies between them
+func (d *ProjectDrawer) DrawComplete(hidePlanned bool) string {
+	chart := newFlowchart()
+	d.drawGroup(chart, d.project.GetApplicationsRootGroup(), hidePlanned, "")
+	for _, application := range d.project.Applications {
+		if hidePlanned && application.Status == core.STATUS_PLANNED {
+			continue
+		}
+		for _, edge := range d.graph.OutgoingEdges(application.Name) {
+			if edge.To == nil || (hidePlanned && edge.IsPlanned()) {
+				continue
+			}
+			chart.link(applicationId(application.Name), applicationId(edge.To.Name), edge.Dependency, application.Display.BorderColor)
+		}
+	}
+	return chart.String()
+}
+
+//DrawComponent - draws the application with its direct dependencies, direct dependents and infrastructure dependencies
+func (d *ProjectDrawer) DrawComponent(application *core.Application, hidePlanned bool) string {
+	chart := newFlowchart()
+	chart.node("", application, true)
+
+	drawn := map[string]bool{application.Name: true}
+	edges := append([]*core.GraphEdge(nil), d.graph.OutgoingEdges(application.Name)...)
+	for _, dependent := range d.graph.Dependents(application.Name) {
+		edges = append(edges, d.graph.EdgesBetween(dependent.Name, application.Name)...)
+	}
+	for _, edge := range edges {
+		if edge.To == nil || (hidePlanned && edge.IsPlanned()) {
+			continue
+		}
+		for _, related := range []*core.Application{edge.From, edge.To} {
+			if !drawn[related.Name] {
+				drawn[related.Name] = true
+				chart.node("", related, true)
+			}
+		}
+		chart.link(applicationId(edge.From.Name), applicationId(edge.To.Name), edge.Dependency, edge.From.Display.BorderColor)
+	}
+
+	for _, infrastructureDependency := range application.InfrastructureDependencies {
+		id := clusterId("infra", infrastructureDependency.Type)
+		chart.add("", id+"[(\""+escape(infrastructureDependency.Type)+"\")]")
+		chart.classes["infrastructure"] = append(chart.classes["infrastructure"], id)
+		chart.link(applicationId(application.Name), id, core.Dependency{}, "")
+	}
+	return chart.String()
+}
+
+// drawGroup - draws the applications of the group and recursive its subgroups as nested subgraphs
+func (d *ProjectDrawer) drawGroup(chart *flowchart, appsByGroup *core.ApplicationsByGroup, hidePlanned bool, indent string) {
+	childIndent := indent
+	if !appsByGroup.IsRoot {
+		chart.add(indent, "subgraph "+clusterId("group", appsByGroup.QualifiedGroupName)+"[\""+escape(appsByGroup.GroupName)+"\"]")
+		childIndent = indent + "  "
+	}
+	for _, subGroup := range appsByGroup.SubGroups {
+		d.drawGroup(chart, subGroup, hidePlanned, childIndent)
+	}
+	for _, application := range appsByGroup.Applications {
+		if hidePlanned && application.Status == core.STATUS_PLANNED {
+			continue
+		}
+		chart.node(childIndent, application, true)
+	}
+	if !appsByGroup.IsRoot {
+		chart.add(indent, "end")
+	}
+}
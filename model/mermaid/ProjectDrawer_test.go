@@ -0,0 +1,110 @@
+package mermaid
+
+import (
+	"strings"
+	"testing"
+
+	"github.com/AOEpeople/vistecture/v2/model/core"
+)
+
+func TestProjectDrawer_DrawComplete(t *testing.T) {
+	project := &core.Project{
+		Applications: []*core.Application{
+			{
+				Name:    "order-workflow",
+				Group:   "shop/checkout",
+				Display: core.ApplicationDisplaySettings{BorderColor: "#00ff00"},
+				Dependencies: []core.Dependency{
+					{Reference: "payment", Relationship: core.RELATIONSHIP_ACL},
+					{Reference: "stock.api", Relationship: core.RELATIONSHIP_PARTNERSHIP},
+					{Reference: "stock", IsBrowserBased: true},
+				},
+			},
+			{Name: "stock", Group: "shop", Status: core.STATUS_PLANNED, ProvidedServices: []core.Service{{Name: "api"}}},
+			{Name: "payment \"psp\"", Category: core.CATEGORY_EXTERNAL},
+			{Name: "payment", Category: core.CATEGORY_EXTERNAL},
+		},
+	}
+
+	chart := CreateProjectDrawer(project).DrawComplete(false)
+	for _, expected := range []string{
+		"flowchart LR\n",
+		"  subgraph group_shop[\"shop\"]\n    subgraph group_shop_checkout[\"checkout\"]\n      app_order_workflow[\"order-workflow\"]\n    end\n    app_stock[\"stock\"]\n  end\n",
+		"  app_payment__psp_[\"payment #quot;psp#quot;\"]\n",
+		"  app_order_workflow -->|acl| app_payment\n",
+		"  app_order_workflow ==>|partnership| app_stock\n",
+		"  app_order_workflow -.-> app_stock\n",
+		"  class app_payment__psp_,app_payment external\n  class app_stock planned\n",
+		"  linkStyle 0 stroke:red,color:red\n  linkStyle 1 stroke:#00ff00\n  linkStyle 2 stroke:#00ff00\n",
+	} {
+		if !strings.Contains(chart, expected) {
+			t.Errorf("Expected %q in flowchart:\n%v", expected, chart)
+		}
+	}
+
+	chart = CreateProjectDrawer(project).DrawComplete(true)
+	if strings.Contains(chart, "app_stock") || strings.Contains(chart, "linkStyle 1") {
+		t.Errorf("Expected planned stock and its links to be hidden:\n%v", chart)
+	}
+}
+
+func TestProjectDrawer_DrawComponent(t *testing.T) {
+	project := &core.Project{
+		Applications: []*core.Application{
+			{
+				Name:                       "orders",
+				Technology:                 "go <1.16>",
+				Dependencies:               []core.Dependency{{Reference: "stock"}},
+				InfrastructureDependencies: []core.InfrastructureDependency{{Type: "rdbms"}},
+			},
+			{Name: "stock", Dependencies: []core.Dependency{{Reference: "warehouse"}}},
+			{Name: "shop", Dependencies: []core.Dependency{{Reference: "orders", Status: core.STATUS_PLANNED}}},
+			{Name: "warehouse"},
+		},
+	}
+	chart := CreateProjectDrawer(project).DrawComponent(project.Applications[0], false)
+	for _, expected := range []string{
+		"  app_orders[\"orders<br/><small>go <1.16></small>\"]\n",
+		"  app_orders --> app_stock\n",
+		"  app_shop -.-> app_orders\n",
+		"  infra_rdbms[(\"rdbms\")]\n  app_orders --> infra_rdbms\n",
+		"  class infra_rdbms infrastructure\n",
+	} {
+		if !strings.Contains(chart, expected) {
+			t.Errorf("Expected %q in flowchart:\n%v", expected, chart)
+		}
+	}
+	if strings.Contains(chart, "app_warehouse") {
+		t.Errorf("Expected only direct neighbours in flowchart:\n%v", chart)
+	}
+	if chart = CreateProjectDrawer(project).DrawComponent(project.Applications[0], true); strings.Contains(chart, "app_shop") {
+		t.Errorf("Expected the planned dependency of shop to be hidden:\n%v", chart)
+	}
+}
+
+func TestClusterDrawer_DrawComplete(t *testing.T) {
+	project := &core.Project{
+		Applications: []*core.Application{
+			{Name: "shop", Team: "team a", Dependencies: []core.Dependency{{Reference: "orders", Relationship: core.RELATIONSHIP_CONFORMIST}, {Reference: "stock", Relationship: core.RELATIONSHIP_ACL}}},
+			{Name: "orders", Team: "team-b"},
+			{Name: "stock", Team: "team-b"},
+		},
+	}
+
+	chart := CreateTeamDependencyDrawer(project, true, false).DrawComplete()
+	for _, expected := range []string{
+		"  subgraph team_team_a[\"team a\"]\n    app_shop[\"shop\"]\n  end\n",
+		"  subgraph team_team_b[\"team-b\"]\n    app_orders[\"orders\"]\n    app_stock[\"stock\"]\n  end\n",
+		"  team_team_a ==>|conformist| team_team_b\n",
+		"  linkStyle 0 stroke:" + core.ClusterColors[0] + "\n",
+	} {
+		if !strings.Contains(chart, expected) {
+			t.Errorf("Expected %q in flowchart:\n%v", expected, chart)
+		}
+	}
+
+	chart = CreateTeamDependencyDrawer(project, false, false).DrawComplete()
+	if !strings.Contains(chart, "  app_shop ==>|conformist| app_orders\n  app_shop -->|acl| app_stock\n") || !strings.Contains(chart, "  linkStyle 1 stroke:red,color:red\n") {
+		t.Errorf("Expected the links between the applications:\n%v", chart)
+	}
+}
@@ -0,0 +1,92 @@
+package mermaid
+
+import (
+	"fmt"
+	"regexp"
+	"strings"
+
+	"github.com/AOEpeople/vistecture/v2/model/core"
+)
+
+type (
+	//flowchart - collects nodes, links and styles. Mermaid styles links by their index so the links are counted while adding them
+	flowchart struct {
+		body       string
+		links      int
+		linkStyles string
+		classes    map[string][]string
+	}
+)
+
+const classDefinitions = `  classDef external fill:#F6E0E0,stroke:#8E0909
+  classDef planned fill:#F5F5F5,stroke:#BBBBBB,color:#888888,stroke-dasharray:5 5
+  classDef infrastructure fill:#EEEEEE,stroke:#555555
+`
+
+var idReplacer = regexp.MustCompile(`[^A-Za-z0-9_]`)
+
+func newFlowchart() *flowchart {
+	return &flowchart{classes: make(map[string][]string)}
+}
+
+func (f *flowchart) add(indent string, line string) {
+	f.body += "  " + indent + line + "\n"
+}
+
+//node - adds the application as node and remembers its classes (external and planned)
+func (f *flowchart) node(indent string, application *core.Application, withDetails bool) {
+	label := escape(application.Name)
+	if withDetails && application.Technology != "" {
+		label += "<br/><small>" + escape(application.Technology) + "</small>"
+	}
+	f.add(indent, applicationId(application.Name)+"[\""+label+"\"]")
+	if application.Category == core.CATEGORY_EXTERNAL {
+		f.classes["external"] = append(f.classes["external"], applicationId(application.Name))
+	}
+	if application.Status == core.STATUS_PLANNED {
+		f.classes["planned"] = append(f.classes["planned"], applicationId(application.Name))
+	}
+}
+
+//link - adds a link. Planned and browser based dependencies are dotted, strong relationships thick (same semantics as the graphviz edge layout)
+func (f *flowchart) link(from string, to string, dependency core.Dependency, color string) {
+	arrow := "-->"
+	if dependency.Status == core.STATUS_PLANNED || dependency.IsBrowserBased {
+		arrow = "-.->"
+	} else if dependency.Relationship.Weight() > 1 {
+		arrow = "==>"
+	}
+	if dependency.Relationship != "" {
+		arrow += "|" + escape(string(dependency.Relationship)) + "|"
+	}
+	f.add("", from+" "+arrow+" "+to)
+	if dependency.Relationship == core.RELATIONSHIP_ACL {
+		f.linkStyles += fmt.Sprintf("  linkStyle %v stroke:red,color:red\n", f.links)
+	} else if color != "" {
+		f.linkStyles += fmt.Sprintf("  linkStyle %v stroke:%v\n", f.links, color)
+	}
+	f.links++
+}
+
+func (f *flowchart) String() string {
+	result := "flowchart LR\n" + f.body + classDefinitions
+	for _, class := range []string{"external", "planned", "infrastructure"} {
+		if len(f.classes[class]) > 0 {
+			result += "  class " + strings.Join(f.classes[class], ",") + " " + class + "\n"
+		}
+	}
+	return result + f.linkStyles
+}
+
+func applicationId(name string) string {
+	return "app_" + idReplacer.ReplaceAllString(name, "_")
+}
+
+func clusterId(prefix string, name string) string {
+	return prefix + "_" + idReplacer.ReplaceAllString(name, "_")
+}
+
+// escape - quotes are not allowed in mermaid labels
+func escape(value string) string {
+	return strings.Replace(value, "\"", "#quot;", -1)
+}
@@ -0,0 +1,79 @@
+package plantuml
+
+import (
+	"regexp"
+	"strings"
+
+	"github.com/AOEpeople/vistecture/v2/model/core"
+)
+
+type (
+	//ApplicationDrawer - draws an application as component with its provided services as interfaces
+	ApplicationDrawer struct {
+		application *core.Application
+	}
+)
+
+var aliasReplacer = regexp.MustCompile(`[^A-Za-z0-9_]`)
+
+// Draw - returns the component, the interfaces and the links between them
+func (d ApplicationDrawer) Draw(hidePlanned bool) string {
+	app := d.application
+	label := app.Name
+	if app.Technology != "" {
+		label += " (" + app.Technology + ")"
+	}
+	if app.Title != "" {
+		label += "\\n<size:10>" + app.Title + "</size>"
+	}
+	result := "component \"" + quote(label) + "\" as " + applicationAlias(app.Name) + applicationStereotypes(app) + "\n"
+	for _, service := range app.ProvidedServices {
+		if hidePlanned && service.Status == core.STATUS_PLANNED {
+			continue
+		}
+		serviceLabel := service.Name
+		if service.Type != "" {
+			serviceLabel = string(service.Type) + ":" + service.Name
+		}
+		if service.IsOpenHost {
+			serviceLabel += " ♡"
+		}
+		link := " -- "
+		stereotype := ""
+		if service.Status == core.STATUS_PLANNED {
+			link = " .. "
+			stereotype = " <<planned>>"
+		}
+		result += "interface \"" + quote(serviceLabel) + "\" as " + serviceAlias(app.Name, service.Name) + stereotype + "\n"
+		result += applicationAlias(app.Name) + link + serviceAlias(app.Name, service.Name) + "\n"
+	}
+	return result
+}
+
+func applicationStereotypes(app *core.Application) string {
+	result := ""
+	if app.Category == core.CATEGORY_EXTERNAL {
+		result += " <<external>>"
+	}
+	if app.Status == core.STATUS_PLANNED {
+		result += " <<planned>>"
+	}
+	return result
+}
+
+// applicationAlias - PlantUML aliases may only contain letters, digits and underscores
+func applicationAlias(name string) string {
+	return "app_" + aliasReplacer.ReplaceAllString(name, "_")
+}
+
+func serviceAlias(applicationName string, serviceName string) string {
+	return "svc_" + aliasReplacer.ReplaceAllString(applicationName, "_") + "__" + aliasReplacer.ReplaceAllString(serviceName, "_")
+}
+
+func clusterAlias(prefix string, name string) string {
+	return prefix + "_" + aliasReplacer.ReplaceAllString(name, "_")
+}
+
+func quote(value string) string {
+	return strings.Replace(value, "\"", "'", -1)
+}
@@ -0,0 +1,66 @@
+package plantuml
+
+import (
+	"github.com/AOEpeople/vistecture/v2/model/core"
+)
+
+type (
+	//ClusterDrawer - draws the applications clustered by team or main group and the dependencies between the clusters
+	ClusterDrawer struct {
+		project             *core.Project
+		clustering          *core.Clustering
+		summaryRelationOnly bool
+	}
+)
+
+//CreateGroupDrawer - Factory for the view of the main groups (see core.GroupClustering)
+func CreateGroupDrawer(project *core.Project, summaryRelation bool, hidePlanned bool) *ClusterDrawer {
+	return &ClusterDrawer{
+		project:             project,
+		clustering:          core.GroupClustering(hidePlanned),
+		summaryRelationOnly: summaryRelation,
+	}
+}
+
+//CreateTeamDependencyDrawer - Factory for the view of the teams (see core.TeamClustering)
+func CreateTeamDependencyDrawer(project *core.Project, summaryRelation bool, hidePlanned bool) *ClusterDrawer {
+	return &ClusterDrawer{
+		project:             project,
+		clustering:          core.TeamClustering(hidePlanned),
+		summaryRelationOnly: summaryRelation,
+	}
+}
+
+//DrawComplete - draws a rectangle per cluster with its applications. The dependencies are drawn between the applications or - with summaryRelation - once per pair of clusters with the strongest relationship
+func (d *ClusterDrawer) DrawComplete() string {
+	graph := d.project.Graph()
+	clusters := d.clustering.Clusters(graph)
+	prefix := d.clustering.Prefix
+
+	result := startUml(d.project.Name)
+	for _, cluster := range clusters {
+		result += "rectangle \"" + quote(cluster.Name) + "\" as " + clusterAlias(prefix, cluster.Name) + " " + cluster.Color + " {\n"
+		for _, application := range cluster.Applications {
+			result += "  component \"" + quote(application.Name) + "\" as " + applicationAlias(application.Name) + applicationStereotypes(application) + "\n"
+		}
+		result += "}\n"
+	}
+
+	for _, relation := range d.clustering.Relations(graph) {
+		color := clusters.Color(relation.FromCluster)
+		if d.summaryRelationOnly {
+			relationship := relation.StrongestRelationship()
+			result += clusterAlias(prefix, relation.FromCluster) + " " + arrow(core.Dependency{Relationship: relationship}, color) + " " + clusterAlias(prefix, relation.ToCluster) + label(relationship) + "\n"
+			continue
+		}
+		drawn := make(map[[2]string]bool)
+		for _, edge := range relation.Edges {
+			if drawn[[2]string{edge.From.Name, edge.To.Name}] {
+				continue
+			}
+			drawn[[2]string{edge.From.Name, edge.To.Name}] = true
+			result += applicationAlias(edge.From.Name) + " " + arrow(edge.Dependency, color) + " " + applicationAlias(edge.To.Name) + label(edge.EffectiveRelationship()) + "\n"
+		}
+	}
+	return result + "@enduml\n"
+}
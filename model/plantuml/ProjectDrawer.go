@@ -0,0 +1,169 @@
+package plantuml
+
+import (
+	"strings"
+
+	"github.com/AOEpeople/vistecture/v2/model/core"
+)
+
+type (
+	//ProjectDrawer - draws the applications of a project as PlantUML component diagram (same views as graphviz.ProjectDrawer)
+	ProjectDrawer struct {
+		project *core.Project
+		graph   *core.Graph
+	}
+)
+
+const header = `skinparam componentStyle uml2
+skinparam shadowing false
+skinparam component {
+  BackgroundColor #FFFFFF
+  BorderColor #1B4E5E
+  BackgroundColor<<external>> #F6E0E0
+  BorderColor<<external>> #8E0909
+  BackgroundColor<<planned>> #F5F5F5
+  BorderColor<<planned>> #BBBBBB
+  FontColor<<planned>> #888888
+}
+skinparam interface {
+  BackgroundColor #A3C7D4
+  BackgroundColor<<planned>> #BBBBBB
+}
+skinparam rectangle {
+  BorderColor #1B4E5E
+}
+`
+
+//CreateProjectDrawer - Factory
+func CreateProjectDrawer(project *core.Project) *ProjectDrawer {
+	return &ProjectDrawer{
+		project: project,
+		graph:   project.Graph(),
+	}
+}
+
+//DrawComplete - draws all applications as components in (nested) packages per group and all dependencies
+func (d *ProjectDrawer) DrawComplete(hidePlanned bool) string {
+	result := startUml(d.project.Name)
+	result += d.drawGroup(d.project.GetApplicationsRootGroup(), hidePlanned, "")
+	for _, app := range d.project.Applications {
+		if hidePlanned && app.Status == core.STATUS_PLANNED {
+			continue
+		}
+		for _, edge := range d.graph.OutgoingEdges(app.Name) {
+			if hidePlanned && edge.IsPlanned() {
+				continue
+			}
+			result += d.drawEdge(edge, hidePlanned)
+		}
+	}
+	return result + "@enduml\n"
+}
+
+//DrawComponent - draws the application with its direct dependencies, direct dependents and infrastructure dependencies
+func (d *ProjectDrawer) DrawComponent(application *core.Application, hidePlanned bool) string {
+	result := startUml(application.Name)
+	result += ApplicationDrawer{application: application}.Draw(hidePlanned)
+
+	drawn := map[string]bool{application.Name: true}
+	edges := append([]*core.GraphEdge(nil), d.graph.OutgoingEdges(application.Name)...)
+	for _, dependent := range d.graph.Dependents(application.Name) {
+		edges = append(edges, d.graph.EdgesBetween(dependent.Name, application.Name)...)
+	}
+	for _, edge := range edges {
+		if edge.To == nil || (hidePlanned && edge.IsPlanned()) {
+			continue
+		}
+		for _, related := range []*core.Application{edge.From, edge.To} {
+			if !drawn[related.Name] {
+				drawn[related.Name] = true
+				result += ApplicationDrawer{application: related}.Draw(hidePlanned)
+			}
+		}
+		result += d.drawEdge(edge, hidePlanned)
+	}
+
+	for _, infrastructureDependency := range application.InfrastructureDependencies {
+		alias := clusterAlias("infra", infrastructureDependency.Type)
+		result += "database \"" + quote(infrastructureDependency.Type) + "\" as " + alias + "\n"
+		result += applicationAlias(application.Name) + " -- " + alias + "\n"
+	}
+	return result + "@enduml\n"
+}
+
+// drawGroup - draws the applications of the group and recursive its subgroups as nested packages
+func (d *ProjectDrawer) drawGroup(appsByGroup *core.ApplicationsByGroup, hidePlanned bool, indent string) string {
+	result := ""
+	childIndent := indent
+	if !appsByGroup.IsRoot {
+		result += indent + "package \"" + quote(appsByGroup.GroupName) + "\" as " + clusterAlias("group", appsByGroup.QualifiedGroupName) + " {\n"
+		childIndent = indent + "  "
+	}
+	for _, subGroup := range appsByGroup.SubGroups {
+		result += d.drawGroup(subGroup, hidePlanned, childIndent)
+	}
+	for _, app := range appsByGroup.Applications {
+		if hidePlanned && app.Status == core.STATUS_PLANNED {
+			continue
+		}
+		for _, line := range strings.Split(strings.TrimRight(ApplicationDrawer{application: app}.Draw(hidePlanned), "\n"), "\n") {
+			result += childIndent + line + "\n"
+		}
+	}
+	if !appsByGroup.IsRoot {
+		result += indent + "}\n"
+	}
+	return result
+}
+
+// drawEdge - draws the dependency to the referenced service (if it is drawn) or application. Dependencies to unknown applications are skipped
+func (d *ProjectDrawer) drawEdge(edge *core.GraphEdge, hidePlanned bool) string {
+	if edge.To == nil {
+		return ""
+	}
+	target := applicationAlias(edge.To.Name)
+	if edge.TargetService != "" {
+		if service, err := edge.To.FindService(edge.TargetService); err == nil && !(hidePlanned && service.Status == core.STATUS_PLANNED) {
+			target = serviceAlias(edge.To.Name, edge.TargetService)
+		}
+	}
+	return applicationAlias(edge.From.Name) + " " + arrow(edge.Dependency, edge.From.Display.BorderColor) + " " + target + label(edge.Dependency.Relationship) + "\n"
+}
+
+func startUml(title string) string {
+	result := "@startuml\n"
+	if title != "" {
+		result += "title " + title + "\n"
+	}
+	return result + header
+}
+
+// arrow - planned dependencies are dotted, browser based dependencies dashed and strong relationships bold
+func arrow(dependency core.Dependency, color string) string {
+	var styles []string
+	if color != "" {
+		styles = append(styles, color)
+	}
+	if dependency.Status == core.STATUS_PLANNED {
+		styles = append(styles, "dotted")
+	} else if dependency.IsBrowserBased {
+		styles = append(styles, "dashed")
+	}
+	if dependency.Relationship.Weight() > 1 {
+		styles = append(styles, "bold")
+	}
+	if len(styles) == 0 {
+		return "-->"
+	}
+	return "-[" + strings.Join(styles, ",") + "]->"
+}
+
+func label(relationship core.Relationship) string {
+	switch relationship {
+	case "":
+		return ""
+	case core.RELATIONSHIP_ACL:
+		return " : <color:red><b>acl</b></color>"
+	}
+	return " : " + string(relationship)
+}
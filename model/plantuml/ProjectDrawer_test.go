@@ -0,0 +1,106 @@
+package plantuml
+
+import (
+	"strings"
+	"testing"
+
+	"github.com/AOEpeople/vistecture/v2/model/core"
+)
+
+func testProject() *core.Project {
+	return &core.Project{
+		Name: "Project1",
+		Applications: []*core.Application{
+			{
+				Name:  "app1",
+				Group: "shop/frontend",
+				Team:  "team1",
+				Dependencies: []core.Dependency{
+					{Reference: "app2.api", Relationship: core.RELATIONSHIP_CUSTOMER_SUPPLIER},
+					{Reference: "app3", Relationship: core.RELATIONSHIP_ACL},
+					{Reference: "app4", Status: core.STATUS_PLANNED},
+				},
+			},
+			{
+				Name:             "app2",
+				Group:            "shop",
+				Team:             "team2",
+				ProvidedServices: []core.Service{{Name: "api", Type: core.SERVICE_TYPE_API}},
+			},
+			{
+				Name:     "app3",
+				Team:     "team2",
+				Category: core.CATEGORY_EXTERNAL,
+			},
+			{
+				Name:   "app4",
+				Group:  "shop",
+				Status: core.STATUS_PLANNED,
+			},
+		},
+	}
+}
+
+func TestProjectDrawer_DrawComplete(t *testing.T) {
+	drawer := CreateProjectDrawer(testProject())
+
+	graph := drawer.DrawComplete(false)
+	for _, expected := range []string{
+		"@startuml",
+		"package \"shop\" as group_shop {",
+		"  package \"frontend\" as group_shop_frontend {",
+		"interface \"api:api\" as svc_app2__api",
+		"component \"app3\" as app_app3 <<external>>",
+		"component \"app4\" as app_app4 <<planned>>",
+		"app_app1 -[bold]-> svc_app2__api : customer-supplier",
+		"app_app1 --> app_app3 : <color:red><b>acl</b></color>",
+		"app_app1 -[dotted]-> app_app4",
+		"@enduml",
+	} {
+		if !strings.Contains(graph, expected) {
+			t.Errorf("Expected %q in diagram:\n%v", expected, graph)
+		}
+	}
+
+	graph = drawer.DrawComplete(true)
+	if strings.Contains(graph, "app_app4") {
+		t.Errorf("Expected planned app4 to be hidden:\n%v", graph)
+	}
+}
+
+func TestProjectDrawer_DrawComponent(t *testing.T) {
+	project := testProject()
+	drawer := CreateProjectDrawer(project)
+
+	graph := drawer.DrawComponent(project.Applications[1], false)
+	if !strings.Contains(graph, "component \"app1\" as app_app1") || !strings.Contains(graph, "app_app1 -[bold]-> svc_app2__api") {
+		t.Errorf("Expected the dependent app1 in diagram:\n%v", graph)
+	}
+	if strings.Contains(graph, "app_app3") {
+		t.Errorf("Expected only direct neighbours in diagram:\n%v", graph)
+	}
+}
+
+func TestClusterDrawer_DrawComplete(t *testing.T) {
+	graph := CreateTeamDependencyDrawer(testProject(), true, false).DrawComplete()
+	if !strings.Contains(graph, "rectangle \"team1\" as team_team1 #9013a0 {") {
+		t.Errorf("Expected rectangle for team1:\n%v", graph)
+	}
+	if !strings.Contains(graph, "team_team1 -[#9013a0,bold]-> team_team2 : customer-supplier") {
+		t.Errorf("Expected one relation with the strongest relationship:\n%v", graph)
+	}
+	if strings.Contains(graph, "app_app4") {
+		t.Errorf("Expected applications without team to be skipped:\n%v", graph)
+	}
+
+	graph = CreateGroupDrawer(testProject(), false, true).DrawComplete()
+	if !strings.Contains(graph, "rectangle \"UNGROUPED\" as group_UNGROUPED") {
+		t.Errorf("Expected rectangle for applications without group:\n%v", graph)
+	}
+	if !strings.Contains(graph, "app_app1 -[#2936c4]-> app_app3 : <color:red><b>acl</b></color>") {
+		t.Errorf("Expected relation between applications:\n%v", graph)
+	}
+	if strings.Contains(graph, "app_app4") {
+		t.Errorf("Expected planned app4 to be hidden:\n%v", graph)
+	}
+}
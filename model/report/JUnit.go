@@ -0,0 +1,116 @@
+package report
+
+import (
+	"encoding/xml"
+	"fmt"
+	"io"
+	"strings"
+
+	"github.com/AOEpeople/vistecture/v2/model/analyze"
+	"github.com/AOEpeople/vistecture/v2/model/core"
+)
+
+type (
+	junitTestSuites struct {
+		XMLName xml.Name         `xml:"testsuites"`
+		Suites  []junitTestSuite `xml:"testsuite"`
+	}
+
+	junitTestSuite struct {
+		Name      string          `xml:"name,attr"`
+		Tests     int             `xml:"tests,attr"`
+		Failures  int             `xml:"failures,attr"`
+		Errors    int             `xml:"errors,attr"`
+		TestCases []junitTestCase `xml:"testcase"`
+	}
+
+	junitTestCase struct {
+		Name      string          `xml:"name,attr"`
+		ClassName string          `xml:"classname,attr"`
+		Failures  []junitFailure  `xml:"failure,omitempty"`
+		SystemOut *junitSystemOut `xml:"system-out,omitempty"`
+	}
+
+	junitFailure struct {
+		Message string `xml:"message,attr"`
+		Type    string `xml:"type,attr"`
+		Content string `xml:",chardata"`
+	}
+
+	junitSystemOut struct {
+		Content string `xml:",chardata"`
+	}
+)
+
+// writeJUnit - every checked application is a test case. Findings with error severity are failures, warnings and infos are added to the output of the test case
+func (r *Report) writeJUnit(w io.Writer) error {
+	suite := junitTestSuite{Name: r.Tool}
+	testCaseIndex := make(map[string]int)
+	addTestCase := func(name string, className string) int {
+		if i, found := testCaseIndex[name]; found {
+			return i
+		}
+		suite.TestCases = append(suite.TestCases, junitTestCase{Name: name, ClassName: className})
+		testCaseIndex[name] = len(suite.TestCases) - 1
+		return len(suite.TestCases) - 1
+	}
+
+	for _, app := range r.applications {
+		addTestCase(app.Name, junitClassName(app.Source.File))
+	}
+	for _, finding := range r.Findings {
+		name := finding.Application
+		if name == "" {
+			name = finding.RuleId
+		}
+		testCase := &suite.TestCases[addTestCase(name, junitClassName(finding.File))]
+		if finding.Severity != analyze.SEVERITY_ERROR {
+			if testCase.SystemOut == nil {
+				testCase.SystemOut = &junitSystemOut{}
+			}
+			testCase.SystemOut.Content += finding.String() + "\n"
+			continue
+		}
+		testCase.Failures = append(testCase.Failures, junitFailure{
+			Message: finding.Message,
+			Type:    finding.RuleId,
+			Content: findingDetails(finding),
+		})
+		suite.Failures++
+	}
+	suite.Tests = len(suite.TestCases)
+
+	if _, err := io.WriteString(w, xml.Header); err != nil {
+		return err
+	}
+	encoder := xml.NewEncoder(w)
+	encoder.Indent("", "  ")
+	if err := encoder.Encode(junitTestSuites{Suites: []junitTestSuite{suite}}); err != nil {
+		return err
+	}
+	_, err := io.WriteString(w, "\n")
+	return err
+}
+
+func junitClassName(file string) string {
+	if file == "" {
+		return "project"
+	}
+	return file
+}
+
+func findingDetails(finding *Finding) string {
+	var details []string
+	for _, detail := range [][2]string{
+		{"rule", finding.RuleId},
+		{"application", finding.Application},
+		{"service", finding.Service},
+		{"dependency", finding.Dependency},
+		{"file", core.SourcePosition{File: finding.File, Line: finding.Line}.String()},
+	} {
+		if detail[1] != "" {
+			details = append(details, fmt.Sprintf("%v: %v", detail[0], detail[1]))
+		}
+	}
+	return strings.Join(details, "\n")
+}
@@ -0,0 +1,169 @@
+package report
+
+import (
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+	"sort"
+
+	"github.com/AOEpeople/vistecture/v2/model/analyze"
+	"github.com/AOEpeople/vistecture/v2/model/core"
+)
+
+type (
+	//Report - machine readable result of the validate and analyze commands
+	Report struct {
+		Tool     string     `json:"tool"`
+		Valid    bool       `json:"valid"`
+		Findings []*Finding `json:"findings"`
+		//applications - the checked applications (used for the passed test cases in JUnit)
+		applications []*core.Application
+	}
+
+	//Finding - one problem with the location it refers to
+	Finding struct {
+		RuleId      string `json:"ruleId"`
+		Severity    string `json:"severity"`
+		Message     string `json:"message"`
+		Application string `json:"application,omitempty"`
+		Service     string `json:"service,omitempty"`
+		Dependency  string `json:"dependency,omitempty"`
+		File        string `json:"file,omitempty"`
+		Line        int    `json:"line,omitempty"`
+	}
+)
+
+const (
+	FORMAT_TEXT  = "text"
+	FORMAT_JSON  = "json"
+	FORMAT_JUNIT = "junit"
+	FORMAT_SARIF = "sarif"
+
+	//RULE_GENERIC - rule id for errors without further information
+	RULE_GENERIC = "vistecture"
+)
+
+//IsSupportedFormat - true if Write supports the format
+func IsSupportedFormat(format string) bool {
+	return format == FORMAT_TEXT || format == FORMAT_JSON || format == FORMAT_JUNIT || format == FORMAT_SARIF
+}
+
+//CreateReport - converts the errors found by loader, validation and analyzer into findings. The project (may be nil) is used to look up the definition files of the applications
+func CreateReport(tool string, errs []error, project *core.Project) *Report {
+	r := &Report{Tool: tool, Findings: []*Finding{}}
+	if project != nil {
+		r.applications = project.Applications
+	}
+	for _, err := range errs {
+		r.Findings = append(r.Findings, newFinding(err, project))
+	}
+	r.Valid = !r.HasErrors()
+	return r
+}
+
+//HasErrors - true if at least one finding has error severity
+func (r *Report) HasErrors() bool {
+	for _, finding := range r.Findings {
+		if finding.Severity == analyze.SEVERITY_ERROR {
+			return true
+		}
+	}
+	return false
+}
+
+//Write - writes the report in the given format
+func (r *Report) Write(w io.Writer, format string) error {
+	switch format {
+	case FORMAT_TEXT, "":
+		return r.writeText(w)
+	case FORMAT_JSON:
+		encoder := json.NewEncoder(w)
+		encoder.SetIndent("", "  ")
+		return encoder.Encode(r)
+	case FORMAT_JUNIT:
+		return r.writeJUnit(w)
+	case FORMAT_SARIF:
+		return r.writeSarif(w)
+	}
+	return errors.New(fmt.Sprintf("Unknown output format '%v' - use %v, %v, %v or %v", format, FORMAT_TEXT, FORMAT_JSON, FORMAT_JUNIT, FORMAT_SARIF))
+}
+
+//String - human readable representation of the finding
+func (f *Finding) String() string {
+	location := core.SourcePosition{File: f.File, Line: f.Line}.String()
+	if location == "" {
+		location = f.Application
+	}
+	if location == "" {
+		return fmt.Sprintf("[%v] %v: %v", f.Severity, f.RuleId, f.Message)
+	}
+	return fmt.Sprintf("[%v] %v: %v (%v)", f.Severity, f.RuleId, f.Message, location)
+}
+
+func (r *Report) writeText(w io.Writer) error {
+	for _, finding := range r.Findings {
+		if _, err := fmt.Fprintln(w, finding.String()); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+// ruleIds - the distinct rule ids of all findings (sorted)
+func (r *Report) ruleIds() []string {
+	var ids []string
+	seen := make(map[string]bool)
+	for _, finding := range r.Findings {
+		if !seen[finding.RuleId] {
+			seen[finding.RuleId] = true
+			ids = append(ids, finding.RuleId)
+		}
+	}
+	sort.Strings(ids)
+	return ids
+}
+
+func newFinding(err error, project *core.Project) *Finding {
+	finding := &Finding{
+		RuleId:   RULE_GENERIC,
+		Severity: analyze.SEVERITY_ERROR,
+		Message:  err.Error(),
+	}
+	switch typedErr := err.(type) {
+	case *core.ValidationError:
+		finding.RuleId = typedErr.CheckId
+		finding.Message = typedErr.Message
+		finding.Application = typedErr.Application
+		finding.Service = typedErr.Service
+		finding.Dependency = typedErr.Dependency
+		finding.setSource(typedErr.Source)
+	case *analyze.RuleViolation:
+		finding.RuleId = typedErr.RuleId
+		finding.Severity = typedErr.Severity
+		finding.Message = typedErr.Message
+		finding.Application = typedErr.Application
+		finding.Service = typedErr.Service
+		finding.Dependency = typedErr.Dependency
+		finding.setSource(typedErr.Source)
+	case *analyze.CyclicDependency:
+		finding.RuleId = analyze.CHECK_CYCLIC_DEPENDENCY
+		if len(typedErr.Steps) > 0 && len(typedErr.Steps[0].Dependencies) > 0 {
+			finding.Application = typedErr.Steps[0].Application
+			finding.Service = typedErr.Steps[0].Dependencies[0].SourceService
+			finding.Dependency = typedErr.Steps[0].Dependencies[0].Reference
+			finding.setSource(typedErr.Steps[0].Dependencies[0].Source)
+		}
+	}
+	if finding.File == "" && finding.Application != "" && project != nil {
+		if app, err := project.FindApplication(finding.Application); err == nil {
+			finding.setSource(app.Source)
+		}
+	}
+	return finding
+}
+
+func (f *Finding) setSource(source core.SourcePosition) {
+	f.File = source.File
+	f.Line = source.Line
+}
@@ -0,0 +1,86 @@
+package report
+
+import (
+	"bytes"
+	"encoding/json"
+	"encoding/xml"
+	"errors"
+	"testing"
+
+	"github.com/AOEpeople/vistecture/v2/model/analyze"
+	"github.com/AOEpeople/vistecture/v2/model/core"
+)
+
+func TestReport_Write(t *testing.T) {
+
+	project := &core.Project{
+		Name: "Project1",
+		Applications: []*core.Application{
+			{Name: "app1", Source: core.SourcePosition{File: "apps/app1.yml", Line: 1}},
+			{Name: "app2", Source: core.SourcePosition{File: "apps/app2.yml", Line: 1}},
+		},
+	}
+	errs := []error{
+		&core.ValidationError{CheckId: core.CHECK_MISSING_DEPENDENCY, Application: "app1", Dependency: "app3", Message: "app3 missing"},
+		&analyze.RuleViolation{RuleId: "team-rule", Severity: analyze.SEVERITY_WARNING, Application: "app2", Message: "warning"},
+		errors.New("something else"),
+	}
+	r := CreateReport("test", errs, project)
+	if r.Valid || !r.HasErrors() {
+		t.Error("Expected report with errors")
+	}
+	if r.Findings[0].File != "apps/app1.yml" || r.Findings[2].RuleId != RULE_GENERIC {
+		t.Error("Unexpected findings", r.Findings[0], r.Findings[2])
+	}
+
+	buf := new(bytes.Buffer)
+	if err := r.Write(buf, FORMAT_JUNIT); err != nil {
+		t.Fatal(err)
+	}
+	var suites junitTestSuites
+	if err := xml.Unmarshal(buf.Bytes(), &suites); err != nil {
+		t.Fatal(err)
+	}
+	if suites.Suites[0].Tests != 3 || suites.Suites[0].Failures != 2 {
+		t.Error("Expected 3 test cases (2 applications and the generic error) with 2 failures", suites.Suites[0])
+	}
+
+	buf.Reset()
+	if err := r.Write(buf, FORMAT_SARIF); err != nil {
+		t.Fatal(err)
+	}
+	var sarif sarifLog
+	if err := json.Unmarshal(buf.Bytes(), &sarif); err != nil {
+		t.Fatal(err)
+	}
+	if len(sarif.Runs[0].Results) != 3 || sarif.Runs[0].Results[1].Level != "warning" {
+		t.Error("Unexpected sarif results", sarif.Runs[0].Results)
+	}
+
+	if err := r.Write(buf, "unknown"); err == nil {
+		t.Error("Expected error for unknown format")
+	}
+}
+
+func TestReport_WriteWithoutFindings(t *testing.T) {
+	r := CreateReport("test", nil, &core.Project{Name: "Project1"})
+	if !r.Valid {
+		t.Error("Expected a valid report")
+	}
+
+	buf := new(bytes.Buffer)
+	if err := r.Write(buf, FORMAT_JSON); err != nil {
+		t.Fatal(err)
+	}
+	if !bytes.Contains(buf.Bytes(), []byte(`"findings": []`)) {
+		t.Error("Expected an empty findings array", buf.String())
+	}
+
+	buf.Reset()
+	if err := r.Write(buf, FORMAT_SARIF); err != nil {
+		t.Fatal(err)
+	}
+	if !bytes.Contains(buf.Bytes(), []byte(`"rules": []`)) || !bytes.Contains(buf.Bytes(), []byte(`"results": []`)) {
+		t.Error("Expected empty rules and results arrays", buf.String())
+	}
+}
@@ -0,0 +1,131 @@
+package report
+
+import (
+	"encoding/json"
+	"io"
+
+	"github.com/AOEpeople/vistecture/v2/model/analyze"
+)
+
+// Minimal SARIF 2.1.0 structure - see https://docs.oasis-open.org/sarif/sarif/v2.1.0/sarif-v2.1.0.html
+type (
+	sarifLog struct {
+		Schema  string     `json:"$schema"`
+		Version string     `json:"version"`
+		Runs    []sarifRun `json:"runs"`
+	}
+
+	sarifRun struct {
+		Tool    sarifTool     `json:"tool"`
+		Results []sarifResult `json:"results"`
+	}
+
+	sarifTool struct {
+		Driver sarifDriver `json:"driver"`
+	}
+
+	sarifDriver struct {
+		Name           string      `json:"name"`
+		InformationUri string      `json:"informationUri"`
+		Rules          []sarifRule `json:"rules"`
+	}
+
+	sarifRule struct {
+		Id               string       `json:"id"`
+		ShortDescription sarifMessage `json:"shortDescription"`
+	}
+
+	sarifResult struct {
+		RuleId    string          `json:"ruleId"`
+		Level     string          `json:"level"`
+		Message   sarifMessage    `json:"message"`
+		Locations []sarifLocation `json:"locations,omitempty"`
+	}
+
+	sarifMessage struct {
+		Text string `json:"text"`
+	}
+
+	sarifLocation struct {
+		PhysicalLocation *sarifPhysicalLocation `json:"physicalLocation,omitempty"`
+		LogicalLocations []sarifLogicalLocation `json:"logicalLocations,omitempty"`
+	}
+
+	sarifPhysicalLocation struct {
+		ArtifactLocation sarifArtifactLocation `json:"artifactLocation"`
+		Region           *sarifRegion          `json:"region,omitempty"`
+	}
+
+	sarifRegion struct {
+		StartLine int `json:"startLine"`
+	}
+
+	sarifArtifactLocation struct {
+		Uri string `json:"uri"`
+	}
+
+	sarifLogicalLocation struct {
+		Name               string `json:"name"`
+		FullyQualifiedName string `json:"fullyQualifiedName,omitempty"`
+		Kind               string `json:"kind"`
+	}
+)
+
+func (r *Report) writeSarif(w io.Writer) error {
+	run := sarifRun{
+		Tool: sarifTool{Driver: sarifDriver{
+			Name:           "vistecture",
+			InformationUri: "https://github.com/AOEpeople/vistecture",
+			Rules:          []sarifRule{},
+		}},
+		Results: []sarifResult{},
+	}
+	for _, ruleId := range r.ruleIds() {
+		run.Tool.Driver.Rules = append(run.Tool.Driver.Rules, sarifRule{Id: ruleId, ShortDescription: sarifMessage{Text: ruleId}})
+	}
+	for _, finding := range r.Findings {
+		result := sarifResult{
+			RuleId:  finding.RuleId,
+			Level:   sarifLevel(finding.Severity),
+			Message: sarifMessage{Text: finding.Message},
+		}
+		location := sarifLocation{}
+		if finding.File != "" {
+			location.PhysicalLocation = &sarifPhysicalLocation{ArtifactLocation: sarifArtifactLocation{Uri: finding.File}}
+			if finding.Line > 0 {
+				location.PhysicalLocation.Region = &sarifRegion{StartLine: finding.Line}
+			}
+		}
+		if finding.Application != "" {
+			location.LogicalLocations = append(location.LogicalLocations, sarifLogicalLocation{Name: finding.Application, Kind: "module"})
+		}
+		if finding.Service != "" {
+			location.LogicalLocations = append(location.LogicalLocations, sarifLogicalLocation{Name: finding.Service, FullyQualifiedName: finding.Application + "." + finding.Service, Kind: "interface"})
+		}
+		if finding.Dependency != "" {
+			location.LogicalLocations = append(location.LogicalLocations, sarifLogicalLocation{Name: finding.Dependency, Kind: "dependency"})
+		}
+		if location.PhysicalLocation != nil || location.LogicalLocations != nil {
+			result.Locations = []sarifLocation{location}
+		}
+		run.Results = append(run.Results, result)
+	}
+
+	encoder := json.NewEncoder(w)
+	encoder.SetIndent("", "  ")
+	return encoder.Encode(sarifLog{
+		Schema:  "https://json.schemastore.org/sarif-2.1.0.json",
+		Version: "2.1.0",
+		Runs:    []sarifRun{run},
+	})
+}
+
+func sarifLevel(severity string) string {
+	switch severity {
+	case analyze.SEVERITY_ERROR:
+		return "error"
+	case analyze.SEVERITY_WARNING:
+		return "warning"
+	}
+	return "note"
+}
@@ -0,0 +1,343 @@
+package search
+
+import (
+	"math"
+	"sort"
+	"strings"
+	"unicode/utf8"
+
+	"github.com/AOEpeople/vistecture/v2/model/core"
+)
+
+type (
+	//Index - full-text index over the applications of a project with their provided services and dependencies
+	Index struct {
+		documents []*document
+		//postings - the documents containing a term
+		postings map[string][]int
+		//applicationCount - number of applications containing a term (for the inverse document frequency)
+		applicationCount map[string]int
+		//terms - all indexed terms sorted for the prefix search
+		terms        []string
+		applications int
+	}
+
+	// document - one indexed text: a field of an application, a provided service or a dependency
+	document struct {
+		application *core.Application
+		kind        string
+		name        string
+		field       string
+		text        string
+		weight      float64
+		tokens      []token
+		source      core.SourcePosition
+	}
+
+	//Result - an application matching the query with its matching fields (best match first)
+	Result struct {
+		Application string   `json:"application"`
+		Title       string   `json:"title,omitempty"`
+		Team        string   `json:"team,omitempty"`
+		Group       string   `json:"group,omitempty"`
+		Score       float64  `json:"score"`
+		Matches     []*Match `json:"matches"`
+	}
+
+	//Match - a field matching the query with a snippet of its text
+	Match struct {
+		//Kind - application, service or dependency
+		Kind string `json:"kind"`
+		//Name - the name of the service or the reference of the dependency - empty for fields of the application
+		Name    string `json:"name,omitempty"`
+		Field   string `json:"field"`
+		Snippet string `json:"snippet"`
+		//Highlights - the matched words in the snippet as UTF-16 offsets (the string indexes of javascript)
+		Highlights []Highlight         `json:"highlights"`
+		Source     core.SourcePosition `json:"source"`
+		score      float64
+		//byteHighlights - the matched words in the snippet as byte offsets
+		byteHighlights []Highlight
+	}
+
+	//Highlight - offsets of a matched word in the snippet, the end is exclusive
+	Highlight struct {
+		Start int `json:"start"`
+		End   int `json:"end"`
+	}
+)
+
+const (
+	KIND_APPLICATION = "application"
+	KIND_SERVICE     = "service"
+	KIND_DEPENDENCY  = "dependency"
+
+	//SNIPPET_LENGTH - maximal length of a snippet in bytes (without the ellipses)
+	SNIPPET_LENGTH = 120
+	//PREFIX_MATCH_FACTOR - words that only start with a query word count less than exact matches
+	PREFIX_MATCH_FACTOR = 0.5
+	//MIN_PREFIX_LENGTH - shorter query words only match exactly
+	MIN_PREFIX_LENGTH = 3
+)
+
+//fieldWeights - matches in names count more than matches in descriptions. Services and dependencies count half
+var fieldWeights = map[string]float64{
+	"name":        10,
+	"title":       6,
+	"team":        4,
+	"group":       4,
+	"technology":  4,
+	"summary":     3,
+	"properties":  2,
+	"description": 1,
+}
+
+var kindWeights = map[string]float64{
+	KIND_APPLICATION: 1,
+	KIND_SERVICE:     0.5,
+	KIND_DEPENDENCY:  0.5,
+}
+
+//CreateIndex - Factory that indexes name, title, summary, description, team, group, technology and properties of the applications and the descriptions of their services and dependencies
+func CreateIndex(project *core.Project) *Index {
+	index := &Index{postings: make(map[string][]int), applicationCount: make(map[string]int), applications: len(project.Applications)}
+	for _, application := range project.Applications {
+		for _, field := range []struct{ name, text string }{
+			{"name", application.Name},
+			{"title", application.Title},
+			{"summary", application.Summary},
+			{"description", application.Description},
+			{"team", application.Team},
+			{"group", application.Group},
+			{"technology", application.Technology},
+		} {
+			index.add(application, KIND_APPLICATION, "", field.name, field.text, application.Source)
+		}
+		var keys []string
+		for key := range application.Properties {
+			keys = append(keys, key)
+		}
+		sort.Strings(keys)
+		for _, key := range keys {
+			index.add(application, KIND_APPLICATION, "", "properties."+key, key+": "+application.Properties[key], application.Source)
+		}
+		for _, service := range application.ProvidedServices {
+			index.add(application, KIND_SERVICE, service.Name, "name", service.Name, service.Source)
+			index.add(application, KIND_SERVICE, service.Name, "title", service.Title, service.Source)
+			index.add(application, KIND_SERVICE, service.Name, "summary", service.Summary, service.Source)
+			index.add(application, KIND_SERVICE, service.Name, "description", service.Description, service.Source)
+		}
+		for _, dependency := range application.GetAllDependencies() {
+			index.add(application, KIND_DEPENDENCY, dependency.Reference, "description", dependency.Description, dependency.Source)
+		}
+	}
+	for term := range index.postings {
+		index.terms = append(index.terms, term)
+	}
+	sort.Strings(index.terms)
+	return index
+}
+
+func (index *Index) add(application *core.Application, kind string, name string, field string, text string, source core.SourcePosition) {
+	text = collapseWhitespace(text)
+	tokens := tokenize(text)
+	if len(tokens) == 0 {
+		return
+	}
+	fieldWeight := fieldWeights[strings.SplitN(field, ".", 2)[0]]
+	doc := &document{application: application, kind: kind, name: name, field: field, text: text, weight: fieldWeight * kindWeights[kind], tokens: tokens, source: source}
+	index.documents = append(index.documents, doc)
+	docId := len(index.documents) - 1
+	for _, t := range tokens {
+		postings := index.postings[t.term]
+		if len(postings) > 0 && postings[len(postings)-1] == docId {
+			continue
+		}
+		if len(postings) == 0 || index.documents[postings[len(postings)-1]].application != application {
+			index.applicationCount[t.term]++
+		}
+		index.postings[t.term] = append(postings, docId)
+	}
+}
+
+//Search - returns the applications matching at least one word of the query, the best first (limit <= 0 returns all).
+// A word matches the same word (plural insensitive) and with a lower score words starting with it. Applications matching all words rank higher
+func (index *Index) Search(query string, limit int) []*Result {
+	var queryTerms []string
+	for _, t := range tokenize(query) {
+		if !inStringSlice(t.term, queryTerms) {
+			queryTerms = append(queryTerms, t.term)
+		}
+	}
+	// score of every query term in every document and the matched terms of the documents
+	scores := make(map[int][]float64)
+	matchedTerms := make(map[int]map[string]bool)
+	for i, queryTerm := range queryTerms {
+		for term, factor := range index.matchingTerms(queryTerm) {
+			idf := math.Log(1 + float64(index.applications)/float64(index.applicationCount[term]))
+			for _, docId := range index.postings[term] {
+				if scores[docId] == nil {
+					scores[docId] = make([]float64, len(queryTerms))
+					matchedTerms[docId] = make(map[string]bool)
+				}
+				scores[docId][i] = math.Max(scores[docId][i], index.documents[docId].weight*factor*idf)
+				matchedTerms[docId][term] = true
+			}
+		}
+	}
+
+	var results []*Result
+	byApplication := make(map[*core.Application]*Result)
+	coverage := make(map[*Result][]bool)
+	for docId := 0; docId < len(index.documents); docId++ {
+		if scores[docId] == nil {
+			continue
+		}
+		doc := index.documents[docId]
+		result, found := byApplication[doc.application]
+		if !found {
+			result = &Result{Application: doc.application.Name, Title: doc.application.Title, Team: doc.application.Team, Group: doc.application.Group}
+			byApplication[doc.application] = result
+			coverage[result] = make([]bool, len(queryTerms))
+			results = append(results, result)
+		}
+		match := doc.match(matchedTerms[docId])
+		for i, score := range scores[docId] {
+			match.score += score
+			coverage[result][i] = coverage[result][i] || score > 0
+		}
+		result.Score += match.score
+		result.Matches = append(result.Matches, match)
+	}
+	for _, result := range results {
+		covered := 0
+		for _, isCovered := range coverage[result] {
+			if isCovered {
+				covered++
+			}
+		}
+		ratio := float64(covered) / float64(len(queryTerms))
+		result.Score = math.Round(result.Score*ratio*ratio*100) / 100
+		sort.SliceStable(result.Matches, func(i, j int) bool {
+			return result.Matches[i].score > result.Matches[j].score
+		})
+	}
+	sort.SliceStable(results, func(i, j int) bool {
+		if results[i].Score != results[j].Score {
+			return results[i].Score > results[j].Score
+		}
+		return results[i].Application < results[j].Application
+	})
+	if limit > 0 && len(results) > limit {
+		results = results[:limit]
+	}
+	return results
+}
+
+// matchingTerms - the indexed terms matching the query term with their factor (1 for the term itself, PREFIX_MATCH_FACTOR for longer terms starting with it)
+func (index *Index) matchingTerms(queryTerm string) map[string]float64 {
+	matching := make(map[string]float64)
+	if _, found := index.postings[queryTerm]; found {
+		matching[queryTerm] = 1
+	}
+	if len(queryTerm) < MIN_PREFIX_LENGTH {
+		return matching
+	}
+	for i := sort.SearchStrings(index.terms, queryTerm); i < len(index.terms) && strings.HasPrefix(index.terms[i], queryTerm); i++ {
+		if index.terms[i] != queryTerm {
+			matching[index.terms[i]] = PREFIX_MATCH_FACTOR
+		}
+	}
+	return matching
+}
+
+// match - the match of the document with a snippet around the first matched word
+func (doc *document) match(matchedTerms map[string]bool) *Match {
+	var matched []token
+	for _, t := range doc.tokens {
+		if matchedTerms[t.term] {
+			matched = append(matched, t)
+		}
+	}
+	start, end := 0, len(doc.text)
+	if len(doc.text) > SNIPPET_LENGTH {
+		start = matched[0].start - SNIPPET_LENGTH/3
+		if start < 0 {
+			start = 0
+		}
+		end = start + SNIPPET_LENGTH
+		if end > len(doc.text) {
+			end = len(doc.text)
+			start = end - SNIPPET_LENGTH
+		}
+		//cut at word boundaries
+		if start > 0 {
+			if space := strings.Index(doc.text[start:matched[0].start], " "); space >= 0 {
+				start += space + 1
+			}
+		}
+		if end < len(doc.text) && matched[0].end < end {
+			if space := strings.LastIndex(doc.text[matched[0].end:end], " "); space >= 0 {
+				end = matched[0].end + space
+			}
+		}
+		for start > 0 && !utf8.RuneStart(doc.text[start]) {
+			start++
+		}
+		for end < len(doc.text) && !utf8.RuneStart(doc.text[end]) {
+			end--
+		}
+	}
+	prefix, suffix := "", ""
+	if start > 0 {
+		prefix = "…"
+	}
+	if end < len(doc.text) {
+		suffix = "…"
+	}
+	match := &Match{Kind: doc.kind, Name: doc.name, Field: doc.field, Snippet: prefix + doc.text[start:end] + suffix, Highlights: []Highlight{}, Source: doc.source}
+	for _, t := range matched {
+		if t.start >= start && t.end <= end {
+			highlight := Highlight{Start: t.start - start + len(prefix), End: t.end - start + len(prefix)}
+			match.byteHighlights = append(match.byteHighlights, highlight)
+			match.Highlights = append(match.Highlights, Highlight{Start: utf16Length(match.Snippet[:highlight.Start]), End: utf16Length(match.Snippet[:highlight.End])})
+		}
+	}
+	return match
+}
+
+// utf16Length - the length of the text in UTF-16 code units
+func utf16Length(text string) int {
+	length := 0
+	for _, r := range text {
+		//runes outside the basic multilingual plane are surrogate pairs
+		if r > 0xFFFF {
+			length += 2
+		} else {
+			length++
+		}
+	}
+	return length
+}
+
+//Highlighted - returns the snippet with the matched words enclosed in open and close (e.g. "<mark>" and "</mark>")
+func (m *Match) Highlighted(open string, close string) string {
+	var b strings.Builder
+	last := 0
+	for _, highlight := range m.byteHighlights {
+		b.WriteString(m.Snippet[last:highlight.Start])
+		b.WriteString(open + m.Snippet[highlight.Start:highlight.End] + close)
+		last = highlight.End
+	}
+	b.WriteString(m.Snippet[last:])
+	return b.String()
+}
+
+func inStringSlice(search string, in []string) bool {
+	for _, v := range in {
+		if v == search {
+			return true
+		}
+	}
+	return false
+}
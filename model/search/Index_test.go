@@ -0,0 +1,109 @@
+package search
+
+import (
+	"strings"
+	"testing"
+	"unicode/utf16"
+
+	"github.com/AOEpeople/vistecture/v2/model/core"
+)
+
+func testProject() *core.Project {
+	return &core.Project{
+		Applications: []*core.Application{
+			{
+				Name:        "billing",
+				Title:       "Billing",
+				Team:        "finance",
+				Summary:     "Creates invoices and sends them to the customers",
+				Description: "The billing service collects all orders of a month.\n\nAt the end of the month it creates the invoices, stores them as PDF in the archive and sends them by mail to the customers of the shop.",
+				Properties:  map[string]string{"owner": "finance-team"},
+			},
+			{
+				Name:             "mailer",
+				Team:             "platform",
+				Technology:       "go",
+				ProvidedServices: []core.Service{{Name: "smtp", Description: "Sends transactional mails"}},
+			},
+			{
+				Name:         "shop",
+				Team:         "storefront",
+				Dependencies: []core.Dependency{{Reference: "billing", Description: "shows the invoice of an order"}},
+			},
+			{Name: "invoice-archive", Team: "finance"},
+		},
+	}
+}
+
+func TestIndex_Search(t *testing.T) {
+	index := CreateIndex(testProject())
+
+	results := index.Search("sends invoices", 0)
+	var names []string
+	for _, result := range results {
+		names = append(names, result.Application)
+	}
+	if strings.Join(names, ",") != "billing,invoice-archive,mailer,shop" {
+		t.Errorf("Expected billing ranked first, got %v", names)
+	}
+	if results[0].Matches[0].Field != "summary" || results[0].Matches[0].Highlighted("[", "]") != "Creates [invoices] and [sends] them to the customers" {
+		t.Errorf("Expected the summary as best match, got %#v", results[0].Matches[0])
+	}
+
+	if results := index.Search("transactional", 0); len(results) != 1 || results[0].Matches[0].Kind != KIND_SERVICE || results[0].Matches[0].Name != "smtp" {
+		t.Errorf("Expected the service of the mailer, got %#v", results)
+	}
+	if results := index.Search("fin", 1); len(results) != 1 || results[0].Matches[0].Highlighted("<", ">") != "<finance>" {
+		t.Errorf("Expected one result with prefix match, got %#v", results)
+	}
+	if results := index.Search("unknown", 0); len(results) != 0 {
+		t.Errorf("Expected no results, got %#v", results)
+	}
+}
+
+func TestIndex_Snippet(t *testing.T) {
+	var description *Match
+	for _, result := range CreateIndex(testProject()).Search("archive pdf", 0) {
+		for _, match := range result.Matches {
+			if result.Application == "billing" && match.Field == "description" {
+				description = match
+			}
+		}
+	}
+	if description == nil {
+		t.Fatal("Expected a match in the description of billing")
+	}
+	expected := "…month it creates the invoices, stores them as <PDF> in the <archive> and sends them by mail to the customers of the shop."
+	if highlighted := description.Highlighted("<", ">"); highlighted != expected {
+		t.Errorf("Expected snippet %q, got %q", expected, highlighted)
+	}
+	//the highlights are UTF-16 offsets - the ellipsis is one unit, not three bytes
+	snippet := utf16.Encode([]rune(description.Snippet))
+	if len(description.Highlights) != 2 {
+		t.Fatalf("Expected 2 highlights, got %v", description.Highlights)
+	}
+	for i, word := range []string{"PDF", "archive"} {
+		highlight := description.Highlights[i]
+		if highlighted := string(utf16.Decode(snippet[highlight.Start:highlight.End])); highlighted != word {
+			t.Errorf("Expected highlight %v to be %q, got %q", i, word, highlighted)
+		}
+	}
+}
+
+func TestUtf16Length(t *testing.T) {
+	for text, expected := range map[string]int{"": 0, "invoice": 7, "…": 1, "Größe": 5, "📄 pdf": 6} {
+		if length := utf16Length(text); length != expected {
+			t.Errorf("Expected length %v of %q, got %v", expected, text, length)
+		}
+	}
+}
+
+func TestTokenize(t *testing.T) {
+	var terms []string
+	for _, token := range tokenize("Order-Workflow: sends Invoices, queries status") {
+		terms = append(terms, token.term)
+	}
+	if strings.Join(terms, " ") != "order workflow send invoice query status" {
+		t.Errorf("Unexpected terms %v", terms)
+	}
+}
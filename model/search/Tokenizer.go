@@ -0,0 +1,52 @@
+package search
+
+import (
+	"strings"
+	"unicode"
+)
+
+type (
+	// token - a normalized term and its position (byte offsets) in the text
+	token struct {
+		term  string
+		start int
+		end   int
+	}
+)
+
+// tokenize - splits the text into words (letters and digits). Terms are lower case and stemmed
+func tokenize(text string) []token {
+	var tokens []token
+	start := -1
+	for i, r := range text {
+		isWordRune := unicode.IsLetter(r) || unicode.IsDigit(r)
+		if isWordRune && start < 0 {
+			start = i
+		}
+		if !isWordRune && start >= 0 {
+			tokens = append(tokens, token{term: normalize(text[start:i]), start: start, end: i})
+			start = -1
+		}
+	}
+	if start >= 0 {
+		tokens = append(tokens, token{term: normalize(text[start:]), start: start, end: len(text)})
+	}
+	return tokens
+}
+
+// normalize - lower case and a simple plural stemming, so that "invoices" matches "invoice"
+func normalize(word string) string {
+	term := strings.ToLower(word)
+	switch {
+	case len(term) > 4 && strings.HasSuffix(term, "ies"):
+		return term[:len(term)-3] + "y"
+	case len(term) > 3 && strings.HasSuffix(term, "s") && !strings.HasSuffix(term, "ss") && !strings.HasSuffix(term, "us"):
+		return term[:len(term)-1]
+	}
+	return term
+}
+
+// collapseWhitespace - line breaks and indentation of descriptions are not relevant for snippets
+func collapseWhitespace(text string) string {
+	return strings.Join(strings.Fields(text), " ")
+}
@@ -0,0 +1,306 @@
+package structurizr
+
+import (
+	"io"
+	"regexp"
+	"sort"
+	"strings"
+
+	"github.com/AOEpeople/vistecture/v2/model/core"
+)
+
+type (
+	//WorkspaceWriter - writes a project as Structurizr DSL workspace (C4 model).
+	//The project is the software system, internal applications are its containers (grouped by team) and the provided services are components.
+	//External applications become external software systems and infrastructure dependencies containers of the project system.
+	WorkspaceWriter struct {
+		project *core.Project
+		graph   *core.Graph
+		views   []*View
+	}
+
+	//View - additional container view with a subset of the applications (e.g. a subview of the project config)
+	View struct {
+		Key          string
+		Title        string
+		Applications []*core.Application
+	}
+
+	//dslWriter - indents the written lines by the current block depth
+	dslWriter struct {
+		out   string
+		depth int
+	}
+)
+
+const (
+	TAG_EXTERNAL      = "External"
+	TAG_PLANNED       = "Planned"
+	TAG_DATABASE      = "Database"
+	TAG_BROWSER_BASED = "Browser based"
+	TAG_ACL           = "ACL"
+)
+
+// projectIdentifier - application identifiers are prefixed so they can not clash with it
+const projectIdentifier = "project"
+
+var identifierReplacer = regexp.MustCompile(`[^A-Za-z0-9_]`)
+
+//CreateWorkspaceWriter - Factory
+func CreateWorkspaceWriter(project *core.Project, views []*View) *WorkspaceWriter {
+	return &WorkspaceWriter{
+		project: project,
+		graph:   project.Graph(),
+		views:   views,
+	}
+}
+
+//Write - writes the workspace with the model, the default views, the additional views and the styles
+func (w *WorkspaceWriter) Write(out io.Writer) error {
+	dsl := &dslWriter{}
+	dsl.open("workspace " + quote(w.project.Name) + " " + quote("Generated by vistecture"))
+	w.writeModel(dsl)
+	w.writeViews(dsl)
+	dsl.close()
+	_, err := io.WriteString(out, dsl.out)
+	return err
+}
+
+func (w *WorkspaceWriter) writeModel(dsl *dslWriter) {
+	dsl.open("model")
+
+	var external, internal []*core.Application
+	for _, application := range w.project.Applications {
+		if isExternal(application) {
+			external = append(external, application)
+		} else {
+			internal = append(internal, application)
+		}
+	}
+
+	for _, team := range teamsOf(external) {
+		w.inGroup(dsl, team, func() {
+			for _, application := range applicationsOfTeam(external, team) {
+				dsl.element(applicationIdentifier(application.Name)+" = softwareSystem "+quote(application.Name)+" "+quote(application.GetSummary()), tagIf(true, TAG_EXTERNAL)+tagIf(application.Status == core.STATUS_PLANNED, TAG_PLANNED))
+			}
+		})
+	}
+
+	dsl.open(projectIdentifier + " = softwareSystem " + quote(w.project.Name))
+	for _, team := range teamsOf(internal) {
+		w.inGroup(dsl, team, func() {
+			for _, application := range applicationsOfTeam(internal, team) {
+				w.writeContainer(dsl, application)
+			}
+		})
+	}
+	for _, application := range internal {
+		for _, infrastructureDependency := range application.InfrastructureDependencies {
+			dsl.element(infrastructureIdentifier(application.Name, infrastructureDependency.Type)+" = container "+quote(infrastructureDependency.Type+" ("+application.Name+")")+" "+quote("")+" "+quote(infrastructureDependency.Type), tagIf(true, TAG_DATABASE))
+		}
+	}
+	dsl.close()
+
+	// Structurizr rejects relationships between an element and its children and duplicated relationships
+	written := make(map[string]bool)
+	for _, application := range w.project.Applications {
+		for _, edge := range w.graph.OutgoingEdges(application.Name) {
+			if edge.To == nil || edge.To == edge.From {
+				continue
+			}
+			tags := tagIf(edge.IsPlanned(), TAG_PLANNED) + tagIf(edge.Dependency.IsBrowserBased, TAG_BROWSER_BASED) + tagIf(edge.Relationship == core.RELATIONSHIP_ACL, TAG_ACL)
+			description := string(edge.Relationship)
+			if description == "" {
+				description = "uses"
+			}
+			relationship := w.elementIdentifier(edge.From, edge.SourceService) + " -> " + w.elementIdentifier(edge.To, edge.TargetService) + " " + quote(description)
+			if written[relationship] {
+				continue
+			}
+			written[relationship] = true
+			dsl.element(relationship, tags)
+		}
+		if isExternal(application) {
+			continue
+		}
+		for _, infrastructureDependency := range application.InfrastructureDependencies {
+			dsl.line(applicationIdentifier(application.Name) + " -> " + infrastructureIdentifier(application.Name, infrastructureDependency.Type) + " " + quote("uses"))
+		}
+	}
+	dsl.close()
+}
+
+// writeContainer - writes the application as container and its provided services as components
+func (w *WorkspaceWriter) writeContainer(dsl *dslWriter, application *core.Application) {
+	declaration := applicationIdentifier(application.Name) + " = container " + quote(application.Name) + " " + quote(application.GetSummary()) + " " + quote(application.Technology)
+	if len(application.ProvidedServices) == 0 {
+		dsl.element(declaration, tagIf(application.Status == core.STATUS_PLANNED, TAG_PLANNED))
+		return
+	}
+	dsl.open(declaration)
+	if application.Status == core.STATUS_PLANNED {
+		dsl.line("tags " + quote(TAG_PLANNED))
+	}
+	for _, service := range application.ProvidedServices {
+		dsl.element(serviceIdentifier(application.Name, service.Name)+" = component "+quote(service.Name)+" "+quote(service.Summary)+" "+quote(string(service.Type)), tagIf(service.Status == core.STATUS_PLANNED, TAG_PLANNED))
+	}
+	dsl.close()
+}
+
+func (w *WorkspaceWriter) writeViews(dsl *dslWriter) {
+	dsl.open("views")
+	dsl.open("systemContext " + projectIdentifier + " " + quote("SystemContext"))
+	dsl.line("include *")
+	dsl.line("autoLayout")
+	dsl.close()
+	dsl.open("container " + projectIdentifier + " " + quote("Containers"))
+	dsl.line("include *")
+	dsl.line("autoLayout")
+	dsl.close()
+	for _, view := range w.views {
+		dsl.open("container " + projectIdentifier + " " + quote(identifierReplacer.ReplaceAllString(view.Key, "_")) + " " + quote(view.Title))
+		var included []string
+		for _, application := range view.Applications {
+			included = append(included, applicationIdentifier(application.Name))
+		}
+		if len(included) > 0 {
+			dsl.line("include " + strings.Join(included, " "))
+		}
+		dsl.line("autoLayout")
+		dsl.close()
+	}
+	for _, application := range w.project.Applications {
+		if isExternal(application) || len(application.ProvidedServices) == 0 {
+			continue
+		}
+		dsl.open("component " + applicationIdentifier(application.Name) + " " + quote("Components_"+identifierReplacer.ReplaceAllString(application.Name, "_")))
+		dsl.line("include *")
+		dsl.line("autoLayout")
+		dsl.close()
+	}
+
+	dsl.open("styles")
+	dsl.open("element " + quote(TAG_EXTERNAL))
+	dsl.line("background #999999")
+	dsl.line("color #ffffff")
+	dsl.close()
+	dsl.open("element " + quote(TAG_PLANNED))
+	dsl.line("opacity 50")
+	dsl.line("border dashed")
+	dsl.close()
+	dsl.open("element " + quote(TAG_DATABASE))
+	dsl.line("shape Cylinder")
+	dsl.close()
+	dsl.open("relationship " + quote(TAG_PLANNED))
+	dsl.line("style dotted")
+	dsl.close()
+	dsl.open("relationship " + quote(TAG_BROWSER_BASED))
+	dsl.line("style dashed")
+	dsl.close()
+	dsl.open("relationship " + quote(TAG_ACL))
+	dsl.line("color #d62a2a")
+	dsl.close()
+	dsl.close()
+	dsl.close()
+}
+
+// elementIdentifier - the component of the service (only internal applications have components) or the application
+func (w *WorkspaceWriter) elementIdentifier(application *core.Application, serviceName string) string {
+	if serviceName != "" && !isExternal(application) {
+		if _, err := application.FindService(serviceName); err == nil {
+			return serviceIdentifier(application.Name, serviceName)
+		}
+	}
+	return applicationIdentifier(application.Name)
+}
+
+// inGroup - teams become groups, applications without team are written without group
+func (w *WorkspaceWriter) inGroup(dsl *dslWriter, team string, write func()) {
+	if team == "" {
+		write()
+		return
+	}
+	dsl.open("group " + quote(team))
+	write()
+	dsl.close()
+}
+
+func (d *dslWriter) line(line string) {
+	d.out += strings.Repeat("    ", d.depth) + line + "\n"
+}
+
+func (d *dslWriter) open(line string) {
+	d.line(line + " {")
+	d.depth++
+}
+
+// element - writes the declaration and the tags (as returned by tagIf) in a block if there are any
+func (d *dslWriter) element(declaration string, tags string) {
+	if tags == "" {
+		d.line(declaration)
+		return
+	}
+	d.open(declaration)
+	d.line("tags" + tags)
+	d.close()
+}
+
+func (d *dslWriter) close() {
+	d.depth--
+	d.line("}")
+}
+
+func isExternal(application *core.Application) bool {
+	return application.Category == core.CATEGORY_EXTERNAL
+}
+
+// teamsOf - the sorted teams of the applications
+func teamsOf(applications []*core.Application) []string {
+	var teams []string
+	found := make(map[string]bool)
+	for _, application := range applications {
+		if !found[application.Team] {
+			found[application.Team] = true
+			teams = append(teams, application.Team)
+		}
+	}
+	sort.Strings(teams)
+	return teams
+}
+
+func applicationsOfTeam(applications []*core.Application, team string) []*core.Application {
+	var result []*core.Application
+	for _, application := range applications {
+		if application.Team == team {
+			result = append(result, application)
+		}
+	}
+	return result
+}
+
+func applicationIdentifier(name string) string {
+	return "app_" + identifierReplacer.ReplaceAllString(name, "_")
+}
+
+func serviceIdentifier(applicationName string, serviceName string) string {
+	return applicationIdentifier(applicationName) + "__" + identifierReplacer.ReplaceAllString(serviceName, "_")
+}
+
+func infrastructureIdentifier(applicationName string, infrastructureType string) string {
+	return "infra_" + identifierReplacer.ReplaceAllString(applicationName, "_") + "__" + identifierReplacer.ReplaceAllString(infrastructureType, "_")
+}
+
+func tagIf(condition bool, tag string) string {
+	if !condition {
+		return ""
+	}
+	return " " + quote(tag)
+}
+
+// quote - DSL strings are double quoted and must not contain line breaks
+func quote(value string) string {
+	value = strings.Replace(value, "\\", "\\\\", -1)
+	value = strings.Replace(value, "\"", "\\\"", -1)
+	value = strings.Replace(value, "\n", " ", -1)
+	return "\"" + strings.TrimSpace(value) + "\""
+}
@@ -0,0 +1,80 @@
+package structurizr
+
+import (
+	"bytes"
+	"strings"
+	"testing"
+
+	"github.com/AOEpeople/vistecture/v2/model/core"
+)
+
+func TestWorkspaceWriter_Write(t *testing.T) {
+	project := &core.Project{
+		Name: "Shop",
+		Applications: []*core.Application{
+			{
+				Name:       "checkout",
+				Team:       "team1",
+				Summary:    "Checkout \"v2\"",
+				Technology: "go",
+				ProvidedServices: []core.Service{
+					{Name: "api", Type: core.SERVICE_TYPE_API},
+				},
+				InfrastructureDependencies: []core.InfrastructureDependency{{Type: "rdbms"}},
+				Dependencies: []core.Dependency{
+					{Reference: "payment.auth", Relationship: core.RELATIONSHIP_ACL},
+					{Reference: "payment.capture", Relationship: core.RELATIONSHIP_ACL},
+					{Reference: "checkout.api"},
+				},
+			},
+			{
+				Name: "cart",
+				Dependencies: []core.Dependency{
+					{Reference: "checkout.api", Status: core.STATUS_PLANNED},
+				},
+			},
+			{
+				Name:     "payment",
+				Category: core.CATEGORY_EXTERNAL,
+				ProvidedServices: []core.Service{
+					{Name: "auth"},
+				},
+			},
+		},
+	}
+
+	var out bytes.Buffer
+	views := []*View{{Key: "Checkout view", Title: "Checkout view", Applications: project.Applications[0:1]}}
+	if err := CreateWorkspaceWriter(project, views).Write(&out); err != nil {
+		t.Fatal(err)
+	}
+	dsl := out.String()
+
+	for _, expected := range []string{
+		"workspace \"Shop\" \"Generated by vistecture\" {\n",
+		"        app_payment = softwareSystem \"payment\" \"\" {\n            tags \"External\"\n",
+		"        project = softwareSystem \"Shop\" {\n            app_cart = container \"cart\" \"\" \"\"\n            group \"team1\" {\n",
+		"app_checkout = container \"checkout\" \"Checkout \\\"v2\\\"\" \"go\" {\n",
+		"app_checkout__api = component \"api\" \"\" \"api\"\n",
+		"infra_checkout__rdbms = container \"rdbms (checkout)\" \"\" \"rdbms\" {\n",
+		"        app_checkout -> app_payment \"acl\" {\n            tags \"ACL\"\n",
+		"        app_cart -> app_checkout__api \"uses\" {\n            tags \"Planned\"\n",
+		"        app_checkout -> infra_checkout__rdbms \"uses\"\n",
+		"        container project \"Checkout_view\" \"Checkout view\" {\n            include app_checkout\n",
+		"        component app_checkout \"Components_checkout\" {\n",
+	} {
+		if !strings.Contains(dsl, expected) {
+			t.Errorf("Expected %q in workspace:\n%v", expected, dsl)
+		}
+	}
+
+	if strings.Count(dsl, "app_checkout -> app_payment") != 1 {
+		t.Errorf("Expected relationships to be written once:\n%v", dsl)
+	}
+	if strings.Contains(dsl, "app_checkout -> app_checkout__api") {
+		t.Errorf("Expected no relationship to own component:\n%v", dsl)
+	}
+	if strings.Contains(dsl, "component app_payment") {
+		t.Errorf("Expected no component view for external systems:\n%v", dsl)
+	}
+}
@@ -5,13 +5,17 @@ import (
 	"log"
 	"net/http"
 	"os"
-	"path"
+	"strings"
 	"time"
 
 	"github.com/AOEpeople/vistecture/v2/application"
 	"github.com/AOEpeople/vistecture/v2/controller"
 	"github.com/AOEpeople/vistecture/v2/controller/web"
+	"github.com/AOEpeople/vistecture/v2/model/analyze"
 	"github.com/AOEpeople/vistecture/v2/model/core"
+	"github.com/AOEpeople/vistecture/v2/model/diff"
+	"github.com/AOEpeople/vistecture/v2/model/drift"
+	"github.com/AOEpeople/vistecture/v2/model/report"
 	"github.com/gorilla/mux"
 	"github.com/urfave/cli"
 )
@@ -25,11 +29,14 @@ type (
 var (
 	//global cli flags
 	projectConfigFile, projectSubViewName string
-	skipValidation                        bool
+	skipValidation, schemaValidation      bool
+	validateOutput, schemaType            string
 	//server cli flags
 	serverPort            int
 	localTemplateFolder   string
 	staticDocumentsFolder string
+	watchInterval         time.Duration
+	serverIconPath        string
 )
 
 func actionFunc(lazyProjectInjectAble projectInjectAble, cb func()) func(c *cli.Context) error {
@@ -42,7 +49,11 @@ func actionFunc(lazyProjectInjectAble projectInjectAble, cb func()) func(c *cli.
 }
 
 func main() {
-	var componentName, templatePath, iconPath, summaryRelation, hidePlanned string
+	var componentName, templatePath, iconPath, summaryRelation, hidePlanned, output, view, markdown, mappingProperty string
+	var skipOptional, skipPlanned, failOnUnobserved bool
+	var outputPath, baseConfigFile, headConfigFile string
+	var fromApplication, toApplication string
+	var maxLength, depth, upstreamDepth, downstreamDepth, limit int
 
 	app := cli.NewApp()
 	app.Name = "vistecture tool "
@@ -67,16 +78,46 @@ func main() {
 			Usage:       "Skip the validation of the project",
 			Destination: &skipValidation,
 		},
+		cli.BoolFlag{
+			Name:        "validateSchema",
+			Usage:       "Validate the definition files against the JSON schema (see schema command)",
+			Destination: &schemaValidation,
+		},
 	}
 
 	analyzeController := &controller.AnalyzeController{}
 	documentationController := &controller.DocumentationController{}
+	exportController := &controller.ExportController{}
+	driftController := &controller.DriftController{}
+	diffController := &controller.DiffController{}
+	searchController := &controller.SearchController{}
 
 	app.Commands = []cli.Command{
 		{
 			Name:   "validate",
 			Usage:  "Validates project JSON",
 			Action: validate,
+			Flags: []cli.Flag{
+				cli.StringFlag{
+					Name:        "output",
+					Value:       report.FORMAT_TEXT,
+					Usage:       "Output format of the validation result: text, json, junit or sarif",
+					Destination: &validateOutput,
+				},
+			},
+		},
+		{
+			Name:   "schema",
+			Usage:  "Prints the JSON schema of the application definition files or the project config - use it for autocompletion and validation in your editor",
+			Action: printSchema,
+			Flags: []cli.Flag{
+				cli.StringFlag{
+					Name:        "type",
+					Value:       application.SCHEMA_APPLICATION,
+					Usage:       "Schema to print: application or project",
+					Destination: &schemaType,
+				},
+			},
 		},
 		{
 			Name:   "list",
@@ -84,9 +125,169 @@ func main() {
 			Action: listApps,
 		},
 		{
-			Name:   "analyze",
-			Usage:  "Analyses project structure. Detects cyclic dependencies etc",
-			Action: actionFunc(analyzeController, analyzeController.AnalyzeAction),
+			Name:  "analyze",
+			Usage: "Analyses project structure. Detects cyclic dependencies etc",
+			Action: func(c *cli.Context) error {
+				project, loadErrors := loadProjectForReport(projectConfigFile, projectSubViewName, skipValidation)
+				analyzeController.Inject(project)
+				analyzeController.InjectLoadErrors(loadErrors)
+				analyzeController.InjectRules(loadRules(projectConfigFile))
+				analyzeController.AnalyzeAction(componentName, output, skipOptional, skipPlanned)
+				return nil
+			},
+			Flags: []cli.Flag{
+				cli.StringFlag{
+					Name:        "application",
+					Value:       "",
+					Usage:       "Name of a application - then only the impact of this application is analyzed",
+					Destination: &componentName,
+				},
+				cli.StringFlag{
+					Name:        "output",
+					Value:       "table",
+					Usage:       "Output format: table, json, csv (impact analysis) or junit, sarif (findings only)",
+					Destination: &output,
+				},
+				cli.BoolFlag{
+					Name:        "skipOptional",
+					Usage:       "Ignore optional dependencies in the cycle detection, the rules and the impact analysis",
+					Destination: &skipOptional,
+				},
+				cli.BoolFlag{
+					Name:        "skipPlanned",
+					Usage:       "Ignore planned applications and dependencies in the cycle detection, the rules and the impact analysis",
+					Destination: &skipPlanned,
+				},
+			},
+		},
+		{
+			Name:      "drift",
+			Usage:     "Compares the declared dependencies with the calls observed in Jaeger, Zipkin or OpenTelemetry JSON trace exports or CSV files (caller,callee,count)",
+			ArgsUsage: "<trace export or folder> ...",
+			Action: func(c *cli.Context) error {
+				driftController.Inject(loadProject(projectConfigFile, projectSubViewName, skipValidation))
+				driftController.DriftAction(c.Args(), mappingProperty, output, failOnUnobserved)
+				return nil
+			},
+			Flags: []cli.Flag{
+				cli.StringFlag{
+					Name:        "property",
+					Value:       drift.DEFAULT_MAPPING_PROPERTY,
+					Usage:       "Application property with the service name(s) used in the traces - applications without it are mapped by name",
+					Destination: &mappingProperty,
+				},
+				cli.StringFlag{
+					Name:        "output",
+					Value:       "table",
+					Usage:       "Output format: table or json",
+					Destination: &output,
+				},
+				cli.BoolFlag{
+					Name:        "failOnUnobserved",
+					Usage:       "Exit with a non-zero code also if declared dependencies are not observed (by default only undeclared calls fail)",
+					Destination: &failOnUnobserved,
+				},
+			},
+		},
+		{
+			Name:  "diff",
+			Usage: "Shows the changed applications, services, dependencies, team couplings and cycles between two versions of a project. \n go run main.go diff --base main/project.yml --head project.yml --output markdown",
+			Action: func(c *cli.Context) error {
+				if baseConfigFile == "" || headConfigFile == "" {
+					log.Fatal("Project config of base and head missing")
+				}
+				base := loadProject(baseConfigFile, projectSubViewName, skipValidation)
+				head := loadProject(headConfigFile, projectSubViewName, skipValidation)
+				diffController.DiffAction(base, head, output)
+				return nil
+			},
+			Flags: []cli.Flag{
+				cli.StringFlag{
+					Name:        "base",
+					Value:       "",
+					Usage:       "Path to the project config of the base version",
+					Destination: &baseConfigFile,
+				},
+				cli.StringFlag{
+					Name:        "head",
+					Value:       "",
+					Usage:       "Path to the project config of the changed version",
+					Destination: &headConfigFile,
+				},
+				cli.StringFlag{
+					Name:        "output",
+					Value:       diff.FORMAT_TEXT,
+					Usage:       "Output format: text, markdown, json or dot",
+					Destination: &output,
+				},
+			},
+		},
+		{
+			Name:  "path",
+			Usage: "Lists the shortest path and all paths from one application to another with the used services and relationships. \n go run main.go path --from customer-portal --to paymentprovider --output dot | dot -Tpng -o path.png",
+			Action: actionFunc(analyzeController, func() {
+				analyzeController.PathAction(fromApplication, toApplication, maxLength, output, skipOptional, skipPlanned)
+			}),
+			Flags: []cli.Flag{
+				cli.StringFlag{
+					Name:        "from",
+					Value:       "",
+					Usage:       "Name of the application the paths start at",
+					Destination: &fromApplication,
+				},
+				cli.StringFlag{
+					Name:        "to",
+					Value:       "",
+					Usage:       "Name of the application the paths lead to",
+					Destination: &toApplication,
+				},
+				cli.IntFlag{
+					Name:        "maxLength",
+					Value:       analyze.DEFAULT_MAX_PATH_LENGTH,
+					Usage:       "Maximum number of hops of the listed paths",
+					Destination: &maxLength,
+				},
+				cli.StringFlag{
+					Name:        "output",
+					Value:       controller.OUTPUT_TEXT,
+					Usage:       "Output format: text, json or dot",
+					Destination: &output,
+				},
+				cli.BoolFlag{
+					Name:        "skipOptional",
+					Usage:       "Ignore optional dependencies",
+					Destination: &skipOptional,
+				},
+				cli.BoolFlag{
+					Name:        "skipPlanned",
+					Usage:       "Ignore planned applications and dependencies",
+					Destination: &skipPlanned,
+				},
+			},
+		},
+		{
+			Name:      "search",
+			Usage:     "Full-text search over name, title, summary, description, team, group, technology and properties of the applications and the descriptions of services and dependencies",
+			ArgsUsage: "<query>",
+			Action: func(c *cli.Context) error {
+				searchController.Inject(loadProject(projectConfigFile, projectSubViewName, skipValidation))
+				searchController.SearchAction(strings.Join(c.Args(), " "), limit, output)
+				return nil
+			},
+			Flags: []cli.Flag{
+				cli.IntFlag{
+					Name:        "limit",
+					Value:       10,
+					Usage:       "Maximum number of listed applications (0 for all)",
+					Destination: &limit,
+				},
+				cli.StringFlag{
+					Name:        "output",
+					Value:       controller.OUTPUT_TEXT,
+					Usage:       "Output format: text or json",
+					Destination: &output,
+				},
+			},
 		},
 		{
 			Name:   "documentation",
@@ -108,9 +309,11 @@ func main() {
 			},
 		},
 		{
-			Name:   "graph",
-			Usage:  "Build graphviz format which can be used by dot or any other graphviz command. \n go run main.go graph | dot -Tpng -o graph.png \n See: http://www.graphviz.org/pdf/twopi.1.pdf",
-			Action: actionFunc(documentationController, func() { documentationController.GraphvizAction(componentName, iconPath, hidePlanned, skipValidation) }),
+			Name:  "graph",
+			Usage: "Build graphviz format which can be used by dot or any other graphviz command. \n go run main.go graph | dot -Tpng -o graph.png \n See: http://www.graphviz.org/pdf/twopi.1.pdf",
+			Action: actionFunc(documentationController, func() {
+				documentationController.GraphvizAction(componentName, iconPath, hidePlanned, skipValidation, depth, upstreamDepth, downstreamDepth)
+			}),
 			Flags: []cli.Flag{
 				cli.StringFlag{
 					Name:        "application",
@@ -130,6 +333,24 @@ func main() {
 					Usage:       "Flag if planned applications should be drawn or not",
 					Destination: &hidePlanned,
 				},
+				cli.IntFlag{
+					Name:        "depth",
+					Value:       0,
+					Usage:       "Number of hops around the application that are drawn (with all edges among them) - only with --application",
+					Destination: &depth,
+				},
+				cli.IntFlag{
+					Name:        "upstream",
+					Value:       -1,
+					Usage:       "Number of hops of applications using the application - defaults to --depth",
+					Destination: &upstreamDepth,
+				},
+				cli.IntFlag{
+					Name:        "downstream",
+					Value:       -1,
+					Usage:       "Number of hops of applications used by the application - defaults to --depth",
+					Destination: &downstreamDepth,
+				},
 			},
 		},
 		{
@@ -158,6 +379,171 @@ func main() {
 				},
 			},
 		},
+		{
+			Name:   "plantuml",
+			Usage:  "Build a PlantUML component diagram. \n go run main.go plantuml --view group | plantuml -pipe > graph.png",
+			Action: actionFunc(documentationController, func() { documentationController.PlantUMLAction(view, componentName, hidePlanned, summaryRelation) }),
+			Flags: []cli.Flag{
+				cli.StringFlag{
+					Name:        "view",
+					Value:       "complete",
+					Usage:       "The view to draw: complete, application, group or team",
+					Destination: &view,
+				},
+				cli.StringFlag{
+					Name:        "application",
+					Value:       "",
+					Usage:       "Name of the application for the application view",
+					Destination: &componentName,
+				},
+				cli.StringFlag{
+					Name:        "hidePlanned",
+					Value:       "",
+					Usage:       "Flag if planned applications, services and dependencies should be drawn or not",
+					Destination: &hidePlanned,
+				},
+				cli.StringFlag{
+					Name:        "summaryRelation",
+					Value:       "",
+					Usage:       "if set then only one arrow is drawn between the groups or teams",
+					Destination: &summaryRelation,
+				},
+			},
+		},
+		{
+			Name:  "mermaid",
+			Usage: "Build a Mermaid flowchart that can be embedded in markdown. \n go run main.go mermaid --view group --markdown 1 >> architecture.md",
+			Action: actionFunc(documentationController, func() {
+				documentationController.MermaidAction(view, componentName, hidePlanned, summaryRelation, markdown)
+			}),
+			Flags: []cli.Flag{
+				cli.StringFlag{
+					Name:        "view",
+					Value:       "complete",
+					Usage:       "The view to draw: complete, application, group or team",
+					Destination: &view,
+				},
+				cli.StringFlag{
+					Name:        "application",
+					Value:       "",
+					Usage:       "Name of the application for the application view",
+					Destination: &componentName,
+				},
+				cli.StringFlag{
+					Name:        "hidePlanned",
+					Value:       "",
+					Usage:       "Flag if planned applications and dependencies should be drawn or not",
+					Destination: &hidePlanned,
+				},
+				cli.StringFlag{
+					Name:        "summaryRelation",
+					Value:       "",
+					Usage:       "if set then only one arrow is drawn between the groups or teams",
+					Destination: &summaryRelation,
+				},
+				cli.StringFlag{
+					Name:        "markdown",
+					Value:       "",
+					Usage:       "if set then the flowchart is wrapped in a mermaid code block",
+					Destination: &markdown,
+				},
+			},
+		},
+		{
+			Name:  "structurizr",
+			Usage: "Export the project as Structurizr DSL workspace (C4 model). \n go run main.go structurizr > workspace.dsl",
+			Action: actionFunc(documentationController, func() {
+				documentationController.StructurizrAction(loadProjectConfig(projectConfigFile).SubViewConfig)
+			}),
+		},
+		{
+			Name:  "export",
+			Usage: "Export the project to the inventory of other tools",
+			Subcommands: []cli.Command{
+				{
+					Name:   "backstage",
+					Usage:  "Write Backstage catalog entities (catalog-info.yaml): applications become Components, services APIs, teams Groups and groups Systems",
+					Action: actionFunc(exportController, func() { exportController.BackstageAction(outputPath) }),
+					Flags: []cli.Flag{
+						cli.StringFlag{
+							Name:        "output",
+							Value:       "",
+							Usage:       "File to write the entities to (default stdout)",
+							Destination: &outputPath,
+						},
+					},
+				},
+			},
+		},
+		{
+			Name:  "import",
+			Usage: "Create application definitions from the inventory of other tools",
+			Subcommands: []cli.Command{
+				{
+					Name:      "backstage",
+					Usage:     "Import the Components of Backstage catalog files as application definitions",
+					ArgsUsage: "<catalog file or folder>",
+					Action: func(c *cli.Context) error {
+						importPath := c.Args().First()
+						if importPath == "" {
+							log.Fatal("Path of the catalog file or folder missing")
+						}
+						controller.CreateImportController(outputPath).BackstageAction(importPath)
+						return nil
+					},
+					Flags: []cli.Flag{
+						cli.StringFlag{
+							Name:        "output",
+							Value:       ".",
+							Usage:       "Folder to write the application definitions to (existing definitions are merged)",
+							Destination: &outputPath,
+						},
+					},
+				},
+				{
+					Name:      "k8s",
+					Usage:     "Import Deployments, StatefulSets and CronJobs with their Services and Ingresses from Kubernetes manifests as application definitions",
+					ArgsUsage: "<manifest file or folder>",
+					Action: func(c *cli.Context) error {
+						importPath := c.Args().First()
+						if importPath == "" {
+							log.Fatal("Path of the manifest file or folder missing")
+						}
+						controller.CreateImportController(outputPath).KubernetesAction(importPath)
+						return nil
+					},
+					Flags: []cli.Flag{
+						cli.StringFlag{
+							Name:        "output",
+							Value:       ".",
+							Usage:       "Folder to write the application definitions to (existing definitions are merged)",
+							Destination: &outputPath,
+						},
+					},
+				},
+				{
+					Name:      "compose",
+					Usage:     "Import the services of a docker-compose file as application definitions",
+					ArgsUsage: "<compose file>",
+					Action: func(c *cli.Context) error {
+						importPath := c.Args().First()
+						if importPath == "" {
+							log.Fatal("Path of the compose file missing")
+						}
+						controller.CreateImportController(outputPath).ComposeAction(importPath)
+						return nil
+					},
+					Flags: []cli.Flag{
+						cli.StringFlag{
+							Name:        "output",
+							Value:       ".",
+							Usage:       "Folder to write the application definitions to (existing definitions are merged)",
+							Destination: &outputPath,
+						},
+					},
+				},
+			},
+		},
 		{
 			Name:   "serve",
 			Usage:  "Runs the vistecture webserver",
@@ -181,6 +567,18 @@ func main() {
 					Usage:       "if set then this  folder will be scanned for files that are linked in the mainmenu then",
 					Destination: &staticDocumentsFolder,
 				},
+				cli.DurationFlag{
+					Name:        "watchInterval",
+					Value:       application.DEFAULT_WATCH_INTERVAL,
+					Usage:       "how often the definitions are checked for changes to reload the project (0 disables watching)",
+					Destination: &watchInterval,
+				},
+				cli.StringFlag{
+					Name:        "iconPath",
+					Value:       "templates/icons",
+					Usage:       "Path of icons that will be in drawing components of the rendered graphs",
+					Destination: &serverIconPath,
+				},
 			},
 		},
 	}
@@ -189,7 +587,7 @@ func main() {
 }
 
 func loadProject(configFile string, subViewName string, skipValidation bool) *core.Project {
-	loader := application.ProjectLoader{StrictMode: !skipValidation}
+	loader := application.ProjectLoader{StrictMode: !skipValidation, SchemaValidation: schemaValidation}
 	project, err := loader.LoadProjectFromConfigFile(configFile, subViewName)
 
 	if err != nil {
@@ -201,21 +599,63 @@ func loadProject(configFile string, subViewName string, skipValidation bool) *co
 	return project
 }
 
-func validate(_ *cli.Context) error {
-	loader := application.ProjectLoader{StrictMode: !skipValidation}
-	project, err := loader.LoadProjectFromConfigFile(projectConfigFile, projectSubViewName)
+//loadProjectForReport - like loadProject, but in strict mode the loading errors are returned instead of aborting, so that they can be reported (the project is nil if the config cannot be loaded)
+func loadProjectForReport(configFile string, subViewName string, skipValidation bool) (*core.Project, []error) {
+	loader := application.ProjectLoader{StrictMode: !skipValidation, SchemaValidation: schemaValidation}
+	project, err := loader.LoadProjectFromConfigFile(configFile, subViewName)
+	if skipValidation {
+		if err != nil {
+			log.Println(err)
+		}
+		return project, nil
+	}
+	return project, application.AllErrors(err)
+}
+
+//loadRules - the architecture rules of the project config. Errors of the config are not reported here - loadProjectForReport returns them
+func loadRules(configFile string) []*analyze.Rule {
+	loader := application.ProjectLoader{StrictMode: !skipValidation, SchemaValidation: schemaValidation}
+	projectConfig, _ := loader.LoadProjectConfig(configFile)
+	if projectConfig == nil {
+		return nil
+	}
+	return projectConfig.Rules
+}
 
+func loadProjectConfig(configFile string) *application.ProjectConfig {
+	loader := application.ProjectLoader{StrictMode: !skipValidation, SchemaValidation: schemaValidation}
+	projectConfig, err := loader.LoadProjectConfig(configFile)
 	if err != nil {
-		log.Println(err)
+		log.Fatal(err)
+	}
+	return projectConfig
+}
+
+func validate(_ *cli.Context) error {
+	if !report.IsSupportedFormat(validateOutput) {
+		log.Fatalf("Unknown output format '%v' - use %v, %v, %v or %v", validateOutput, report.FORMAT_TEXT, report.FORMAT_JSON, report.FORMAT_JUNIT, report.FORMAT_SARIF)
 	}
-	var validationErrors []error
+	loader := application.ProjectLoader{StrictMode: !skipValidation, SchemaValidation: schemaValidation}
+	project, err := loader.LoadProjectFromConfigFile(projectConfigFile, projectSubViewName)
+
+	validationErrors := application.AllErrors(err)
 	if project != nil {
-		validationErrors = project.Validate()
-		for _, valErr := range validationErrors {
-			log.Println(valErr)
+		validationErrors = append(validationErrors, project.Validate()...)
+	}
+	if validateOutput != report.FORMAT_TEXT {
+		findings := report.CreateReport("vistecture validate", validationErrors, project)
+		if err := findings.Write(os.Stdout, validateOutput); err != nil {
+			log.Fatal(err)
 		}
+		if findings.HasErrors() {
+			os.Exit(1)
+		}
+		return nil
+	}
+	for _, valErr := range validationErrors {
+		log.Println(valErr)
 	}
-	if err != nil || len(validationErrors) > 0 {
+	if len(validationErrors) > 0 {
 		log.Fatal("Not valid")
 	} else {
 		log.Println("valid")
@@ -223,6 +663,22 @@ func validate(_ *cli.Context) error {
 	return nil
 }
 
+func printSchema(_ *cli.Context) error {
+	//with a project config the project specific vocabulary is part of the schema
+	var vocabulary *core.Vocabulary
+	if projectConfigFile != "" {
+		vocabulary = loadProjectConfig(projectConfigFile).GetVocabulary()
+	}
+	schema := application.SchemaByName(schemaType, vocabulary)
+	if schema == nil {
+		log.Fatalf("Unknown schema type '%v' - use %v or %v", schemaType, application.SCHEMA_APPLICATION, application.SCHEMA_PROJECT)
+	}
+	if err := schema.Write(os.Stdout); err != nil {
+		log.Fatal(err)
+	}
+	return nil
+}
+
 func listApps(_ *cli.Context) error {
 	project := loadProject(projectConfigFile, projectSubViewName, true)
 	for _, app := range project.Applications {
@@ -234,30 +690,66 @@ func listApps(_ *cli.Context) error {
 func startServer(_ *cli.Context) error {
 	r := mux.NewRouter()
 
+	//there is no write timeout for the event stream - all other routes are registered in the timed router
 	srv := &http.Server{
-		Handler:      r,
-		Addr:         fmt.Sprintf(":%v", serverPort),
-		WriteTimeout: 15 * time.Second,
-		ReadTimeout:  15 * time.Second,
+		Handler:     r,
+		Addr:        fmt.Sprintf(":%v", serverPort),
+		ReadTimeout: 15 * time.Second,
 	}
 
 	webProjectController := web.ProjectController{}
 	loader := application.ProjectLoader{}
-	definitions, err := loader.LoadProjectConfig(projectConfigFile)
+	projectModel, err := application.CreateProjectModel(&loader, projectConfigFile)
 	if err != nil {
 		log.Fatal(err)
 		return nil
 	}
-	webProjectController.Inject(definitions, &loader, path.Dir(projectConfigFile), skipValidation)
+	webProjectController.Inject(projectModel, skipValidation)
+	if watchInterval > 0 {
+		go projectModel.Watch(watchInterval, application.DEFAULT_WATCH_DEBOUNCE, make(chan struct{}))
+	}
+
+	r.HandleFunc("/events", webProjectController.EventsAction)
+
+	timed := r.NewRoute().Subrouter()
+	timed.Use(func(next http.Handler) http.Handler {
+		return http.TimeoutHandler(next, 15*time.Second, "Request timed out")
+	})
 
 	// This will serve files under http://localhost:8000/documents/<filename>
-	r.PathPrefix("/documents/").Handler(http.StripPrefix("/documents/", http.FileServer(http.Dir(staticDocumentsFolder))))
+	timed.PathPrefix("/documents/").Handler(http.StripPrefix("/documents/", http.FileServer(http.Dir(staticDocumentsFolder))))
 
-	r.HandleFunc("/data", func(w http.ResponseWriter, r *http.Request) {
+	timed.HandleFunc("/data", func(w http.ResponseWriter, r *http.Request) {
 		webProjectController.DataAction(w, r, staticDocumentsFolder)
 	})
 
-	r.PathPrefix("/").HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+	timed.HandleFunc("/path", webProjectController.PathAction)
+
+	timed.HandleFunc("/status", webProjectController.StatusAction)
+
+	graphController := web.GraphController{}
+	graphController.Inject(projectModel, serverIconPath)
+	timed.HandleFunc("/graphs/{view}.{format}", graphController.GraphAction).Methods(http.MethodGet)
+
+	searchController := web.SearchController{}
+	searchController.Inject(projectModel)
+	timed.HandleFunc("/search", searchController.SearchAction).Methods(http.MethodGet)
+
+	apiController := web.ApiController{}
+	apiController.Inject(projectModel)
+	api := timed.PathPrefix("/api/v1").Subrouter()
+	api.HandleFunc("/applications", apiController.ApplicationsAction).Methods(http.MethodGet)
+	api.HandleFunc("/applications/{name}", apiController.ApplicationAction).Methods(http.MethodGet)
+	api.HandleFunc("/applications/{name}/services", apiController.ServicesAction).Methods(http.MethodGet)
+	api.HandleFunc("/teams", apiController.TeamsAction).Methods(http.MethodGet)
+	api.HandleFunc("/teams/{team}", apiController.TeamAction).Methods(http.MethodGet)
+	api.HandleFunc("/groups", apiController.GroupAction).Methods(http.MethodGet)
+	api.HandleFunc("/groups/{qualifiedName:.+}", apiController.GroupAction).Methods(http.MethodGet)
+	api.HandleFunc("/subviews", apiController.SubViewsAction).Methods(http.MethodGet)
+	api.HandleFunc("/subviews/{name}", apiController.SubViewAction).Methods(http.MethodGet)
+	api.HandleFunc("/missing", apiController.MissingAction).Methods(http.MethodGet)
+
+	timed.PathPrefix("/").HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
 		webProjectController.IndexAction(w, r, localTemplateFolder)
 	})
 